@@ -29,6 +29,12 @@ const (
 	API_DATA_REPOSITORY_LIST   = "/api/datarepository"
 	API_DATA_REPOSITORY_DELETE = "/api/datarepository"
 
+	API_SCRIPT_MODULE_LIST   = "/api/scriptmodule"
+	API_SCRIPT_MODULE_DELETE = "/api/scriptmodule"
+
+	API_SCENE_LIST   = "/api/scene"
+	API_SCENE_DELETE = "/api/scene"
+
 	API_VIRTUAL_DEVICE_LIST    = "/api/virtualdevice"
 	API_VIRTUAL_DEVICE_ENABLE  = "/api/virtualdevice/enable"
 	API_VIRTUAL_DEVICE_DISABLE = "/api/virtualdevice/disable"
@@ -45,6 +51,8 @@ const (
 	API_TASK_DISABLE = "/api/task/disable"
 	API_TASK_DELETE  = "/api/task"
 
+	API_TASK_HISTORY_LIST = "/api/taskhistory"
+
 	API_SCHEDULE_LIST    = "/api/schedule"
 	API_SCHEDULE_ENABLE  = "/api/schedule/enable"
 	API_SCHEDULE_DISABLE = "/api/schedule/disable"
@@ -60,4 +68,9 @@ const (
 
 	API_BACKUP_LIST   = "/api/backup"
 	API_BACKUP_DELETE = "/api/backup"
+
+	API_METRIC_EXPORT = "/api/metric/export"
+	API_METRIC_IMPORT = "/api/metric/import"
+
+	API_BUNDLE_APPLY = "/api/bundle/apply"
 )
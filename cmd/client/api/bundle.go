@@ -0,0 +1,22 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/mycontroller-org/server/v2/pkg/json"
+	bundleTY "github.com/mycontroller-org/server/v2/pkg/types/bundle"
+)
+
+func (c *Client) ApplyBundle(queryParams map[string]interface{}, data []byte) (*bundleTY.ApplyResult, error) {
+	headers := map[string]string{"Content-Type": "application/yaml"}
+	res, err := c.execute(API_BUNDLE_APPLY, http.MethodPost, headers, queryParams, string(data), http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	result := &bundleTY.ApplyResult{}
+	err = json.Unmarshal(res.Body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -24,6 +24,12 @@ func (c *Client) executeJson(api, httpMethod string, headers map[string]string,
 	return client.ExecuteJson(fmt.Sprintf("%s%s", c.ServerAddress, api), httpMethod, c.getHeaders(headers), queryParams, body, responseCode)
 }
 
+func (c *Client) execute(api, httpMethod string, headers map[string]string, queryParams map[string]interface{},
+	body string, responseCode int) (*httpUtils.ResponseConfig, error) {
+	client := httpUtils.New(c.Insecure, c.Timeout)
+	return client.Execute(fmt.Sprintf("%s%s", c.ServerAddress, api), httpMethod, c.getHeaders(headers), queryParams, body, responseCode)
+}
+
 func (c *Client) getHeaders(headers map[string]string) map[string]string {
 	if headers == nil {
 		headers = map[string]string{}
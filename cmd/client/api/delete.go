@@ -32,6 +32,16 @@ func (c *Client) DeleteDataRepository(nodes ...string) error {
 	return err
 }
 
+func (c *Client) DeleteScriptModule(items ...string) error {
+	_, err := c.executeJson(API_SCRIPT_MODULE_DELETE, http.MethodDelete, nil, nil, items, http.StatusOK)
+	return err
+}
+
+func (c *Client) DeleteScene(items ...string) error {
+	_, err := c.executeJson(API_SCENE_DELETE, http.MethodDelete, nil, nil, items, http.StatusOK)
+	return err
+}
+
 func (c *Client) DeleteVirtualDevice(items ...string) error {
 	_, err := c.executeJson(API_VIRTUAL_DEVICE_DELETE, http.MethodDelete, nil, nil, items, http.StatusOK)
 	return err
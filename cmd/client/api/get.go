@@ -45,6 +45,14 @@ func (c *Client) ListDataRepository(queryParams map[string]interface{}) (*storag
 	return c.listResource(API_DATA_REPOSITORY_LIST, queryParams)
 }
 
+func (c *Client) ListScriptModule(queryParams map[string]interface{}) (*storageTY.Result, error) {
+	return c.listResource(API_SCRIPT_MODULE_LIST, queryParams)
+}
+
+func (c *Client) ListScene(queryParams map[string]interface{}) (*storageTY.Result, error) {
+	return c.listResource(API_SCENE_LIST, queryParams)
+}
+
 func (c *Client) ListVirtualDevice(queryParams map[string]interface{}) (*storageTY.Result, error) {
 	return c.listResource(API_VIRTUAL_DEVICE_LIST, queryParams)
 }
@@ -57,6 +65,10 @@ func (c *Client) ListTask(queryParams map[string]interface{}) (*storageTY.Result
 	return c.listResource(API_TASK_LIST, queryParams)
 }
 
+func (c *Client) ListTaskHistory(queryParams map[string]interface{}) (*storageTY.Result, error) {
+	return c.listResource(API_TASK_HISTORY_LIST, queryParams)
+}
+
 func (c *Client) ListSchedule(queryParams map[string]interface{}) (*storageTY.Result, error) {
 	return c.listResource(API_SCHEDULE_LIST, queryParams)
 }
@@ -7,11 +7,12 @@ import (
 	handlerTY "github.com/mycontroller-org/server/v2/pkg/types/web_handler"
 )
 
-func (c *Client) Login(username, password, token, expiresIn string) (*handlerTY.JwtTokenResponse, error) {
+func (c *Client) Login(username, password, token, code, expiresIn string) (*handlerTY.JwtTokenResponse, error) {
 	req := &handlerTY.UserLogin{
 		Username:  username,
 		Password:  password,
 		SvcToken:  token,
+		Code:      code,
 		ExpiresIn: expiresIn,
 	}
 	res, err := c.executeJson(API_LOGIN, http.MethodPost, nil, nil, req, http.StatusOK)
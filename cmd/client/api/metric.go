@@ -0,0 +1,35 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/mycontroller-org/server/v2/pkg/json"
+)
+
+// ImportResult of a metric import request
+type ImportResult struct {
+	Imported int64  `json:"imported"`
+	Skipped  int64  `json:"skipped"`
+	Message  string `json:"message"`
+}
+
+func (c *Client) ExportMetric(queryParams map[string]interface{}) ([]byte, error) {
+	res, err := c.execute(API_METRIC_EXPORT, http.MethodGet, nil, queryParams, "", http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+func (c *Client) ImportMetric(queryParams map[string]interface{}, data []byte) (*ImportResult, error) {
+	res, err := c.execute(API_METRIC_IMPORT, http.MethodPost, nil, queryParams, string(data), http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	result := &ImportResult{}
+	err = json.Unmarshal(res.Body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
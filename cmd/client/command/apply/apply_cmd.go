@@ -0,0 +1,75 @@
+package apply
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	rootCmd "github.com/mycontroller-org/server/v2/cmd/client/command/root"
+	bundleTY "github.com/mycontroller-org/server/v2/pkg/types/bundle"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	file   string
+	dryRun bool
+)
+
+func runApply(cmd *cobra.Command, args []string) {
+	client := rootCmd.GetClient()
+
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(rootCmd.IOStreams.In)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		fmt.Fprintf(rootCmd.IOStreams.ErrOut, "error:%s\n", err)
+		return
+	}
+
+	queryParams := map[string]interface{}{"dryRun": dryRun}
+	result, err := client.ApplyBundle(queryParams, data)
+	if err != nil {
+		fmt.Fprintf(rootCmd.IOStreams.ErrOut, "error:%s\n", err)
+		return
+	}
+	printResult(result)
+}
+
+func printResult(result *bundleTY.ApplyResult) {
+	out := rootCmd.IOStreams.Out
+	partiallyApplied := false
+	for _, change := range result.Changes {
+		if change.Applied && !result.Applied {
+			partiallyApplied = true
+			fmt.Fprintf(out, "%s/%s %s (applied)\n", change.EntityType, change.ID, change.Action)
+		} else {
+			fmt.Fprintf(out, "%s/%s %s\n", change.EntityType, change.ID, change.Action)
+		}
+		for _, diff := range change.Diff {
+			fmt.Fprintf(out, "  %s\n", diff)
+		}
+	}
+
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(out, "warning: %s\n", warning)
+	}
+	for _, errMsg := range result.Errors {
+		fmt.Fprintf(rootCmd.IOStreams.ErrOut, "error: %s\n", errMsg)
+	}
+
+	switch {
+	case partiallyApplied:
+		fmt.Fprintln(rootCmd.IOStreams.ErrOut, "bundle partially applied, only the changes marked as applied are saved")
+	case len(result.Errors) > 0:
+		fmt.Fprintln(rootCmd.IOStreams.ErrOut, "bundle not applied, fix the errors")
+	case result.DryRun:
+		fmt.Fprintln(out, "dry run, changes not applied")
+	case result.Applied:
+		fmt.Fprintln(out, "bundle applied")
+	}
+}
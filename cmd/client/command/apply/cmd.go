@@ -0,0 +1,28 @@
+package apply
+
+import (
+	rootCmd "github.com/mycontroller-org/server/v2/cmd/client/command/root"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.Cmd.AddCommand(applyCmd)
+	applyCmd.Flags().StringVarP(&file, "file", "f", "", "bundle file, \"-\" for stdin")
+	applyCmd.Flags().BoolVar(&dryRun, "dry-run", false, "prints the changes without applying")
+	_ = applyCmd.MarkFlagRequired("file")
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Applies a bundle of tasks, schedules, handlers, data repositories and forward payloads",
+	Example: `  # preview the changes
+  myc apply -f automation.yaml --dry-run
+
+  # apply the changes
+  myc apply -f automation.yaml`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		rootCmd.UpdateStreams(cmd)
+	},
+	Run: runApply,
+}
@@ -12,6 +12,8 @@ func init() {
 	deleteCmd.AddCommand(fieldDeleteCmd)
 	deleteCmd.AddCommand(firmwareDeleteCmd)
 	deleteCmd.AddCommand(dataRepositoryDeleteCmd)
+	deleteCmd.AddCommand(scriptModuleDeleteCmd)
+	deleteCmd.AddCommand(sceneDeleteCmd)
 	deleteCmd.AddCommand(virtualDeviceDeleteCmd)
 	deleteCmd.AddCommand(virtualAssistantDeleteCmd)
 	deleteCmd.AddCommand(taskDeleteCmd)
@@ -111,6 +113,36 @@ var dataRepositoryDeleteCmd = &cobra.Command{
 	},
 }
 
+var scriptModuleDeleteCmd = &cobra.Command{
+	Use:     "script-module",
+	Aliases: []string{"script-modules", "sm"},
+	Short:   "Deletes the given script modules",
+	PreRun: func(cmd *cobra.Command, args []string) {
+		rootCmd.UpdateStreams(cmd)
+	},
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := rootCmd.GetClient()
+		err := client.DeleteScriptModule(args...)
+		printStatus(err)
+	},
+}
+
+var sceneDeleteCmd = &cobra.Command{
+	Use:     "scene",
+	Aliases: []string{"scenes"},
+	Short:   "Deletes the given scenes",
+	PreRun: func(cmd *cobra.Command, args []string) {
+		rootCmd.UpdateStreams(cmd)
+	},
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := rootCmd.GetClient()
+		err := client.DeleteScene(args...)
+		printStatus(err)
+	},
+}
+
 var virtualDeviceDeleteCmd = &cobra.Command{
 	Use:     "virtual-device",
 	Aliases: []string{"virtual-devices", "vd"},
@@ -1,6 +1,9 @@
 package get
 
 import (
+	"fmt"
+	"strings"
+
 	rootCmd "github.com/mycontroller-org/server/v2/cmd/client/command/root"
 	backupTY "github.com/mycontroller-org/server/v2/pkg/types/backup"
 	clientTY "github.com/mycontroller-org/server/v2/pkg/types/client"
@@ -9,12 +12,16 @@ import (
 	firmwareTY "github.com/mycontroller-org/server/v2/pkg/types/firmware"
 	fwPayloadTY "github.com/mycontroller-org/server/v2/pkg/types/forward_payload"
 	nodeTY "github.com/mycontroller-org/server/v2/pkg/types/node"
+	sceneTY "github.com/mycontroller-org/server/v2/pkg/types/scene"
 	scheduleTY "github.com/mycontroller-org/server/v2/pkg/types/schedule"
+	scriptModuleTY "github.com/mycontroller-org/server/v2/pkg/types/script_module"
 	sourceTY "github.com/mycontroller-org/server/v2/pkg/types/source"
 	taskTY "github.com/mycontroller-org/server/v2/pkg/types/task"
+	taskHistoryTY "github.com/mycontroller-org/server/v2/pkg/types/task_history"
 	vaTY "github.com/mycontroller-org/server/v2/pkg/types/virtual_assistant"
 	vdTY "github.com/mycontroller-org/server/v2/pkg/types/virtual_device"
 	"github.com/mycontroller-org/server/v2/pkg/utils/printer"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
 	gwTY "github.com/mycontroller-org/server/v2/plugin/gateway/types"
 	handlerTY "github.com/mycontroller-org/server/v2/plugin/handler/types"
 	"github.com/spf13/cobra"
@@ -27,9 +34,12 @@ func init() {
 	getCmd.AddCommand(fieldGetCmd)
 	getCmd.AddCommand(firmwareGetCmd)
 	getCmd.AddCommand(dataRepositoryGetCmd)
+	getCmd.AddCommand(scriptModuleGetCmd)
+	getCmd.AddCommand(sceneGetCmd)
 	getCmd.AddCommand(virtualDeviceGetCmd)
 	getCmd.AddCommand(virtualAssistantGetCmd)
 	getCmd.AddCommand(taskGetCmd)
+	getCmd.AddCommand(taskHistoryGetCmd)
 	getCmd.AddCommand(scheduleGetCmd)
 	getCmd.AddCommand(handlerGetCmd)
 	getCmd.AddCommand(forwardPayloadGetCmd)
@@ -184,6 +194,49 @@ var dataRepositoryGetCmd = &cobra.Command{
 	},
 }
 
+var scriptModuleGetCmd = &cobra.Command{
+	Use:     "script-module",
+	Aliases: []string{"script-modules", "sm"},
+	Short:   "Print the script module details",
+	PreRun: func(cmd *cobra.Command, args []string) {
+		rootCmd.UpdateStreams(cmd)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		client := rootCmd.GetClient()
+
+		headers := []printer.Header{
+			{Title: "id"},
+			{Title: "description"},
+			{Title: "labels"},
+			{Title: "modified on", ValuePath: "modifiedOn", DisplayStyle: printer.DisplayStyleRelativeTime},
+		}
+		executeGetCmd(headers, client.ListScriptModule, scriptModuleTY.Config{})
+	},
+}
+
+var sceneGetCmd = &cobra.Command{
+	Use:     "scene",
+	Aliases: []string{"scenes"},
+	Short:   "Print the scene details",
+	PreRun: func(cmd *cobra.Command, args []string) {
+		rootCmd.UpdateStreams(cmd)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		client := rootCmd.GetClient()
+
+		headers := []printer.Header{
+			{Title: "id"},
+			{Title: "description"},
+			{Title: "fields"},
+			{Title: "field labels", ValuePath: "fieldLabels", IsWide: true},
+			{Title: "snapshot on", ValuePath: "snapshotOn", DisplayStyle: printer.DisplayStyleRelativeTime},
+			{Title: "labels"},
+			{Title: "modified on", ValuePath: "modifiedOn", DisplayStyle: printer.DisplayStyleRelativeTime},
+		}
+		executeGetCmd(headers, client.ListScene, sceneTY.Config{})
+	},
+}
+
 var virtualDeviceGetCmd = &cobra.Command{
 	Use:     "virtual-device",
 	Aliases: []string{"virtual-devices", "vd"},
@@ -258,6 +311,58 @@ var taskGetCmd = &cobra.Command{
 	},
 }
 
+var taskHistoryGetCmd = &cobra.Command{
+	Use:     "task-history",
+	Aliases: []string{"task-histories", "th"},
+	Short:   "Print the execution history of the tasks and schedules",
+	Example: `  # list the latest executions of a task
+  myc get task-history --filter "source id==my-task"
+
+  # list the latest executions of the schedules
+  myc get task-history --filter "source type==schedule" --limit 20`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		rootCmd.UpdateStreams(cmd)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		client := rootCmd.GetClient()
+
+		// latest executions on the top, unless the sort options supplied
+		if !cmd.Flags().Changed("sort-by") {
+			sortBy = "timestamp"
+		}
+		if !cmd.Flags().Changed("sort-order") {
+			sortOrder = storageTY.SortByDESC
+		}
+
+		headers := []printer.Header{
+			{Title: "id", IsWide: true},
+			{Title: "source type", ValuePath: "sourceType"},
+			{Title: "source id", ValuePath: "sourceId"},
+			{Title: "event", ValuePath: "event.entityId", IsWide: true},
+			{Title: "triggered"},
+			{Title: "notified handlers", ValuePath: "notifiedHandlers"},
+			{Title: "handlers", ValueFunc: taskHistoryHandlersValueFunc},
+			{Title: "error"},
+			{Title: "duration", IsWide: true},
+			{Title: "timestamp", DisplayStyle: printer.DisplayStyleRelativeTime},
+		}
+		executeGetCmd(headers, client.ListTaskHistory, taskHistoryTY.Entry{})
+	},
+}
+
+// returns the handlers result in "handlerId:status" format
+func taskHistoryHandlersValueFunc(data interface{}) string {
+	entry, ok := data.(*taskHistoryTY.Entry)
+	if !ok {
+		return ""
+	}
+	results := make([]string, 0)
+	for _, handler := range entry.Handlers {
+		results = append(results, fmt.Sprintf("%s:%s", handler.HandlerID, handler.Status))
+	}
+	return strings.Join(results, ",")
+}
+
 var scheduleGetCmd = &cobra.Command{
 	Use:     "schedule",
 	Aliases: []string{"schedules"},
@@ -0,0 +1,20 @@
+package metric
+
+import (
+	rootCmd "github.com/mycontroller-org/server/v2/cmd/client/command/root"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.Cmd.AddCommand(metricCmd)
+}
+
+var metricCmd = &cobra.Command{
+	Use:     "metric",
+	Aliases: []string{"metrics"},
+	Short:   "Exports or imports the metric data of a field",
+	PreRun: func(cmd *cobra.Command, args []string) {
+		rootCmd.UpdateStreams(cmd)
+	},
+}
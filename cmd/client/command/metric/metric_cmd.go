@@ -0,0 +1,114 @@
+package metric
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	rootCmd "github.com/mycontroller-org/server/v2/cmd/client/command/root"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	start     string
+	stop      string
+	window    string
+	functions []string
+	format    string
+	file      string
+)
+
+func init() {
+	metricCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVar(&start, "start", "-1h", "start time. relative duration(-1h) or RFC3339")
+	exportCmd.Flags().StringVar(&stop, "stop", "", "stop time. relative duration(-1h) or RFC3339, default: now")
+	exportCmd.Flags().StringVar(&window, "window", "", "aggregation window, applicable for gauge and counter metrics")
+	exportCmd.Flags().StringSliceVar(&functions, "functions", []string{}, "aggregation functions. comma separated or repeated")
+	exportCmd.Flags().StringVar(&format, "format", "csv", "export format. options: csv, json, line_protocol")
+	exportCmd.Flags().StringVarP(&file, "file", "f", "", "write the data to the file, default: stdout")
+
+	metricCmd.AddCommand(importCmd)
+	importCmd.Flags().StringVar(&format, "format", "csv", "import format. options: csv, json, line_protocol")
+	importCmd.Flags().StringVarP(&file, "file", "f", "", "read the data from the file, \"-\" for stdin")
+	_ = importCmd.MarkFlagRequired("file")
+}
+
+var exportCmd = &cobra.Command{
+	Use:     "export",
+	Short:   "Exports the metric data of a field",
+	Example: `  myc metric export gateway1.node1.source1.V_TEMP --start -24h --window 15m --format csv --file temperature.csv`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		rootCmd.UpdateStreams(cmd)
+	},
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := rootCmd.GetClient()
+		queryParams := map[string]interface{}{
+			"quick_id": fmt.Sprintf("field:%s", args[0]),
+			"start":    start,
+			"stop":     stop,
+			"window":   window,
+			"format":   format,
+		}
+		if len(functions) > 0 {
+			queryParams["functions"] = functions
+		}
+
+		data, err := client.ExportMetric(queryParams)
+		if err != nil {
+			fmt.Fprintf(rootCmd.IOStreams.ErrOut, "error:%s\n", err)
+			return
+		}
+
+		if file == "" {
+			fmt.Fprint(rootCmd.IOStreams.Out, string(data))
+			return
+		}
+		err = os.WriteFile(file, data, os.ModePerm)
+		if err != nil {
+			fmt.Fprintf(rootCmd.IOStreams.ErrOut, "error:%s\n", err)
+			return
+		}
+		fmt.Fprintf(rootCmd.IOStreams.Out, "Exported to %s\n", file)
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:     "import",
+	Short:   "Imports the historical metric data of a field",
+	Example: `  myc metric import gateway1.node1.source1.V_TEMP --format csv --file temperature.csv`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		rootCmd.UpdateStreams(cmd)
+	},
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := rootCmd.GetClient()
+
+		var data []byte
+		var err error
+		if file == "-" {
+			data, err = io.ReadAll(rootCmd.IOStreams.In)
+		} else {
+			data, err = os.ReadFile(file)
+		}
+		if err != nil {
+			fmt.Fprintf(rootCmd.IOStreams.ErrOut, "error:%s\n", err)
+			return
+		}
+
+		queryParams := map[string]interface{}{
+			"quick_id": fmt.Sprintf("field:%s", args[0]),
+			"format":   format,
+		}
+		result, err := client.ImportMetric(queryParams, data)
+		if err != nil {
+			fmt.Fprintf(rootCmd.IOStreams.ErrOut, "error:%s\n", err)
+			return
+		}
+		fmt.Fprintf(rootCmd.IOStreams.Out, "Imported:%d, Skipped:%d\n", result.Imported, result.Skipped)
+		if result.Message != "" {
+			fmt.Fprintln(rootCmd.IOStreams.Out, result.Message)
+		}
+	},
+}
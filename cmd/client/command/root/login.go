@@ -3,6 +3,7 @@ package root
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -13,6 +14,7 @@ var (
 	loginUsername  string
 	loginPassword  string
 	loginToken     string
+	loginCode      string
 	loginExpiresIn string
 	loginInsecure  bool
 )
@@ -22,6 +24,7 @@ func init() {
 	loginCmd.Flags().StringVarP(&loginUsername, "username", "u", "", "Username to login")
 	loginCmd.Flags().StringVarP(&loginPassword, "password", "p", "", "Password to login")
 	loginCmd.Flags().StringVarP(&loginToken, "token", "t", "", "token to login")
+	loginCmd.Flags().StringVar(&loginCode, "code", "", "two factor authentication code or a recovery code")
 	loginCmd.Flags().StringVar(&loginExpiresIn, "expires-in", "720h", "session expires in, value in hours")
 	loginCmd.Flags().BoolVar(&loginInsecure, "insecure", false,
 		"If true, the server's certificate will not be checked for validity. This will make your HTTPS connections insecure")
@@ -46,6 +49,9 @@ var loginCmd = &cobra.Command{
 
   # token based login
   myc login http://localhost:8080 --token <token>
+
+  # login with two factor authentication code, prompts the code if not supplied
+  myc login http://localhost:8080 --username admin --code 123456
 	`,
 	PreRun: func(cmd *cobra.Command, args []string) {
 		UpdateStreams(cmd)
@@ -77,13 +83,25 @@ var loginCmd = &cobra.Command{
 		CONFIG.URL = args[0]
 		CONFIG.Insecure = loginInsecure
 		client := GetClient()
-		res, err := client.Login(loginUsername, loginPassword, loginToken, loginExpiresIn)
+		res, err := client.Login(loginUsername, loginPassword, loginToken, loginCode, loginExpiresIn)
+		if err != nil && loginCode == "" && strings.Contains(err.Error(), "two factor authentication code required") {
+			// get two factor authentication code from terminal
+			loginCode, err = promptCode()
+			if err != nil {
+				fmt.Fprintln(IOStreams.ErrOut, err.Error())
+				return
+			}
+			res, err = client.Login(loginUsername, loginPassword, loginToken, loginCode, loginExpiresIn)
+		}
 		if err != nil {
 			fmt.Fprintln(IOStreams.ErrOut, "error on login", err)
 			return
 		}
 		if res != nil {
 			fmt.Fprintln(IOStreams.ErrOut, "Login successful.")
+			if res.TwoFactorEnrollmentRequired {
+				fmt.Fprintln(IOStreams.ErrOut, "Two factor authentication is required, enroll via web console.")
+			}
 			CONFIG.URL = args[0]
 			CONFIG.Username = loginUsername
 			CONFIG.Password = res.Token
@@ -121,6 +139,13 @@ func promptUsername() (string, error) {
 	return username, err
 }
 
+func promptCode() (string, error) {
+	_, err := fmt.Fprint(IOStreams.Out, "Two factor code: ")
+	var code string
+	fmt.Fscanln(IOStreams.In, &code)
+	return code, err
+}
+
 func promptPassword() (string, error) {
 	fmt.Fprint(IOStreams.Out, "Password: ")
 	// TODO: should use IOStreams.In in the place of os.Stdin.Fd
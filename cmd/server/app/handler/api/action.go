@@ -6,6 +6,10 @@ import (
 	"github.com/gorilla/mux"
 	handlerUtils "github.com/mycontroller-org/server/v2/cmd/server/app/handler/utils"
 	"github.com/mycontroller-org/server/v2/pkg/api/action"
+	sceneAPI "github.com/mycontroller-org/server/v2/pkg/api/scene"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	"github.com/mycontroller-org/server/v2/pkg/utils"
+	quickIdUtils "github.com/mycontroller-org/server/v2/pkg/utils/quick_id"
 	webHandlerTY "github.com/mycontroller-org/server/v2/pkg/types/web_handler"
 	handlerTY "github.com/mycontroller-org/server/v2/plugin/handler/types"
 )
@@ -90,6 +94,11 @@ func executeGetAction(w http.ResponseWriter, r *http.Request) {
 	if len(keyPathArr) > 0 {
 		resourceData.KeyPath = keyPathArr[0]
 	}
+	err = verifySceneAccess(r, resourceData)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
 	err = action.ExecuteActionOnResourceByQuickID(resourceData)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -117,7 +126,12 @@ func executePostAction(w http.ResponseWriter, r *http.Request) {
 			KeyPath: axn.KayPath,
 			Payload: axn.Payload,
 		}
-		err := action.ExecuteActionOnResourceByQuickID(resourceData)
+		err := verifySceneAccess(r, resourceData)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		err = action.ExecuteActionOnResourceByQuickID(resourceData)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -125,3 +139,12 @@ func executePostAction(w http.ResponseWriter, r *http.Request) {
 	}
 
 }
+
+// verifySceneAccess verifies the field permissions of the caller on a scene action
+func verifySceneAccess(r *http.Request, resourceData *handlerTY.ResourceData) error {
+	resourceType, kvMap, err := quickIdUtils.EntityKeyValueMap(resourceData.QuickID)
+	if err != nil || !utils.ContainsString(quickIdUtils.QuickIDScene, resourceType) {
+		return nil
+	}
+	return sceneAPI.VerifyActionAccess(getAccess(r), kvMap[types.KeyID], resourceData.Payload)
+}
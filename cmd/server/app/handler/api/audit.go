@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	handlerUtils "github.com/mycontroller-org/server/v2/cmd/server/app/handler/utils"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	auditTY "github.com/mycontroller-org/server/v2/pkg/types/audit"
+)
+
+// RegisterAuditLogRoutes registers audit log api
+func RegisterAuditLogRoutes(router *mux.Router) {
+	router.HandleFunc("/api/audit", listAuditLogs).Methods(http.MethodGet)
+	router.HandleFunc("/api/audit/{id}", getAuditLog).Methods(http.MethodGet)
+}
+
+func listAuditLogs(w http.ResponseWriter, r *http.Request) {
+	handlerUtils.FindMany(w, r, types.EntityAuditLog, &[]auditTY.AuditLog{})
+}
+
+func getAuditLog(w http.ResponseWriter, r *http.Request) {
+	handlerUtils.FindOne(w, r, types.EntityAuditLog, &auditTY.AuditLog{})
+}
@@ -1,7 +1,10 @@
 package auth
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -9,6 +12,7 @@ import (
 	handlerUtils "github.com/mycontroller-org/server/v2/cmd/server/app/handler/utils"
 	svcTokenAPI "github.com/mycontroller-org/server/v2/pkg/api/service_token"
 	userAPI "github.com/mycontroller-org/server/v2/pkg/api/user"
+	loginProtection "github.com/mycontroller-org/server/v2/pkg/service/login_protection"
 	svcTokenTY "github.com/mycontroller-org/server/v2/pkg/types/service_token"
 	userTY "github.com/mycontroller-org/server/v2/pkg/types/user"
 	handlerTY "github.com/mycontroller-org/server/v2/pkg/types/web_handler"
@@ -21,6 +25,11 @@ func RegisterAuthRoutes(router *mux.Router) {
 	router.HandleFunc("/api/user/login", login).Methods(http.MethodPost)
 	router.HandleFunc("/api/user/profile", profile).Methods(http.MethodGet)
 	router.HandleFunc("/api/user/profile", updateProfile).Methods(http.MethodPost)
+	router.HandleFunc("/api/user/twofactor/enroll", enrollTwoFactor).Methods(http.MethodPost)
+	router.HandleFunc("/api/user/twofactor/confirm", confirmTwoFactor).Methods(http.MethodPost)
+	router.HandleFunc("/api/user/twofactor/disable", disableTwoFactor).Methods(http.MethodPost)
+	router.HandleFunc("/api/user/twofactor/recovery", regenerateRecoveryCodes).Methods(http.MethodPost)
+	router.HandleFunc("/api/user/twofactor/reset", resetTwoFactor).Methods(http.MethodPost)
 }
 
 func login(w http.ResponseWriter, r *http.Request) {
@@ -34,6 +43,14 @@ func login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// refuse the login, if the username or the client ip is locked
+	clientIP := middleware.GetClientIP(r)
+	if locked, remaining := loginProtection.IsLocked(login.Username, clientIP); locked {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(remaining.Seconds()))))
+		handlerUtils.PostErrorResponse(w, "too many failed login attempts, try again later", http.StatusTooManyRequests)
+		return
+	}
+
 	var userInDB userTY.User
 	var svcTokenID string
 
@@ -41,6 +58,7 @@ func login(w http.ResponseWriter, r *http.Request) {
 	if login.SvcToken != "" {
 		parsedToken, err := svcTokenTY.ParseToken(login.SvcToken)
 		if err != nil {
+			loginProtection.Failed("", clientIP)
 			handlerUtils.PostErrorResponse(w, "invalid token", http.StatusUnauthorized)
 			return
 		}
@@ -48,6 +66,7 @@ func login(w http.ResponseWriter, r *http.Request) {
 		// get actual token
 		actualToken, err := svcTokenAPI.GetByTokenID(parsedToken.ID)
 		if err != nil {
+			loginProtection.Failed("", clientIP)
 			handlerUtils.PostErrorResponse(w, "invalid token", http.StatusUnauthorized)
 			return
 		}
@@ -55,6 +74,7 @@ func login(w http.ResponseWriter, r *http.Request) {
 		// verify validity
 		if !actualToken.NeverExpire {
 			if actualToken.ExpiresOn.Before(time.Now()) {
+				loginProtection.Failed("", clientIP)
 				handlerUtils.PostErrorResponse(w, "invalid token", http.StatusUnauthorized)
 				return
 			}
@@ -62,6 +82,7 @@ func login(w http.ResponseWriter, r *http.Request) {
 
 		// verify token
 		if !hashed.IsValidPassword(actualToken.Token.Token, parsedToken.Token) {
+			loginProtection.Failed("", clientIP)
 			handlerUtils.PostErrorResponse(w, "invalid token", http.StatusUnauthorized)
 			return
 		}
@@ -69,6 +90,7 @@ func login(w http.ResponseWriter, r *http.Request) {
 		// get user details
 		_userInDB, err := userAPI.GetByID(actualToken.UserID)
 		if err != nil {
+			loginProtection.Failed("", clientIP)
 			handlerUtils.PostErrorResponse(w, "invalid token", http.StatusUnauthorized)
 			return
 		}
@@ -78,19 +100,32 @@ func login(w http.ResponseWriter, r *http.Request) {
 		// get user details
 		_userInDB, err := userAPI.GetByUsername(login.Username)
 		if err != nil {
+			loginProtection.Failed(login.Username, clientIP)
 			handlerUtils.PostErrorResponse(w, "invalid user or password", http.StatusUnauthorized)
 			return
 		}
 
 		//compare the user from the request, with the one we defined:
 		if login.Username != _userInDB.Username || !hashed.IsValidPassword(_userInDB.Password, login.Password) {
+			loginProtection.Failed(login.Username, clientIP)
 			handlerUtils.PostErrorResponse(w, "please provide valid login details", http.StatusUnauthorized)
 			return
 		}
+
+		// verify two factor authentication code, if enabled
+		err = userAPI.VerifyTwoFactor(&_userInDB, login.Code)
+		if err != nil {
+			if errors.Is(err, userAPI.ErrTwoFactorInvalidCode) {
+				loginProtection.Failed(login.Username, clientIP)
+			}
+			handlerUtils.PostErrorResponse(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		loginProtection.Succeeded(login.Username)
 		userInDB = _userInDB
 	}
 
-	token, err := middleware.CreateToken(userInDB, login.ExpiresIn, svcTokenID)
+	token, err := middleware.CreateToken(r, userInDB, login.ExpiresIn, svcTokenID)
 	if err != nil {
 		handlerUtils.PostErrorResponse(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -104,14 +139,7 @@ func login(w http.ResponseWriter, r *http.Request) {
 		cookieValidity = handlerTY.DefaultTokenExpiration
 	}
 
-	generatedCookie := &http.Cookie{
-		Name:    handlerTY.AUTH_COOKIE_NAME,
-		Path:    "/",
-		Domain:  handlerUtils.ExtractHost(r.Host),
-		Expires: time.Now().Add(cookieValidity),
-		Value:   token,
-	}
-	http.SetCookie(w, generatedCookie)
+	setAuthCookie(w, r, token, cookieValidity)
 
 	// token response in the response body
 	tokenResponse := &handlerTY.JwtTokenResponse{
@@ -120,10 +148,24 @@ func login(w http.ResponseWriter, r *http.Request) {
 		Email:    userInDB.Email,
 		FullName: userInDB.FullName,
 		Token:    token,
+
+		TwoFactorEnrollmentRequired: svcTokenID == "" && middleware.IsTwoFactorEnrollmentRequired(&userInDB),
 	}
 	handlerUtils.PostSuccessResponse(w, tokenResponse)
 }
 
+// setAuthCookie sets the authorization cookie
+func setAuthCookie(w http.ResponseWriter, r *http.Request, token string, validity time.Duration) {
+	generatedCookie := &http.Cookie{
+		Name:    handlerTY.AUTH_COOKIE_NAME,
+		Path:    "/",
+		Domain:  handlerUtils.ExtractHost(r.Host),
+		Expires: time.Now().Add(validity),
+		Value:   token,
+	}
+	http.SetCookie(w, generatedCookie)
+}
+
 func profile(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	userID := middleware.GetUserID(r)
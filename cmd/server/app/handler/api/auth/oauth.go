@@ -2,10 +2,13 @@ package auth
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -13,6 +16,7 @@ import (
 	handlerUtils "github.com/mycontroller-org/server/v2/cmd/server/app/handler/utils"
 	svcTokenAPI "github.com/mycontroller-org/server/v2/pkg/api/service_token"
 	userAPI "github.com/mycontroller-org/server/v2/pkg/api/user"
+	loginProtection "github.com/mycontroller-org/server/v2/pkg/service/login_protection"
 	userTY "github.com/mycontroller-org/server/v2/pkg/types/user"
 	handlerTY "github.com/mycontroller-org/server/v2/pkg/types/web_handler"
 	"github.com/mycontroller-org/server/v2/pkg/utils/hashed"
@@ -50,9 +54,18 @@ func oAuthLogin(w http.ResponseWriter, r *http.Request) {
 		Username:  credentials.Get("username"),
 		Password:  credentials.Get("password"),
 		SvcToken:  credentials.Get("token"),
+		Code:      credentials.Get("code"),
 		ExpiresIn: "168h", // 7 days
 	}
 
+	// refuse the login, if the username or the client ip is locked
+	clientIP := middleware.GetClientIP(r)
+	if locked, remaining := loginProtection.IsLocked(userLogin.Username, clientIP); locked {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(remaining.Seconds()))))
+		handlerUtils.PostErrorResponse(w, "too many failed login attempts, try again later", http.StatusTooManyRequests)
+		return
+	}
+
 	var userInDB userTY.User
 	var svcTokenID string
 
@@ -61,6 +74,7 @@ func oAuthLogin(w http.ResponseWriter, r *http.Request) {
 		// get hashed token
 		hashedToken, err := hashed.GenerateHash(userLogin.SvcToken)
 		if err != nil {
+			loginProtection.Failed("", clientIP)
 			handlerUtils.PostErrorResponse(w, "invalid token", http.StatusUnauthorized)
 			return
 		}
@@ -68,12 +82,14 @@ func oAuthLogin(w http.ResponseWriter, r *http.Request) {
 		// verify token
 		svcToken, err := svcTokenAPI.GetByTokenID(hashedToken)
 		if err != nil {
+			loginProtection.Failed("", clientIP)
 			handlerUtils.PostErrorResponse(w, "invalid token", http.StatusUnauthorized)
 			return
 		}
 
 		// verify validity
 		if svcToken.ExpiresOn.After(time.Now()) {
+			loginProtection.Failed("", clientIP)
 			handlerUtils.PostErrorResponse(w, "invalid token", http.StatusUnauthorized)
 			return
 		}
@@ -81,28 +97,48 @@ func oAuthLogin(w http.ResponseWriter, r *http.Request) {
 		// get user details
 		_userInDB, err := userAPI.GetByID(svcToken.UserID)
 		if err != nil {
+			loginProtection.Failed("", clientIP)
 			handlerUtils.PostErrorResponse(w, "invalid token", http.StatusUnauthorized)
 			return
 		}
 		userInDB = _userInDB
-		svcTokenID = svcToken.ID
+		svcTokenID = svcToken.Token.ID
 	} else { // user based authentication
 		// get user details
 		_userInDB, err := userAPI.GetByUsername(userLogin.Username)
 		if err != nil {
+			loginProtection.Failed(userLogin.Username, clientIP)
 			handlerUtils.PostErrorResponse(w, "invalid user or password", http.StatusUnauthorized)
 			return
 		}
 
 		//compare the user from the request, with the one we defined:
 		if userLogin.Username != _userInDB.Username || !hashed.IsValidPassword(_userInDB.Password, userLogin.Password) {
+			loginProtection.Failed(userLogin.Username, clientIP)
 			handlerUtils.PostErrorResponse(w, "please provide valid login details", http.StatusUnauthorized)
 			return
 		}
+
+		// verify two factor authentication code, if enabled
+		err = userAPI.VerifyTwoFactor(&_userInDB, userLogin.Code)
+		if err != nil {
+			if errors.Is(err, userAPI.ErrTwoFactorInvalidCode) {
+				loginProtection.Failed(userLogin.Username, clientIP)
+			}
+			handlerUtils.PostErrorResponse(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		// linked services can not complete the two factor enrollment
+		if middleware.IsTwoFactorEnrollmentRequired(&_userInDB) {
+			handlerUtils.PostErrorResponse(w, "two factor authentication enrollment required, enroll via web console", http.StatusUnauthorized)
+			return
+		}
+		loginProtection.Succeeded(userLogin.Username)
 		userInDB = _userInDB
 	}
 
-	accessToken, err := middleware.CreateToken(userInDB, userLogin.ExpiresIn, svcTokenID)
+	accessToken, err := middleware.CreateToken(r, userInDB, userLogin.ExpiresIn, svcTokenID)
 	if err != nil {
 		handlerUtils.PostErrorResponse(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -213,7 +249,7 @@ func oAuthToken(w http.ResponseWriter, r *http.Request) {
 
 	validity := time.Hour * 24 * 7 // 7 days
 
-	refreshToken, err := middleware.CreateToken(userInDB, validity.String(), "")
+	refreshToken, err := middleware.CreateToken(r, userInDB, validity.String(), "")
 	if err != nil {
 		zap.L().Info("error on creating token", zap.Error(err))
 		http.Error(w, "internal error", http.StatusInternalServerError)
@@ -268,7 +304,7 @@ func oAuthTokenAlexa(w http.ResponseWriter, r *http.Request) {
 
 	validity := time.Hour * 24 * 7 // 7 days
 
-	refreshToken, err := middleware.CreateToken(userInDB, validity.String(), "")
+	refreshToken, err := middleware.CreateToken(r, userInDB, validity.String(), "")
 	if err != nil {
 		zap.L().Info("error on creating token", zap.Error(err))
 		http.Error(w, "internal error", http.StatusInternalServerError)
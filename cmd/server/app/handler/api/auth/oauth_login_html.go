@@ -85,6 +85,20 @@ const OAuthLoginPageHTML = `
                   name="token"
                 />
               </div>
+              <div class="pf-c-form__group">
+                <label class="pf-c-form__label" for="code">
+                  <span class="pf-c-form__label-text">Two factor code (if enabled)</span>
+                </label>
+                <input
+                  class="pf-c-form-control"
+                  input="true"
+                  type="text"
+                  inputmode="numeric"
+                  autocomplete="one-time-code"
+                  id="code"
+                  name="code"
+                />
+              </div>
               <div class="pf-c-form__group pf-m-action">
                 <button
                   class="pf-c-button pf-m-primary pf-m-block"
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	middleware "github.com/mycontroller-org/server/v2/cmd/server/app/handler/middleware"
+	handlerUtils "github.com/mycontroller-org/server/v2/cmd/server/app/handler/utils"
+	oidcSVC "github.com/mycontroller-org/server/v2/pkg/service/oidc"
+	handlerTY "github.com/mycontroller-org/server/v2/pkg/types/web_handler"
+	"github.com/mycontroller-org/server/v2/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// RegisterOIDCRoutes registers the external identity provider login api
+func RegisterOIDCRoutes(router *mux.Router) {
+	router.HandleFunc("/api/oidc/providers", listOIDCProviders).Methods(http.MethodGet)
+	router.HandleFunc("/api/oidc/login/{id}", oidcLogin).Methods(http.MethodGet)
+	router.HandleFunc("/api/oidc/callback/{id}", oidcCallback).Methods(http.MethodGet)
+}
+
+func listOIDCProviders(w http.ResponseWriter, r *http.Request) {
+	handlerUtils.PostSuccessResponse(w, oidcSVC.GetProviders())
+}
+
+// oidcLogin redirects to the login page of the provider
+func oidcLogin(w http.ResponseWriter, r *http.Request) {
+	providerID := mux.Vars(r)["id"]
+	authorizationURL, err := oidcSVC.GetAuthorizationURL(providerID)
+	if err != nil {
+		zap.L().Error("error on oidc login", zap.String("provider", providerID), zap.Error(err))
+		handlerUtils.PostErrorResponse(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, authorizationURL, http.StatusFound)
+}
+
+// oidcCallback completes the login, sets the authorization cookie and redirects to the web console
+func oidcCallback(w http.ResponseWriter, r *http.Request) {
+	providerID := mux.Vars(r)["id"]
+	query := r.URL.Query()
+	if errorCode := query.Get("error"); errorCode != "" {
+		handlerUtils.PostErrorResponse(w, errorCode+": "+query.Get("error_description"), http.StatusUnauthorized)
+		return
+	}
+
+	user, expiresIn, err := oidcSVC.Login(providerID, query.Get("state"), query.Get("code"))
+	if err != nil {
+		zap.L().Info("oidc login failed", zap.String("provider", providerID), zap.Error(err))
+		handlerUtils.PostErrorResponse(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	token, err := middleware.CreateToken(r, *user, expiresIn, "")
+	if err != nil {
+		handlerUtils.PostErrorResponse(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	setAuthCookie(w, r, token, utils.ToDuration(expiresIn, handlerTY.DefaultTokenExpiration))
+	http.Redirect(w, r, "/", http.StatusFound)
+}
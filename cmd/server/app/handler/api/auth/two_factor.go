@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	middleware "github.com/mycontroller-org/server/v2/cmd/server/app/handler/middleware"
+	handlerUtils "github.com/mycontroller-org/server/v2/cmd/server/app/handler/utils"
+	userAPI "github.com/mycontroller-org/server/v2/pkg/api/user"
+	userTY "github.com/mycontroller-org/server/v2/pkg/types/user"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+)
+
+func enrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	enrollment, err := userAPI.BeginTwoFactorEnrollment(middleware.GetUserID(r))
+	if err != nil {
+		handlerUtils.PostErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	handlerUtils.PostSuccessResponse(w, enrollment)
+}
+
+func confirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	request := &userTY.TwoFactorRequest{}
+	err := handlerUtils.LoadEntity(w, r, request)
+	if err != nil {
+		handlerUtils.PostErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	recoveryCodes, err := userAPI.ConfirmTwoFactorEnrollment(middleware.GetUserID(r), request.Code)
+	if err != nil {
+		handlerUtils.PostErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	handlerUtils.PostSuccessResponse(w, recoveryCodes)
+}
+
+func disableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	request := &userTY.TwoFactorRequest{}
+	err := handlerUtils.LoadEntity(w, r, request)
+	if err != nil {
+		handlerUtils.PostErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = userAPI.DisableTwoFactor(middleware.GetUserID(r), request.Code)
+	if err != nil {
+		handlerUtils.PostErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	handlerUtils.PostSuccessResponse(w, "two factor authentication disabled")
+}
+
+func regenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	request := &userTY.TwoFactorRequest{}
+	err := handlerUtils.LoadEntity(w, r, request)
+	if err != nil {
+		handlerUtils.PostErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	recoveryCodes, err := userAPI.RegenerateRecoveryCodes(middleware.GetUserID(r), request.Code)
+	if err != nil {
+		handlerUtils.PostErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	handlerUtils.PostSuccessResponse(w, recoveryCodes)
+}
+
+// resetTwoFactor removes two factor authentication of the users, used by administrators
+func resetTwoFactor(w http.ResponseWriter, r *http.Request) {
+	IDs := []string{}
+	updateFn := func(f []storageTY.Filter, p *storageTY.Pagination, d []byte) (interface{}, error) {
+		if len(IDs) > 0 {
+			err := userAPI.ResetTwoFactor(IDs)
+			if err != nil {
+				return nil, err
+			}
+			return "two factor authentication reset", nil
+		}
+		return nil, errors.New("supply id(s)")
+	}
+	handlerUtils.UpdateData(w, r, &IDs, updateFn)
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	handlerUtils "github.com/mycontroller-org/server/v2/cmd/server/app/handler/utils"
+	bundleAPI "github.com/mycontroller-org/server/v2/pkg/api/bundle"
+	json "github.com/mycontroller-org/server/v2/pkg/json"
+	"github.com/mycontroller-org/server/v2/pkg/utils/convertor"
+)
+
+// RegisterBundleRoutes registers bundle api
+func RegisterBundleRoutes(router *mux.Router) {
+	router.HandleFunc("/api/bundle/apply", applyBundle).Methods(http.MethodPost)
+}
+
+func applyBundle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	bundle, err := bundleAPI.Parse(data)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	dryRun := convertor.ToBool(r.URL.Query().Get("dryRun"))
+	result, err := bundleAPI.Apply(handlerUtils.GetApiContext(r), bundle, dryRun)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	od, err := json.Marshal(result)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	handlerUtils.WriteResponse(w, od)
+}
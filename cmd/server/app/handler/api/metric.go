@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
 	handlerUtils "github.com/mycontroller-org/server/v2/cmd/server/app/handler/utils"
 	"github.com/mycontroller-org/server/v2/pkg/api/field"
+	metricAPI "github.com/mycontroller-org/server/v2/pkg/api/metric"
 	json "github.com/mycontroller-org/server/v2/pkg/json"
-	"github.com/mycontroller-org/server/v2/pkg/store"
+	metricSVC "github.com/mycontroller-org/server/v2/pkg/service/database/metric"
 	types "github.com/mycontroller-org/server/v2/pkg/types"
 	"github.com/mycontroller-org/server/v2/pkg/utils"
 	quickIdUL "github.com/mycontroller-org/server/v2/pkg/utils/quick_id"
@@ -18,13 +20,16 @@ import (
 
 // global constants
 const (
-	QuickID = "quick_id"
+	QuickID        = "quick_id"
+	QueryKeyFormat = "format"
 )
 
 // RegisterMetricRoutes registers metric api
 func RegisterMetricRoutes(router *mux.Router) {
 	router.HandleFunc("/api/metric", getMetricList).Methods(http.MethodPost)
 	router.HandleFunc("/api/metric", getMetric).Methods(http.MethodGet)
+	router.HandleFunc("/api/metric/export", exportMetric).Methods(http.MethodGet)
+	router.HandleFunc("/api/metric/import", importMetric).Methods(http.MethodPost)
 }
 
 func getMetric(w http.ResponseWriter, r *http.Request) {
@@ -75,6 +80,7 @@ func getMetric(w http.ResponseWriter, r *http.Request) {
 				}
 				queryConfig.Individual[0].Tags[types.KeyID] = field.ID
 				queryConfig.Individual[0].MetricType = field.MetricType
+				queryConfig.Individual[0].Policy = metricSVC.GetPolicyName(field.MetricType, field.Labels)
 
 			default:
 				http.Error(w, fmt.Sprintf("resource type not supported in metric. ResourceType:%s", rt), 500)
@@ -103,7 +109,7 @@ func getMetric(w http.ResponseWriter, r *http.Request) {
 		queryConfig.Global.Functions = values
 	}
 
-	result, err := store.METRIC.Query(queryConfig)
+	result, err := metricSVC.Query(queryConfig)
 	if err != nil {
 		http.Error(w, err.Error(), 500)
 		return
@@ -134,7 +140,7 @@ func getMetricList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := store.METRIC.Query(queryConfig)
+	result, err := metricSVC.Query(queryConfig)
 	if err != nil {
 		http.Error(w, err.Error(), 500)
 		return
@@ -146,3 +152,70 @@ func getMetricList(w http.ResponseWriter, r *http.Request) {
 	}
 	handlerUtils.WriteResponse(w, od)
 }
+
+func exportMetric(w http.ResponseWriter, r *http.Request) {
+	params, err := handlerUtils.ReceivedQueryMap(r)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	quickID := handlerUtils.GetParameter(QuickID, params)
+	if quickID == "" {
+		http.Error(w, fmt.Sprintf("%s not supplied", QuickID), 400)
+		return
+	}
+
+	query := &mtsTY.Query{
+		Start:     handlerUtils.GetParameter(mtsTY.QueryKeyStart, params),
+		Stop:      handlerUtils.GetParameter(mtsTY.QueryKeyStop, params),
+		Window:    handlerUtils.GetParameter(mtsTY.QueryKeyWindow, params),
+		Functions: handlerUtils.GetParameters(mtsTY.QueryKeyFunctions, params),
+	}
+	format := handlerUtils.GetParameter(QueryKeyFormat, params)
+
+	data, contentType, err := metricAPI.Export(quickID, query, format)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	extension := format
+	if extension == "" {
+		extension = metricAPI.FormatCSV
+	} else if extension == metricAPI.FormatLineProtocol {
+		extension = "txt"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="metric_%s.%s"`, strings.ReplaceAll(quickID, ":", "_"), extension))
+	handlerUtils.WriteResponse(w, data)
+}
+
+func importMetric(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	params, err := handlerUtils.ReceivedQueryMap(r)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	quickID := handlerUtils.GetParameter(QuickID, params)
+	if quickID == "" {
+		http.Error(w, fmt.Sprintf("%s not supplied", QuickID), 400)
+		return
+	}
+
+	d, err := io.ReadAll(r.Body)
+	defer r.Body.Close()
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	result, err := metricAPI.Import(quickID, handlerUtils.GetParameter(QueryKeyFormat, params), d)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	handlerUtils.PostSuccessResponse(w, result)
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	middleware "github.com/mycontroller-org/server/v2/cmd/server/app/handler/middleware"
+	handlerUtils "github.com/mycontroller-org/server/v2/cmd/server/app/handler/utils"
+	"github.com/mycontroller-org/server/v2/pkg/openapi"
+	"github.com/mycontroller-org/server/v2/pkg/version"
+)
+
+// RegisterOpenAPIRoutes registers OpenAPI specification api
+// the specification generated from the routes registered on the router
+func RegisterOpenAPIRoutes(router *mux.Router) {
+	router.HandleFunc("/api/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		getOpenAPISpec(router, w, r)
+	}).Methods(http.MethodGet)
+}
+
+func getOpenAPISpec(router *mux.Router, w http.ResponseWriter, r *http.Request) {
+	routes := make([]openapi.Route, 0)
+	err := router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil { // path prefix and other routes without template
+			return nil
+		}
+		methods, _ := route.GetMethods()
+		routes = append(routes, openapi.Route{Path: path, Methods: methods})
+		return nil
+	})
+	if err != nil {
+		handlerUtils.PostErrorResponse(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	doc := openapi.Generate(routes, version.Get().Version, middleware.IsNonRestrictedAPI)
+	handlerUtils.PostSuccessResponse(w, doc)
+}
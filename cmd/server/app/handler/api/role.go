@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+	handlerUtils "github.com/mycontroller-org/server/v2/cmd/server/app/handler/utils"
+	roleAPI "github.com/mycontroller-org/server/v2/pkg/api/role"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	roleTY "github.com/mycontroller-org/server/v2/pkg/types/role"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+)
+
+// RegisterRoleRoutes registers role api
+func RegisterRoleRoutes(router *mux.Router) {
+	router.HandleFunc("/api/role", listRoles).Methods(http.MethodGet)
+	router.HandleFunc("/api/role/builtin", listBuiltInRoles).Methods(http.MethodGet)
+	router.HandleFunc("/api/role/{id}", getRole).Methods(http.MethodGet)
+	router.HandleFunc("/api/role", updateRole).Methods(http.MethodPost)
+	router.HandleFunc("/api/role", deleteRoles).Methods(http.MethodDelete)
+}
+
+func listRoles(w http.ResponseWriter, r *http.Request) {
+	handlerUtils.FindMany(w, r, types.EntityRole, &[]roleTY.Role{})
+}
+
+func listBuiltInRoles(w http.ResponseWriter, r *http.Request) {
+	roles := make([]roleTY.Role, 0, len(roleTY.BuiltInRoles))
+	for _, role := range roleTY.BuiltInRoles {
+		roles = append(roles, role)
+	}
+	sort.SliceStable(roles, func(i, j int) bool { return roles[i].ID < roles[j].ID })
+	handlerUtils.PostSuccessResponse(w, roles)
+}
+
+func getRole(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	role, err := roleAPI.GetByID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	handlerUtils.PostSuccessResponse(w, role)
+}
+
+func updateRole(w http.ResponseWriter, r *http.Request) {
+	entity := &roleTY.Role{}
+	err := handlerUtils.LoadEntity(w, r, entity)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = roleAPI.Save(entity)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func deleteRoles(w http.ResponseWriter, r *http.Request) {
+	IDs := []string{}
+	updateFn := func(f []storageTY.Filter, p *storageTY.Pagination, d []byte) (interface{}, error) {
+		if len(IDs) > 0 {
+			count, err := roleAPI.Delete(IDs)
+			if err != nil {
+				return nil, err
+			}
+			return fmt.Sprintf("deleted: %d", count), nil
+		}
+		return nil, errors.New("supply id(s)")
+	}
+	handlerUtils.UpdateData(w, r, &IDs, updateFn)
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	handlerUtils "github.com/mycontroller-org/server/v2/cmd/server/app/handler/utils"
+	"github.com/mycontroller-org/server/v2/pkg/api/action"
+	sceneAPI "github.com/mycontroller-org/server/v2/pkg/api/scene"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	roleTY "github.com/mycontroller-org/server/v2/pkg/types/role"
+	sceneTY "github.com/mycontroller-org/server/v2/pkg/types/scene"
+	quickIdUtils "github.com/mycontroller-org/server/v2/pkg/utils/quick_id"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+	handlerTY "github.com/mycontroller-org/server/v2/plugin/handler/types"
+)
+
+// RegisterSceneRoutes registers scene api
+func RegisterSceneRoutes(router *mux.Router) {
+	router.HandleFunc("/api/scene", listScenes).Methods(http.MethodGet)
+	router.HandleFunc("/api/scene/{id}", getScene).Methods(http.MethodGet)
+	router.HandleFunc("/api/scene", updateScene).Methods(http.MethodPost)
+	router.HandleFunc("/api/scene/snapshot", snapshotScenes).Methods(http.MethodPost)
+	router.HandleFunc("/api/scene/restore", restoreScenes).Methods(http.MethodPost)
+	router.HandleFunc("/api/scene", deleteScenes).Methods(http.MethodDelete)
+}
+
+func listScenes(w http.ResponseWriter, r *http.Request) {
+	handlerUtils.FindMany(w, r, types.EntityScene, &[]sceneTY.Config{})
+}
+
+func getScene(w http.ResponseWriter, r *http.Request) {
+	handlerUtils.FindOne(w, r, types.EntityScene, &sceneTY.Config{})
+}
+
+func updateScene(w http.ResponseWriter, r *http.Request) {
+	entity := &sceneTY.Config{}
+	err := handlerUtils.LoadEntity(w, r, entity)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	if entity.ID == "" {
+		http.Error(w, "id should not be empty", 400)
+		return
+	}
+	err = sceneAPI.VerifyFieldAccess(getAccess(r), entity)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	err = sceneAPI.Save(entity)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+}
+
+func snapshotScenes(w http.ResponseWriter, r *http.Request) {
+	executeSceneAction(w, r, sceneTY.ActionSnapshot, "Captured")
+}
+
+func restoreScenes(w http.ResponseWriter, r *http.Request) {
+	executeSceneAction(w, r, sceneTY.ActionRestore, "Restored")
+}
+
+func executeSceneAction(w http.ResponseWriter, r *http.Request, sceneAction, message string) {
+	IDs := []string{}
+	updateFn := func(f []storageTY.Filter, p *storageTY.Pagination, d []byte) (interface{}, error) {
+		if len(IDs) == 0 {
+			return nil, errors.New("supply id(s)")
+		}
+		access := getAccess(r)
+		for _, id := range IDs {
+			if err := sceneAPI.VerifyActionAccess(access, id, sceneAction); err != nil {
+				return nil, err
+			}
+		}
+		for _, id := range IDs {
+			err := action.ExecuteActionOnResourceByQuickID(&handlerTY.ResourceData{
+				ResourceType: quickIdUtils.QuickIDScene[0],
+				QuickID:      fmt.Sprintf("%s:%s", quickIdUtils.QuickIDScene[0], id),
+				Payload:      sceneAction,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return message, nil
+	}
+	handlerUtils.UpdateData(w, r, &IDs, updateFn)
+}
+
+func deleteScenes(w http.ResponseWriter, r *http.Request) {
+	IDs := []string{}
+	updateFn := func(f []storageTY.Filter, p *storageTY.Pagination, d []byte) (interface{}, error) {
+		if len(IDs) > 0 {
+			count, err := sceneAPI.Delete(IDs)
+			if err != nil {
+				return nil, err
+			}
+			return fmt.Sprintf("deleted: %d", count), nil
+		}
+		return nil, errors.New("supply id(s)")
+	}
+	handlerUtils.UpdateData(w, r, &IDs, updateFn)
+}
+
+// getAccess returns the permissions of the caller, nil if not available
+func getAccess(r *http.Request) *roleTY.Access {
+	if mcApiContext := handlerUtils.GetApiContext(r); mcApiContext != nil {
+		return mcApiContext.Access
+	}
+	return nil
+}
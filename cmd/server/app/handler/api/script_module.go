@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	handlerUtils "github.com/mycontroller-org/server/v2/cmd/server/app/handler/utils"
+	scriptModuleAPI "github.com/mycontroller-org/server/v2/pkg/api/script_module"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	scriptModuleTY "github.com/mycontroller-org/server/v2/pkg/types/script_module"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+)
+
+// RegisterScriptModuleRoutes registers script module api
+func RegisterScriptModuleRoutes(router *mux.Router) {
+	router.HandleFunc("/api/scriptmodule", listScriptModules).Methods(http.MethodGet)
+	router.HandleFunc("/api/scriptmodule/{id}", getScriptModule).Methods(http.MethodGet)
+	router.HandleFunc("/api/scriptmodule", updateScriptModule).Methods(http.MethodPost)
+	router.HandleFunc("/api/scriptmodule", deleteScriptModules).Methods(http.MethodDelete)
+}
+
+func listScriptModules(w http.ResponseWriter, r *http.Request) {
+	handlerUtils.FindMany(w, r, types.EntityScriptModule, &[]scriptModuleTY.Config{})
+}
+
+func getScriptModule(w http.ResponseWriter, r *http.Request) {
+	handlerUtils.FindOne(w, r, types.EntityScriptModule, &scriptModuleTY.Config{})
+}
+
+func updateScriptModule(w http.ResponseWriter, r *http.Request) {
+	entity := &scriptModuleTY.Config{}
+	err := handlerUtils.LoadEntity(w, r, entity)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	if entity.ID == "" {
+		http.Error(w, "id should not be empty", 400)
+		return
+	}
+	err = scriptModuleAPI.Save(entity)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+}
+
+func deleteScriptModules(w http.ResponseWriter, r *http.Request) {
+	IDs := []string{}
+	updateFn := func(f []storageTY.Filter, p *storageTY.Pagination, d []byte) (interface{}, error) {
+		if len(IDs) > 0 {
+			count, err := scriptModuleAPI.Delete(IDs)
+			if err != nil {
+				return nil, err
+			}
+			return fmt.Sprintf("deleted: %d", count), nil
+		}
+		return nil, errors.New("supply id(s)")
+	}
+	handlerUtils.UpdateData(w, r, &IDs, updateFn)
+}
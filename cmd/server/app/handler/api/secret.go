@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	handlerUtils "github.com/mycontroller-org/server/v2/cmd/server/app/handler/utils"
+	secretAPI "github.com/mycontroller-org/server/v2/pkg/api/secret"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	secretTY "github.com/mycontroller-org/server/v2/pkg/types/secret"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+)
+
+// RegisterSecretRoutes registers secret api, values are never returned
+func RegisterSecretRoutes(router *mux.Router) {
+	router.HandleFunc("/api/secret", listSecrets).Methods(http.MethodGet)
+	router.HandleFunc("/api/secret/{id}", getSecret).Methods(http.MethodGet)
+	router.HandleFunc("/api/secret", updateSecret).Methods(http.MethodPost)
+	router.HandleFunc("/api/secret", deleteSecrets).Methods(http.MethodDelete)
+}
+
+func listSecrets(w http.ResponseWriter, r *http.Request) {
+	handlerUtils.FindMany(w, r, types.EntitySecret, &[]secretTY.Secret{})
+}
+
+func getSecret(w http.ResponseWriter, r *http.Request) {
+	handlerUtils.FindOne(w, r, types.EntitySecret, &secretTY.Secret{})
+}
+
+func updateSecret(w http.ResponseWriter, r *http.Request) {
+	entity := &secretTY.Secret{}
+	err := handlerUtils.LoadEntity(w, r, entity)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = secretAPI.Save(entity)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func deleteSecrets(w http.ResponseWriter, r *http.Request) {
+	IDs := []string{}
+	updateFn := func(f []storageTY.Filter, p *storageTY.Pagination, d []byte) (interface{}, error) {
+		if len(IDs) > 0 {
+			count, err := secretAPI.Delete(IDs)
+			if err != nil {
+				return nil, err
+			}
+			return fmt.Sprintf("deleted: %d", count), nil
+		}
+		return nil, errors.New("supply id(s)")
+	}
+	handlerUtils.UpdateData(w, r, &IDs, updateFn)
+}
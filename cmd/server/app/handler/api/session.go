@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	middleware "github.com/mycontroller-org/server/v2/cmd/server/app/handler/middleware"
+	handlerUtils "github.com/mycontroller-org/server/v2/cmd/server/app/handler/utils"
+	svcTokenAPI "github.com/mycontroller-org/server/v2/pkg/api/service_token"
+	sessionAPI "github.com/mycontroller-org/server/v2/pkg/api/session"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	sessionTY "github.com/mycontroller-org/server/v2/pkg/types/session"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+)
+
+// sessionResponse marks the session of the request
+type sessionResponse struct {
+	sessionTY.Session
+	Current bool `json:"current"`
+}
+
+// RegisterSessionRoutes registers the session api of the logged in user
+func RegisterSessionRoutes(router *mux.Router) {
+	router.HandleFunc("/api/session", listSessions).Methods(http.MethodGet)
+	router.HandleFunc("/api/session", revokeSessions).Methods(http.MethodDelete)
+	router.HandleFunc("/api/session/servicetoken", listOwnServiceTokens).Methods(http.MethodGet)
+	router.HandleFunc("/api/session/servicetoken", deleteOwnServiceTokens).Methods(http.MethodDelete)
+}
+
+func listSessions(w http.ResponseWriter, r *http.Request) {
+	filters := []storageTY.Filter{{Key: types.KeyUserID, Value: middleware.GetUserID(r)}}
+	result, err := sessionAPI.List(filters, nil)
+	if err != nil {
+		handlerUtils.PostErrorResponse(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sessions, ok := result.Data.(*[]sessionTY.Session)
+	if !ok {
+		handlerUtils.PostErrorResponse(w, fmt.Sprintf("invalid session list type:%T", result.Data), http.StatusInternalServerError)
+		return
+	}
+
+	currentSessionID := ""
+	if mcApiContext := handlerUtils.GetApiContext(r); mcApiContext != nil {
+		currentSessionID = mcApiContext.SessionID
+	}
+	response := make([]sessionResponse, 0, len(*sessions))
+	for _, session := range *sessions {
+		response = append(response, sessionResponse{Session: session, Current: session.ID == currentSessionID})
+	}
+	handlerUtils.PostSuccessResponse(w, response)
+}
+
+func revokeSessions(w http.ResponseWriter, r *http.Request) {
+	IDs := []string{}
+	userID := middleware.GetUserID(r)
+	updateFn := func(f []storageTY.Filter, p *storageTY.Pagination, d []byte) (interface{}, error) {
+		if len(IDs) == 0 {
+			return nil, errors.New("supply id(s)")
+		}
+		// allowed to revoke only the own sessions
+		for _, id := range IDs {
+			session, err := sessionAPI.GetByID(id)
+			if err != nil || session.UserID != userID {
+				return nil, fmt.Errorf("session not found, id:%s", id)
+			}
+		}
+		count, err := sessionAPI.Delete(IDs)
+		if err != nil {
+			return nil, err
+		}
+		return fmt.Sprintf("revoked: %d", count), nil
+	}
+	handlerUtils.UpdateData(w, r, &IDs, updateFn)
+}
+
+func listOwnServiceTokens(w http.ResponseWriter, r *http.Request) {
+	filters := []storageTY.Filter{{Key: types.KeyUserID, Value: middleware.GetUserID(r)}}
+	result, err := svcTokenAPI.List(filters, nil)
+	if err != nil {
+		handlerUtils.PostErrorResponse(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	handlerUtils.PostSuccessResponse(w, result)
+}
+
+func deleteOwnServiceTokens(w http.ResponseWriter, r *http.Request) {
+	IDs := []string{}
+	userID := middleware.GetUserID(r)
+	updateFn := func(f []storageTY.Filter, p *storageTY.Pagination, d []byte) (interface{}, error) {
+		if len(IDs) == 0 {
+			return nil, errors.New("supply id(s)")
+		}
+		// allowed to delete only the own tokens
+		for _, id := range IDs {
+			token, err := svcTokenAPI.GetByID(id)
+			if err != nil || token.UserID != userID {
+				return nil, fmt.Errorf("service token not found, id:%s", id)
+			}
+		}
+		count, err := svcTokenAPI.Delete(IDs)
+		if err != nil {
+			return nil, err
+		}
+		return fmt.Sprintf("deleted: %d", count), nil
+	}
+	handlerUtils.UpdateData(w, r, &IDs, updateFn)
+}
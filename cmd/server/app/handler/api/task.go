@@ -8,6 +8,7 @@ import (
 	"github.com/gorilla/mux"
 	handlerUtils "github.com/mycontroller-org/server/v2/cmd/server/app/handler/utils"
 	taskAPI "github.com/mycontroller-org/server/v2/pkg/api/task"
+	taskSVC "github.com/mycontroller-org/server/v2/pkg/service/task"
 	types "github.com/mycontroller-org/server/v2/pkg/types"
 	taskTY "github.com/mycontroller-org/server/v2/pkg/types/task"
 	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
@@ -21,6 +22,7 @@ func RegisterTaskRoutes(router *mux.Router) {
 	router.HandleFunc("/api/task/enable", enableTask).Methods(http.MethodPost)
 	router.HandleFunc("/api/task/disable", disableTask).Methods(http.MethodPost)
 	router.HandleFunc("/api/task", deleteTasks).Methods(http.MethodDelete)
+	router.HandleFunc("/api/task/dryrun", dryRunTask).Methods(http.MethodPost)
 }
 
 func listTasks(w http.ResponseWriter, r *http.Request) {
@@ -94,3 +96,12 @@ func disableTask(w http.ResponseWriter, r *http.Request) {
 	}
 	handlerUtils.UpdateData(w, r, &ids, updateFn)
 }
+
+// dryRunTask evaluates a saved or unsaved task and returns the evaluation trace, handlers are not notified
+func dryRunTask(w http.ResponseWriter, r *http.Request) {
+	request := &taskSVC.DryRunRequest{}
+	updateFn := func(f []storageTY.Filter, p *storageTY.Pagination, d []byte) (interface{}, error) {
+		return taskSVC.DryRun(request)
+	}
+	handlerUtils.UpdateData(w, r, request, updateFn)
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	handlerUtils "github.com/mycontroller-org/server/v2/cmd/server/app/handler/utils"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	taskHistoryTY "github.com/mycontroller-org/server/v2/pkg/types/task_history"
+)
+
+// RegisterTaskHistoryRoutes registers task history api
+func RegisterTaskHistoryRoutes(router *mux.Router) {
+	router.HandleFunc("/api/taskhistory", listTaskHistory).Methods(http.MethodGet)
+	router.HandleFunc("/api/taskhistory/{id}", getTaskHistory).Methods(http.MethodGet)
+}
+
+func listTaskHistory(w http.ResponseWriter, r *http.Request) {
+	handlerUtils.FindMany(w, r, types.EntityTaskHistory, &[]taskHistoryTY.Entry{})
+}
+
+func getTaskHistory(w http.ResponseWriter, r *http.Request) {
+	handlerUtils.FindOne(w, r, types.EntityTaskHistory, &taskHistoryTY.Entry{})
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	middleware "github.com/mycontroller-org/server/v2/cmd/server/app/handler/middleware"
+	handlerUtils "github.com/mycontroller-org/server/v2/cmd/server/app/handler/utils"
+	userAPI "github.com/mycontroller-org/server/v2/pkg/api/user"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	userTY "github.com/mycontroller-org/server/v2/pkg/types/user"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+)
+
+// RegisterUserRoutes registers user management api
+func RegisterUserRoutes(router *mux.Router) {
+	router.HandleFunc("/api/user", listUsers).Methods(http.MethodGet)
+	router.HandleFunc("/api/user/{id}", getUser).Methods(http.MethodGet)
+	router.HandleFunc("/api/user", updateUser).Methods(http.MethodPost)
+	router.HandleFunc("/api/user", deleteUsers).Methods(http.MethodDelete)
+}
+
+func listUsers(w http.ResponseWriter, r *http.Request) {
+	handlerUtils.FindMany(w, r, types.EntityUser, &[]userTY.User{})
+}
+
+func getUser(w http.ResponseWriter, r *http.Request) {
+	handlerUtils.FindOne(w, r, types.EntityUser, &userTY.User{})
+}
+
+func updateUser(w http.ResponseWriter, r *http.Request) {
+	entity := &userTY.User{}
+	err := handlerUtils.LoadEntity(w, r, entity)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = userAPI.SaveWithPassword(entity)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func deleteUsers(w http.ResponseWriter, r *http.Request) {
+	IDs := []string{}
+	userID := middleware.GetUserID(r)
+	updateFn := func(f []storageTY.Filter, p *storageTY.Pagination, d []byte) (interface{}, error) {
+		if len(IDs) > 0 {
+			for _, id := range IDs {
+				if id == userID {
+					return nil, errors.New("you can not delete your own account")
+				}
+			}
+			count, err := userAPI.Delete(IDs)
+			if err != nil {
+				return nil, err
+			}
+			return fmt.Sprintf("deleted: %d", count), nil
+		}
+		return nil, errors.New("supply id(s)")
+	}
+	handlerUtils.UpdateData(w, r, &IDs, updateFn)
+}
@@ -10,6 +10,7 @@ import (
 	middleware "github.com/mycontroller-org/server/v2/cmd/server/app/handler/middleware"
 	webConsole "github.com/mycontroller-org/server/v2/cmd/server/app/web-console"
 	settingsAPI "github.com/mycontroller-org/server/v2/pkg/api/settings"
+	mcGraphQL "github.com/mycontroller-org/server/v2/pkg/service/graphql"
 	virtualAssistantAPI "github.com/mycontroller-org/server/v2/pkg/service/virtual_assistant"
 	mcWS "github.com/mycontroller-org/server/v2/pkg/service/websocket"
 	"github.com/mycontroller-org/server/v2/pkg/store"
@@ -40,6 +41,7 @@ func GetHandler() (http.Handler, error) {
 	// authentication routes
 	handlerAuthAPI.RegisterAuthRoutes(router)
 	handlerAuthAPI.RegisterOAuthRoutes(router)
+	handlerAuthAPI.RegisterOIDCRoutes(router)
 
 	// other routes
 	handlerAPI.RegisterStatusRoutes(router)
@@ -62,6 +64,16 @@ func GetHandler() (http.Handler, error) {
 	handlerAPI.RegisterVirtualDeviceRoutes(router)
 	handlerAPI.RegisterVirtualAssistantRoutes(router)
 	handlerAPI.RegisterServiceTokenRoutes(router)
+	handlerAPI.RegisterRoleRoutes(router)
+	handlerAPI.RegisterUserRoutes(router)
+	handlerAPI.RegisterAuditLogRoutes(router)
+	handlerAPI.RegisterTaskHistoryRoutes(router)
+	handlerAPI.RegisterSessionRoutes(router)
+	handlerAPI.RegisterSecretRoutes(router)
+	handlerAPI.RegisterScriptModuleRoutes(router)
+	handlerAPI.RegisterSceneRoutes(router)
+	handlerAPI.RegisterBundleRoutes(router)
+	handlerAPI.RegisterOpenAPIRoutes(router)
 
 	// virtual assistants service route
 	virtualAssistantAPI.RegisterVirtualAssistantServiceRoutes(router)
@@ -69,6 +81,9 @@ func GetHandler() (http.Handler, error) {
 	// websocket router
 	mcWS.RegisterWebsocketRoutes(router)
 
+	// graphql router
+	mcGraphQL.RegisterGraphQLRoutes(router)
+
 	// add secure and insecure directories into handler
 	addFileServers(store.CFG.Directories, router)
 
@@ -96,11 +111,20 @@ func GetHandler() (http.Handler, error) {
 	})
 	withPreflight := withCors.Handler(router)
 
+	// include audit log middleware, requires the api context from the authentication middleware
+	withAuditLog := middleware.MiddlewareAuditLog(withPreflight)
+
+	// include rate limit middleware, requires the api context from the authentication middleware
+	withRateLimit := middleware.MiddlewareRateLimit(withAuditLog)
+
 	// include authentication middleware
-	withAuthentication := middleware.MiddlewareAuthenticationVerification(withPreflight)
+	withAuthentication := middleware.MiddlewareAuthenticationVerification(withRateLimit)
+
+	// include client ip rate limit middleware, limits the requests before the authentication
+	withClientIPRateLimit := middleware.MiddlewareClientIPRateLimit(withAuthentication)
 
 	// include gzip middleware
-	withGzip := gziphandler.GzipHandler(withAuthentication)
+	withGzip := gziphandler.GzipHandler(withClientIPRateLimit)
 
 	return withGzip, nil
 }
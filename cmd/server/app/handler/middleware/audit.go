@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	auditAPI "github.com/mycontroller-org/server/v2/pkg/api/audit"
+	userAPI "github.com/mycontroller-org/server/v2/pkg/api/user"
+	backupMap "github.com/mycontroller-org/server/v2/pkg/backup/bkp_map"
+	json "github.com/mycontroller-org/server/v2/pkg/json"
+	"github.com/mycontroller-org/server/v2/pkg/store"
+	"github.com/mycontroller-org/server/v2/pkg/types"
+	auditTY "github.com/mycontroller-org/server/v2/pkg/types/audit"
+	roleTY "github.com/mycontroller-org/server/v2/pkg/types/role"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+	"go.uber.org/zap"
+)
+
+const (
+	auditMaxPayloadSize = 64 * 1024 // payloads bigger than this size are not recorded
+)
+
+// statusRecorder keeps the status code of the response
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	message    bytes.Buffer
+}
+
+func (sr *statusRecorder) WriteHeader(statusCode int) {
+	sr.statusCode = statusCode
+	sr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (sr *statusRecorder) Write(data []byte) (int, error) {
+	// keep the error response to record on the audit log
+	if sr.statusCode >= http.StatusBadRequest && sr.message.Len() < auditMaxPayloadSize {
+		sr.message.Write(data)
+	}
+	return sr.ResponseWriter.Write(data)
+}
+
+// MiddlewareAuditLog records the configuration changes and the actions triggered by the users
+func MiddlewareAuditLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mcApiContext, ok := r.Context().Value(types.MC_API_CONTEXT).(*types.McApiContext)
+		if !ok || mcApiContext == nil || !strings.HasPrefix(r.URL.Path, "/api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		resource, verb := getResourceAndVerb(r)
+		if verb == roleTY.VerbRead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		entry := &auditTY.AuditLog{
+			Timestamp:      time.Now(),
+			UserID:         mcApiContext.UserID,
+			ServiceTokenID: mcApiContext.ServiceTokenID,
+			ClientIP:       GetClientIP(r),
+			Method:         r.Method,
+			Path:           r.URL.Path,
+			Resource:       resource,
+			EntityIDs:      []string{},
+			Changes:        []auditTY.Change{},
+		}
+
+		// read the payload and restore it for the actual handler
+		var payload interface{}
+		// unknown length (chunked) payloads are not recorded
+		if r.Body != nil && r.ContentLength >= 0 && r.ContentLength <= auditMaxPayloadSize && strings.Contains(r.Header.Get("Content-Type"), "json") {
+			body, err := io.ReadAll(io.LimitReader(r.Body, auditMaxPayloadSize))
+			if err != nil {
+				zap.L().Error("error on reading the request body", zap.String("path", r.URL.Path), zap.Error(err))
+			}
+			// unread part of the body, if any, left to the actual handler
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+			if len(body) > 0 {
+				if err = json.Unmarshal(body, &payload); err != nil {
+					payload = nil
+				}
+			}
+		}
+		if payload == nil && len(r.URL.Query()) > 0 {
+			payload = r.URL.Query()
+		}
+		// values of the secrets are not recorded
+		if data, ok := payload.(map[string]interface{}); ok && resource == types.EntitySecret {
+			delete(data, "value")
+		}
+
+		// get the entity ids and the existing state of the entities
+		var oldEntities map[string]map[string]interface{}
+		switch verb {
+		case roleTY.VerbUpdate:
+			entry.Operation = auditTY.OperationUpdate
+			if data, ok := payload.(map[string]interface{}); ok {
+				if id, ok := data["id"].(string); ok && id != "" {
+					entry.EntityIDs = append(entry.EntityIDs, id)
+				}
+			}
+
+		case roleTY.VerbDelete:
+			entry.Operation = auditTY.OperationDelete
+			entry.EntityIDs = append(entry.EntityIDs, toStringSlice(payload)...)
+
+		default:
+			entry.Operation = auditTY.OperationExecute
+			entry.EntityIDs = append(entry.EntityIDs, toStringSlice(payload)...)
+		}
+
+		if entry.Operation != auditTY.OperationExecute {
+			oldEntities = getEntities(resource, entry.EntityIDs)
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		entry.StatusCode = recorder.statusCode
+		if recorder.statusCode >= http.StatusBadRequest {
+			entry.Error = strings.TrimSpace(recorder.message.String())
+		}
+
+		switch entry.Operation {
+		case auditTY.OperationUpdate:
+			if len(entry.EntityIDs) > 0 && len(oldEntities) == 0 {
+				entry.Operation = auditTY.OperationCreate
+			}
+			if entry.StatusCode < http.StatusBadRequest && len(entry.EntityIDs) > 0 {
+				newEntities := getEntities(resource, entry.EntityIDs)
+				for _, id := range entry.EntityIDs {
+					entry.Changes = append(entry.Changes, auditAPI.Diff(id, oldEntities[id], newEntities[id])...)
+				}
+			} else {
+				entry.Payload = auditAPI.Mask(payload)
+			}
+
+		case auditTY.OperationDelete:
+			for id, oldEntity := range oldEntities {
+				entry.Changes = append(entry.Changes, auditAPI.Diff(id, oldEntity, nil)...)
+			}
+
+		default:
+			entry.Payload = auditAPI.Mask(payload)
+		}
+
+		if user, err := userAPI.GetByID(entry.UserID); err == nil {
+			entry.Username = user.Username
+		}
+
+		err := auditAPI.Add(entry)
+		if err != nil {
+			zap.L().Error("error on adding an audit log entry", zap.Any("entry", entry), zap.Error(err))
+		}
+	})
+}
+
+// getEntities returns the entities as map, key is the entity id
+func getEntities(resource string, IDs []string) map[string]map[string]interface{} {
+	entities := make(map[string]map[string]interface{})
+	listFn, found := backupMap.ExportMap[resource]
+	if !found || len(IDs) == 0 {
+		return entities
+	}
+
+	filters := []storageTY.Filter{{Key: types.KeyID, Operator: storageTY.OperatorIn, Value: IDs}}
+	result, err := listFn(filters, nil)
+	if err != nil {
+		zap.L().Error("error on getting entities", zap.String("resource", resource), zap.Strings("ids", IDs), zap.Error(err))
+		return entities
+	}
+
+	// convert to map with json tags
+	data, err := json.Marshal(result.Data)
+	if err != nil {
+		zap.L().Error("error on converting entities", zap.String("resource", resource), zap.Error(err))
+		return entities
+	}
+	items := make([]map[string]interface{}, 0)
+	err = json.Unmarshal(data, &items)
+	if err != nil {
+		zap.L().Error("error on converting entities", zap.String("resource", resource), zap.Error(err))
+		return entities
+	}
+	for _, item := range items {
+		if id, ok := item["id"].(string); ok {
+			entities[id] = item
+		}
+	}
+	return entities
+}
+
+// toStringSlice returns the ids from a payload
+func toStringSlice(payload interface{}) []string {
+	IDs := make([]string, 0)
+	items, ok := payload.([]interface{})
+	if !ok {
+		return IDs
+	}
+	for _, item := range items {
+		if id, ok := item.(string); ok {
+			IDs = append(IDs, id)
+		}
+	}
+	return IDs
+}
+
+// GetClientIP returns the client ip
+// the proxy headers are considered only, if the request received from a trusted proxy
+func GetClientIP(r *http.Request) string {
+	remoteIP, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remoteIP = r.RemoteAddr
+	}
+	if store.CFG == nil || len(store.CFG.Web.TrustedProxies) == 0 || !isTrustedProxy(remoteIP) {
+		return remoteIP
+	}
+
+	// the last address not belongs to a trusted proxy is the client, the leading addresses can be spoofed
+	if forwardedFor := r.Header.Get("X-Forwarded-For"); forwardedFor != "" {
+		addresses := strings.Split(forwardedFor, ",")
+		for index := len(addresses) - 1; index >= 0; index-- {
+			address := strings.TrimSpace(addresses[index])
+			if address != "" && !isTrustedProxy(address) {
+				return address
+			}
+		}
+		return strings.TrimSpace(addresses[0])
+	}
+	if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+		return strings.TrimSpace(realIP)
+	}
+	return remoteIP
+}
+
+// isTrustedProxy verifies the address against the configured trusted proxies
+func isTrustedProxy(address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range store.CFG.Web.TrustedProxies {
+		if strings.Contains(proxy, "/") {
+			_, network, err := net.ParseCIDR(proxy)
+			if err != nil {
+				zap.L().Warn("invalid trusted proxy", zap.String("proxy", proxy), zap.Error(err))
+				continue
+			}
+			if network.Contains(ip) {
+				return true
+			}
+		} else if proxyIP := net.ParseIP(proxy); proxyIP != nil && proxyIP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
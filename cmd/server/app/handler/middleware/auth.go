@@ -10,7 +10,9 @@ import (
 	"time"
 
 	handlerUtils "github.com/mycontroller-org/server/v2/cmd/server/app/handler/utils"
+	sessionAPI "github.com/mycontroller-org/server/v2/pkg/api/session"
 	"github.com/mycontroller-org/server/v2/pkg/types"
+	sessionTY "github.com/mycontroller-org/server/v2/pkg/types/session"
 	"github.com/mycontroller-org/server/v2/pkg/types/user"
 	handlerTY "github.com/mycontroller-org/server/v2/pkg/types/web_handler"
 	"github.com/mycontroller-org/server/v2/pkg/utils/convertor"
@@ -33,6 +35,7 @@ var (
 		handlerTY.InsecureShareDirWebHandlerPath, // web file insecure share api
 		"/api/oauth/login",                       // oauth login api
 		"/api/oauth/token",                       // oauth token api
+		"/api/oidc/",                             // external identity provider login api
 		"/api/plugin/gateway",                    // gateway plugin api
 	}
 )
@@ -60,12 +63,27 @@ func MiddlewareAuthenticationVerification(next http.Handler) http.Handler {
 		if isSecurePrefix {
 			for _, aPath := range nonRestrictedAPIs {
 				if strings.HasPrefix(path, aPath) {
+					// gateway plugin api can be restricted to the client certificates
+					if err := verifyGatewayClientCertificate(r); err != nil {
+						writeForbidden(w, err.Error())
+						return
+					}
 					next.ServeHTTP(w, r)
 					return
 				}
 			}
-			// authentication required
-			if err, mcApiContext := IsValidToken(r); err == nil {
+			// authentication required, with a token or with a client certificate
+			err, mcApiContext := IsValidToken(r)
+			if err != nil && getClientCertificate(r) != nil {
+				mcApiContext, err = authenticateWithClientCertificate(r)
+			}
+			if err == nil {
+				// verify the permissions
+				err = authorize(r, mcApiContext)
+				if err != nil {
+					writeForbidden(w, err.Error())
+					return
+				}
 
 				// include user details as context
 				ctx := context.WithValue(r.Context(), types.MC_API_CONTEXT, mcApiContext)
@@ -83,6 +101,16 @@ func MiddlewareAuthenticationVerification(next http.Handler) http.Handler {
 	})
 }
 
+// IsNonRestrictedAPI returns true, if the path does not require authentication
+func IsNonRestrictedAPI(path string) bool {
+	for _, aPath := range nonRestrictedAPIs {
+		if strings.HasPrefix(path, aPath) {
+			return true
+		}
+	}
+	return false
+}
+
 // steps to verify the authentication
 // 1. Verify the token in header
 // 2. Verify the token in cookie
@@ -101,6 +129,13 @@ func IsValidToken(r *http.Request) (error, *types.McApiContext) {
 		return errors.New("expired token"), nil
 	}
 
+	// verify the session is not revoked
+	// tokens issued before the session registry do not have the session id, valid till the expiry
+	sessionID, _ := claims[handlerTY.KeySessionID].(string)
+	if sessionID != "" && !sessionAPI.IsActive(sessionID) {
+		return errors.New("revoked token"), nil
+	}
+
 	// clear userID header, might be injected from external
 	// add userID into request header from here
 	r.Header.Del(handlerTY.HeaderUserID)
@@ -111,9 +146,15 @@ func IsValidToken(r *http.Request) (error, *types.McApiContext) {
 		}
 	}
 
+	svcTokenID, _ := claims[handlerTY.KeyServiceTokenID].(string)
+	twoFactorEnrol, _ := claims[handlerTY.KeyTwoFactorEnrol].(bool)
+
 	mcApiContext := types.McApiContext{
-		Tenant: "",
-		UserID: r.Header.Get(handlerTY.HeaderUserID),
+		Tenant:         "",
+		UserID:         r.Header.Get(handlerTY.HeaderUserID),
+		ServiceTokenID: svcTokenID,
+		TwoFactorEnrol: twoFactorEnrol,
+		SessionID:      sessionID,
 	}
 
 	return nil, &mcApiContext
@@ -170,13 +211,17 @@ func extractJwtToken(r *http.Request) string {
 	return ""
 }
 
-// CreateToken creates a token for a user
-func CreateToken(user user.User, expiresIn, svcTokenID string) (string, error) {
+// CreateToken creates a token for a user and registers a session
+func CreateToken(r *http.Request, user user.User, expiresIn, svcTokenID string) (string, error) {
 	atClaims := jwt.MapClaims{}
 	atClaims[handlerTY.KeyAuthorized] = true
 	atClaims[handlerTY.KeyUserID] = user.ID
 	atClaims[handlerTY.KeyFullName] = user.FullName
 	atClaims[handlerTY.KeyServiceTokenID] = svcTokenID
+	// two factor authentication enforced, but not enrolled yet
+	if svcTokenID == "" && IsTwoFactorEnrollmentRequired(&user) {
+		atClaims[handlerTY.KeyTwoFactorEnrol] = true
+	}
 
 	expiresInDuration := handlerTY.DefaultTokenExpiration
 
@@ -189,7 +234,21 @@ func CreateToken(user user.User, expiresIn, svcTokenID string) (string, error) {
 		}
 	}
 
-	atClaims[handlerTY.KeyExpiresAt] = time.Now().Add(expiresInDuration).Unix()
+	session := &sessionTY.Session{
+		UserID:         user.ID,
+		ServiceTokenID: svcTokenID,
+		ClientIP:       GetClientIP(r),
+		UserAgent:      r.UserAgent(),
+		CreatedOn:      time.Now(),
+		ExpiresOn:      time.Now().Add(expiresInDuration),
+	}
+	err := sessionAPI.Create(session)
+	if err != nil {
+		return "", err
+	}
+	atClaims[handlerTY.KeySessionID] = session.ID
+
+	atClaims[handlerTY.KeyExpiresAt] = session.ExpiresOn.Unix()
 	at := jwt.NewWithClaims(jwt.SigningMethodHS256, atClaims)
 	token, err := at.SignedString(getJwtSecret())
 	if err != nil {
@@ -198,6 +257,11 @@ func CreateToken(user user.User, expiresIn, svcTokenID string) (string, error) {
 	return token, nil
 }
 
+// IsTwoFactorEnrollmentRequired returns true, if the two factor authentication enforced and not enrolled
+func IsTwoFactorEnrollmentRequired(user *user.User) bool {
+	return user.TwoFactor.Required && !user.TwoFactor.Enabled
+}
+
 // GetUserID returns the logged in user details
 func GetUserID(r *http.Request) string {
 	return r.Header.Get(handlerTY.HeaderUserID)
@@ -207,8 +271,16 @@ func getJwtSecret() []byte {
 	return []byte(fmt.Sprintf("%s_%s", os.Getenv(handlerTY.EnvJwtAccessSecret), version.Get().HostID))
 }
 
-// doSignout clears the cookies
+// doSignout revokes the session and clears the cookies
 func doSignOut(w http.ResponseWriter, r *http.Request) {
+	if _, claims, err := getJwtToken(r); err == nil {
+		if sessionID, _ := claims[handlerTY.KeySessionID].(string); sessionID != "" {
+			if _, err = sessionAPI.Delete([]string{sessionID}); err != nil {
+				zap.L().Error("error on revoking a session", zap.String("sessionId", sessionID), zap.Error(err))
+			}
+		}
+	}
+
 	// remove cookie and redirect to authentication page
 	clearCookie := &http.Cookie{
 		Name:   handlerTY.AUTH_COOKIE_NAME,
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	gatewayAPI "github.com/mycontroller-org/server/v2/pkg/api/gateway"
+	svcTokenAPI "github.com/mycontroller-org/server/v2/pkg/api/service_token"
+	userAPI "github.com/mycontroller-org/server/v2/pkg/api/user"
+	"github.com/mycontroller-org/server/v2/pkg/store"
+	"github.com/mycontroller-org/server/v2/pkg/types"
+	configTY "github.com/mycontroller-org/server/v2/pkg/types/config"
+	handlerTY "github.com/mycontroller-org/server/v2/pkg/types/web_handler"
+)
+
+const (
+	gatewayPluginAPIPrefix = "/api/plugin/gateway/"
+)
+
+// getClientCertificate returns the verified client certificate of the request
+func getClientCertificate(r *http.Request) *x509.Certificate {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return r.TLS.VerifiedChains[0][0]
+}
+
+// isCertificateMatching verifies the certificate with the sha256 fingerprint or with the common name
+func isCertificateMatching(cert *x509.Certificate, fingerprint, commonName string) bool {
+	if fingerprint != "" {
+		return normalizeFingerprint(fingerprint) == getFingerprint(cert)
+	}
+	return commonName != "" && commonName == cert.Subject.CommonName
+}
+
+// getFingerprint returns sha256 fingerprint of the certificate in hex
+func getFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// fingerprints can be supplied with colons and in upper case
+func normalizeFingerprint(fingerprint string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(fingerprint), ":", ""))
+}
+
+// authenticateWithClientCertificate returns the api context of the user or the service token mapped to the client certificate
+func authenticateWithClientCertificate(r *http.Request) (*types.McApiContext, error) {
+	cert := getClientCertificate(r)
+	if cert == nil {
+		return nil, errors.New("client certificate not supplied")
+	}
+	if store.CFG == nil {
+		return nil, errors.New("configuration not loaded")
+	}
+
+	var mapping *configTY.ClientCertificate
+	for index := range store.CFG.Web.HttpsSSL.ClientAuth.Certificates {
+		_mapping := store.CFG.Web.HttpsSSL.ClientAuth.Certificates[index]
+		if isCertificateMatching(cert, _mapping.Fingerprint, _mapping.CommonName) {
+			mapping = &_mapping
+			break
+		}
+	}
+	if mapping == nil {
+		return nil, errors.New("client certificate not mapped")
+	}
+
+	mcApiContext := &types.McApiContext{}
+	if mapping.ServiceTokenID != "" {
+		svcToken, err := svcTokenAPI.GetByTokenID(mapping.ServiceTokenID)
+		if err != nil {
+			return nil, errors.New("invalid service token")
+		}
+		if !svcToken.NeverExpire && svcToken.ExpiresOn.Before(time.Now()) {
+			return nil, errors.New("service token expired")
+		}
+		if _, err = userAPI.GetByID(svcToken.UserID); err != nil {
+			return nil, errors.New("invalid user")
+		}
+		mcApiContext.UserID = svcToken.UserID
+		mcApiContext.ServiceTokenID = mapping.ServiceTokenID
+	} else {
+		user, err := userAPI.GetByUsername(mapping.Username)
+		if err != nil {
+			return nil, errors.New("invalid user")
+		}
+		mcApiContext.UserID = user.ID
+	}
+
+	// replace userID header, might be injected from external
+	r.Header.Set(handlerTY.HeaderUserID, mcApiContext.UserID)
+	return mcApiContext, nil
+}
+
+// verifyGatewayClientCertificate verifies the client certificate, if the gateway requires
+// gateway plugin api format: /api/plugin/gateway/{gatewayId}/...
+func verifyGatewayClientCertificate(r *http.Request) error {
+	if !strings.HasPrefix(r.URL.Path, gatewayPluginAPIPrefix) {
+		return nil
+	}
+	gatewayID := strings.SplitN(strings.TrimPrefix(r.URL.Path, gatewayPluginAPIPrefix), "/", 2)[0]
+	if gatewayID == "" {
+		return nil
+	}
+	gwCfg, err := gatewayAPI.GetByID(gatewayID)
+	if err != nil || len(gwCfg.ClientCertificates) == 0 {
+		return nil
+	}
+
+	cert := getClientCertificate(r)
+	if cert == nil {
+		return errors.New("403 Forbidden, client certificate required")
+	}
+	for _, allowed := range gwCfg.ClientCertificates {
+		if isCertificateMatching(cert, allowed, "") || isCertificateMatching(cert, "", allowed) {
+			return nil
+		}
+	}
+	return errors.New("403 Forbidden, client certificate not allowed")
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	handlerUtils "github.com/mycontroller-org/server/v2/cmd/server/app/handler/utils"
+	svcTokenAPI "github.com/mycontroller-org/server/v2/pkg/api/service_token"
+	rateLimiter "github.com/mycontroller-org/server/v2/pkg/service/rate_limiter"
+	"github.com/mycontroller-org/server/v2/pkg/store"
+	"github.com/mycontroller-org/server/v2/pkg/types"
+	svcTokenTY "github.com/mycontroller-org/server/v2/pkg/types/service_token"
+	"github.com/mycontroller-org/server/v2/pkg/utils"
+)
+
+const (
+	defaultRateLimitInterval = time.Minute
+	quotaCacheValidity       = time.Minute // service token quota reloaded after this duration
+)
+
+// quotaCache keeps the service token quotas, avoids the storage lookup on every request
+type quotaCache struct {
+	quota    svcTokenTY.Quota
+	loadedOn time.Time
+}
+
+var (
+	quotas      = make(map[string]quotaCache)
+	quotasMutex sync.Mutex
+)
+
+// MiddlewareRateLimit limits the api requests per service token, user or client ip
+// requires the api context from the authentication middleware
+func MiddlewareRateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if store.CFG == nil || !store.CFG.Web.RateLimit.Enabled || !strings.HasPrefix(r.URL.Path, "/api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cfg := store.CFG.Web.RateLimit
+		group, limit := "default", cfg.Default
+		if strings.HasPrefix(r.URL.Path, "/api/action") {
+			group, limit = "action", cfg.Action
+		} else if strings.HasPrefix(r.URL.Path, "/api/plugin/gateway") {
+			group, limit = "gateway_plugin", cfg.GatewayPlugin
+		}
+
+		// requests are counted per service token, user or client ip
+		identity := "ip:" + GetClientIP(r)
+		svcTokenID := ""
+		if mcApiContext, ok := r.Context().Value(types.MC_API_CONTEXT).(*types.McApiContext); ok && mcApiContext != nil {
+			if mcApiContext.ServiceTokenID != "" {
+				svcTokenID = mcApiContext.ServiceTokenID
+				identity = "token:" + svcTokenID
+			} else if mcApiContext.UserID != "" {
+				identity = "user:" + mcApiContext.UserID
+			}
+		}
+
+		allowed, retryAfter := allowRequest(group+":"+identity, limit.Requests, limit.Interval)
+		if allowed && svcTokenID != "" {
+			quota := getQuota(svcTokenID)
+			allowed, retryAfter = allowRequest("quota:"+svcTokenID, quota.Requests, quota.Interval)
+		}
+		if !allowed {
+			postTooManyRequests(w, retryAfter)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// MiddlewareClientIPRateLimit limits the api requests per client ip
+// placed before the authentication middleware, limits the unauthenticated requests too
+func MiddlewareClientIPRateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if store.CFG == nil || !store.CFG.Web.RateLimit.Enabled || !strings.HasPrefix(r.URL.Path, "/api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		limit := store.CFG.Web.RateLimit.ClientIP
+		allowed, retryAfter := allowRequest("client_ip:"+GetClientIP(r), limit.Requests, limit.Interval)
+		if !allowed {
+			postTooManyRequests(w, retryAfter)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func postTooManyRequests(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	handlerUtils.PostErrorResponse(w, "429 Too Many Requests, rate limit exceeded", http.StatusTooManyRequests)
+}
+
+func allowRequest(key string, requests int, interval string) (bool, time.Duration) {
+	return rateLimiter.Allow(key, requests, utils.ToDuration(interval, defaultRateLimitInterval))
+}
+
+// getQuota returns the quota of the service token
+func getQuota(tokenID string) svcTokenTY.Quota {
+	quotasMutex.Lock()
+	defer quotasMutex.Unlock()
+
+	cached, found := quotas[tokenID]
+	if found && time.Since(cached.loadedOn) < quotaCacheValidity {
+		return cached.quota
+	}
+	quota := svcTokenTY.Quota{}
+	if svcToken, err := svcTokenAPI.GetByTokenID(tokenID); err == nil {
+		quota = svcToken.Quota
+	}
+	quotas[tokenID] = quotaCache{quota: quota, loadedOn: time.Now()}
+	return quota
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	handlerUtils "github.com/mycontroller-org/server/v2/cmd/server/app/handler/utils"
+	roleAPI "github.com/mycontroller-org/server/v2/pkg/api/role"
+	svcTokenAPI "github.com/mycontroller-org/server/v2/pkg/api/service_token"
+	userAPI "github.com/mycontroller-org/server/v2/pkg/api/user"
+	"github.com/mycontroller-org/server/v2/pkg/types"
+	roleTY "github.com/mycontroller-org/server/v2/pkg/types/role"
+)
+
+var (
+	// resource of the api, first element of the path after "/api/"
+	apiResources = map[string]string{
+		"gateway":                types.EntityGateway,
+		"gateway-sleeping-queue": types.EntityGateway,
+		"node":                   types.EntityNode,
+		"source":                 types.EntitySource,
+		"field":                  types.EntityField,
+		"firmware":               types.EntityFirmware,
+		"dashboard":              types.EntityDashboard,
+		"forwardpayload":         types.EntityForwardPayload,
+		"task":                   types.EntityTask,
+		"handler":                types.EntityHandler,
+		"schedule":               types.EntitySchedule,
+		"datarepository":         types.EntityDataRepository,
+		"settings":               types.EntitySettings,
+		"virtualdevice":          types.EntityVirtualDevice,
+		"virtualassistant":       types.EntityVirtualAssistant,
+		"bot":                    types.EntityVirtualAssistant,
+		"servicetoken":           types.EntityServiceToken,
+		"user":                   types.EntityUser,
+		"role":                   types.EntityRole,
+		"audit":                  types.EntityAuditLog,
+		"taskhistory":            types.EntityTaskHistory,
+		"secret":                 types.EntitySecret,
+		"scriptmodule":           types.EntityScriptModule,
+		"scene":                  types.EntityScene,
+		"metric":                 roleTY.ResourceMetric,
+		"action":                 roleTY.ResourceAction,
+		"quickid":                roleTY.ResourceQuickID,
+		"backup":                 roleTY.ResourceBackup,
+		"restore":                roleTY.ResourceBackup,
+		"bundle":                 roleTY.ResourceBundle,
+		"ws":                     roleTY.ResourceWebsocket,
+	}
+
+	// apis allowed to all the authenticated users
+	authenticatedAPIs = []string{
+		"/api/user/profile", // profile of the logged in user
+		"/api/version",      // server version
+		"/api/session",      // sessions and service tokens of the logged in user
+		"/api/openapi.json", // api specification
+		// two factor authentication of the logged in user
+		"/api/user/twofactor/enroll",
+		"/api/user/twofactor/confirm",
+		"/api/user/twofactor/disable",
+		"/api/user/twofactor/recovery",
+	}
+
+	// apis verifies the read permissions of the resources on the resolvers
+	resourceVerifiedAPIs = []string{
+		"/api/graphql",
+	}
+
+	// apis allowed to the users, not enrolled the enforced two factor authentication
+	twoFactorEnrollmentAPIs = []string{
+		"/api/user/profile",
+		"/api/user/twofactor/enroll",
+		"/api/user/twofactor/confirm",
+	}
+
+	// path suffixes executes an operation on the resources
+	executeSuffixes = []string{"/enable", "/disable", "/reload", "/run", "/clear", "/reset", "/restore"}
+)
+
+// getResourceAndVerb returns the resource and verb of the request
+func getResourceAndVerb(r *http.Request) (string, string) {
+	path := r.URL.Path
+	resource := roleTY.ResourceSecureShare
+	if strings.HasPrefix(path, "/api/") {
+		element := strings.SplitN(strings.TrimPrefix(path, "/api/"), "/", 2)[0]
+		if mapped, found := apiResources[element]; found {
+			resource = mapped
+		} else {
+			resource = element // unknown resources, allowed only with wildcard permission
+		}
+	}
+
+	if resource == roleTY.ResourceAction || (resource == types.EntityVirtualAssistant && strings.HasPrefix(path, "/api/bot/")) {
+		return resource, roleTY.VerbExecute
+	}
+	for _, suffix := range executeSuffixes {
+		if strings.HasSuffix(path, suffix) {
+			return resource, roleTY.VerbExecute
+		}
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		// metric query uses post method
+		if resource == roleTY.ResourceMetric && path == "/api/metric" {
+			return resource, roleTY.VerbRead
+		}
+		// graphql supports only the queries
+		if path == "/api/graphql" {
+			return resource, roleTY.VerbRead
+		}
+		return resource, roleTY.VerbUpdate
+
+	case http.MethodDelete:
+		return resource, roleTY.VerbDelete
+
+	default:
+		return resource, roleTY.VerbRead
+	}
+}
+
+// authorize verifies the permissions of the user and updates the access details on the context
+func authorize(r *http.Request, mcApiContext *types.McApiContext) error {
+	if mcApiContext.TwoFactorEnrol {
+		for _, aPath := range twoFactorEnrollmentAPIs {
+			if strings.HasPrefix(r.URL.Path, aPath) {
+				return nil
+			}
+		}
+		return errors.New("403 Forbidden, two factor authentication enrollment required")
+	}
+
+	for _, aPath := range authenticatedAPIs {
+		if strings.HasPrefix(r.URL.Path, aPath) {
+			return nil
+		}
+	}
+
+	access, err := getAccess(mcApiContext)
+	if err != nil {
+		return err
+	}
+
+	for _, aPath := range resourceVerifiedAPIs {
+		if strings.HasPrefix(r.URL.Path, aPath) {
+			mcApiContext.Access = access
+			return nil
+		}
+	}
+
+	resource, verb := getResourceAndVerb(r)
+	allowed, labelSelectors := access.IsAllowed(resource, verb)
+	if !allowed {
+		return errors.New("403 Forbidden, permission denied")
+	}
+	mcApiContext.Access = access
+	mcApiContext.Resource = resource
+	mcApiContext.LabelSelectors = labelSelectors
+	return nil
+}
+
+// getAccess resolves the permissions of the user and the service token scope
+func getAccess(mcApiContext *types.McApiContext) (*roleTY.Access, error) {
+	user, err := userAPI.GetByID(mcApiContext.UserID)
+	if err != nil {
+		return nil, errors.New("invalid user")
+	}
+	permissions, err := roleAPI.GetPermissions(user.Roles)
+	if err != nil {
+		return nil, err
+	}
+	access := &roleTY.Access{Permissions: permissions}
+
+	if mcApiContext.ServiceTokenID != "" {
+		svcToken, err := svcTokenAPI.GetByTokenID(mcApiContext.ServiceTokenID)
+		if err != nil {
+			return nil, errors.New("invalid service token")
+		}
+		if !svcToken.NeverExpire && svcToken.ExpiresOn.Before(time.Now()) {
+			return nil, errors.New("service token expired")
+		}
+		if len(svcToken.Roles) > 0 {
+			scope, err := roleAPI.GetPermissions(svcToken.Roles)
+			if err != nil {
+				return nil, err
+			}
+			access.Scope = scope
+			access.HasScope = true
+		}
+	}
+	return access, nil
+}
+
+// forbidden response
+func writeForbidden(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	handlerUtils.PostErrorResponse(w, message, http.StatusForbidden)
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/gorilla/mux"
 	json "github.com/mycontroller-org/server/v2/pkg/json"
+	"github.com/mycontroller-org/server/v2/pkg/types"
+	roleTY "github.com/mycontroller-org/server/v2/pkg/types/role"
 	webHandlerTY "github.com/mycontroller-org/server/v2/pkg/types/web_handler"
 	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
 	"go.uber.org/zap"
@@ -101,11 +103,35 @@ func Params(request *http.Request) ([]storageTY.Filter, *storageTY.Pagination, e
 		filters = append(filters, *fs...)
 	}
 
+	// restrict the resources to the allowed labels
+	labelFilters, err := labelSelectorFilters(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	filters = append(filters, labelFilters...)
+
 	zap.L().Debug("received filters and pagination", zap.Any("filter", filters), zap.Any("pagination", p))
 
 	return filters, &p, nil
 }
 
+// GetApiContext returns the api context of the authenticated request
+func GetApiContext(request *http.Request) *types.McApiContext {
+	if mcApiContext, ok := request.Context().Value(types.MC_API_CONTEXT).(*types.McApiContext); ok {
+		return mcApiContext
+	}
+	return nil
+}
+
+// labelSelectorFilters returns the filters of the label restricted permissions
+func labelSelectorFilters(request *http.Request) ([]storageTY.Filter, error) {
+	mcApiContext := GetApiContext(request)
+	if mcApiContext == nil {
+		return make([]storageTY.Filter, 0), nil
+	}
+	return roleTY.LabelSelectorFilters(mcApiContext.LabelSelectors)
+}
+
 func WriteResponse(w http.ResponseWriter, data []byte) {
 	_, err := w.Write(data)
 	if err != nil {
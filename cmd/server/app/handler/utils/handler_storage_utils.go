@@ -1,11 +1,15 @@
 package handlerutils
 
 import (
+	"errors"
 	"io"
 	"net/http"
 
 	json "github.com/mycontroller-org/server/v2/pkg/json"
 	"github.com/mycontroller-org/server/v2/pkg/store"
+	"github.com/mycontroller-org/server/v2/pkg/types"
+	"github.com/mycontroller-org/server/v2/pkg/types/cmap"
+	roleTY "github.com/mycontroller-org/server/v2/pkg/types/role"
 	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
 )
 
@@ -79,6 +83,15 @@ func UpdateData(w http.ResponseWriter, r *http.Request, entity interface{}, upda
 		return
 	}
 
+	// verify the labels of the stored resources, if the operation by ids
+	if ids, ok := entity.(*[]string); ok {
+		err = verifyStoredLabels(r, *ids)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+	}
+
 	result, err := updateFn(f, p, d)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -154,5 +167,58 @@ func LoadEntity(w http.ResponseWriter, r *http.Request, entity interface{}) erro
 	if err != nil {
 		return err
 	}
+
+	// verify the labels, if the permission restricted by labels
+	mcApiContext := GetApiContext(r)
+	if mcApiContext != nil && len(mcApiContext.LabelSelectors) > 0 {
+		labelsHolder := struct {
+			ID     string               `json:"id"`
+			Labels cmap.CustomStringMap `json:"labels"`
+		}{}
+		err = json.Unmarshal(d, &labelsHolder)
+		if err != nil {
+			return err
+		}
+		if !roleTY.MatchesLabels(mcApiContext.LabelSelectors, labelsHolder.Labels) {
+			return errors.New("403 Forbidden, labels of the resource not allowed")
+		}
+		// the stored resource should be allowed too, labels can not be used to take over a resource
+		if labelsHolder.ID != "" {
+			err = verifyStoredLabels(r, []string{labelsHolder.ID})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// verifyStoredLabels verifies the labels of the stored resources against the label selectors of the request
+// not available ids are ignored
+func verifyStoredLabels(r *http.Request, ids []string) error {
+	mcApiContext := GetApiContext(r)
+	if mcApiContext == nil || len(mcApiContext.LabelSelectors) == 0 || len(ids) == 0 {
+		return nil
+	}
+
+	labelFilters, err := labelSelectorFilters(r)
+	if err != nil {
+		return err
+	}
+
+	idFilters := []storageTY.Filter{{Key: types.KeyID, Operator: storageTY.OperatorIn, Value: ids}}
+	pagination := &storageTY.Pagination{Limit: 1}
+
+	available, err := store.STORAGE.Find(mcApiContext.Resource, &[]interface{}{}, idFilters, pagination)
+	if err != nil {
+		return err
+	}
+	allowed, err := store.STORAGE.Find(mcApiContext.Resource, &[]interface{}{}, append(idFilters, labelFilters...), pagination)
+	if err != nil {
+		return err
+	}
+	if allowed.Count != available.Count {
+		return errors.New("403 Forbidden, labels of the resource not allowed")
+	}
 	return nil
 }
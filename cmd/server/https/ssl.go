@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"os"
 	"time"
 
 	"github.com/mycontroller-org/server/v2/pkg/types/config"
@@ -61,9 +62,45 @@ func GetSSLTLSConfig(cfg config.HttpsSSLConfig) (*tls.Config, error) {
 
 	tlsConfig.Certificates[0] = certificate
 
+	err = updateClientAuth(tlsConfig, cfg.ClientAuth)
+	if err != nil {
+		return nil, err
+	}
+
 	return tlsConfig, nil
 }
 
+// updateClientAuth enables the client certificate verification
+func updateClientAuth(tlsConfig *tls.Config, cfg config.ClientAuthConfig) error {
+	switch cfg.Mode {
+	case "", config.ClientAuthNone:
+		return nil
+
+	case config.ClientAuthOptional:
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+
+	case config.ClientAuthRequired:
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+
+	default:
+		return fmt.Errorf("invalid client_auth mode:%s", cfg.Mode)
+	}
+
+	if cfg.CAFile == "" {
+		return errors.New("client_auth ca_file is missing")
+	}
+	caBytes, err := os.ReadFile(cfg.CAFile)
+	if err != nil {
+		return err
+	}
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caBytes) {
+		return fmt.Errorf("no valid certificates found in the ca_file:%s", cfg.CAFile)
+	}
+	tlsConfig.ClientCAs = caPool
+	return nil
+}
+
 // generateSSLCert generates ssl certificate and key
 func generateSSLCert(certDir string) error {
 	// check the certificate on disk, if available use it and skip the following steps
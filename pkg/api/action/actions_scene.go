@@ -0,0 +1,66 @@
+package action
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	sceneAPI "github.com/mycontroller-org/server/v2/pkg/api/scene"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	sceneTY "github.com/mycontroller-org/server/v2/pkg/types/scene"
+	"go.uber.org/zap"
+)
+
+// avoids mixing the values of the concurrent restore requests
+var sceneMutex sync.Mutex
+
+// toScene executes snapshot or restore on a scene
+// "true"(enable) on virtual assistants restores the scene, "false"(disable) ignored
+func toScene(id, action string) error {
+	action = types.GetAction(action)
+	switch action {
+	case sceneTY.ActionSnapshot:
+		_, err := sceneAPI.Snapshot(id)
+		return err
+
+	case sceneTY.ActionRestore, types.ActionEnable:
+		return restoreScene(id)
+
+	case types.ActionDisable:
+		return nil
+
+	default:
+		return fmt.Errorf("unknown action:%s", action)
+	}
+}
+
+// restoreScene sends the captured values to the fields
+// all the fields resolved before sending, nothing sent if any of the field is not available
+// values are sent field by field, on a failure the restored fields are not reverted,
+// hence the error reports the restored and the failed fields
+func restoreScene(id string) error {
+	sceneMutex.Lock()
+	defer sceneMutex.Unlock()
+
+	fields, values, err := sceneAPI.GetSnapshotFields(id)
+	if err != nil {
+		return err
+	}
+
+	restored := make([]string, 0)
+	errs := make([]string, 0)
+	for index, field := range fields {
+		err = toField(field.GatewayID, field.NodeID, field.SourceID, field.FieldID, values[index])
+		if err != nil {
+			zap.L().Error("error on restoring a scene field", zap.String("sceneId", id), zap.String("fieldId", field.ID), zap.Error(err))
+			errs = append(errs, fmt.Sprintf("%s: %s", field.ID, err.Error()))
+			continue
+		}
+		restored = append(restored, field.ID)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("scene '%s' partially restored, restored:[%s], failed:[%s]", id, strings.Join(restored, ", "), strings.Join(errs, ", "))
+	}
+	zap.L().Debug("scene restored", zap.String("sceneId", id), zap.Strings("fields", restored))
+	return nil
+}
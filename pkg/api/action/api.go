@@ -7,6 +7,7 @@ import (
 	fieldAPI "github.com/mycontroller-org/server/v2/pkg/api/field"
 	gatewayAPI "github.com/mycontroller-org/server/v2/pkg/api/gateway"
 	nodeAPI "github.com/mycontroller-org/server/v2/pkg/api/node"
+	sceneAPI "github.com/mycontroller-org/server/v2/pkg/api/scene"
 	scheduleAPI "github.com/mycontroller-org/server/v2/pkg/api/schedule"
 	taskAPI "github.com/mycontroller-org/server/v2/pkg/api/task"
 	"github.com/mycontroller-org/server/v2/pkg/service/mcbus"
@@ -15,6 +16,7 @@ import (
 	fieldTY "github.com/mycontroller-org/server/v2/pkg/types/field"
 	msgTY "github.com/mycontroller-org/server/v2/pkg/types/message"
 	nodeTY "github.com/mycontroller-org/server/v2/pkg/types/node"
+	sceneTY "github.com/mycontroller-org/server/v2/pkg/types/scene"
 	scheduleTY "github.com/mycontroller-org/server/v2/pkg/types/schedule"
 	taskTY "github.com/mycontroller-org/server/v2/pkg/types/task"
 	"github.com/mycontroller-org/server/v2/pkg/utils"
@@ -83,6 +85,9 @@ func ExecuteActionOnResourceByQuickID(data *handlerTY.ResourceData) error {
 	case utils.ContainsString(quickIdUtils.QuickIDDataRepository, resourceType):
 		return toDataRepository(kvMap[types.KeyID], data.KeyPath, data.Payload)
 
+	case utils.ContainsString(quickIdUtils.QuickIDScene, resourceType):
+		return toScene(kvMap[types.KeyID], data.Payload)
+
 	default:
 		return fmt.Errorf("unknown resource type: %s", resourceType)
 	}
@@ -186,6 +191,23 @@ func ExecuteActionOnResourceByLabels(data *handlerTY.ResourceData) error {
 			}
 		}
 
+	case utils.ContainsString(quickIdUtils.QuickIDScene, data.ResourceType):
+		result, err := sceneAPI.List(filters, pagination)
+		if err != nil {
+			return err
+		}
+		if result.Count == 0 {
+			return nil
+		}
+		items := result.Data.(*[]sceneTY.Config)
+		for index := 0; index < len(*items); index++ {
+			item := (*items)[index]
+			err = toScene(item.ID, data.Payload)
+			if err != nil {
+				zap.L().Error("error on sending data", zap.Error(err), zap.String("sceneID", item.ID), zap.String("payload", data.Payload))
+			}
+		}
+
 	default:
 		return fmt.Errorf("unknown resource type: %s", data.ResourceType)
 	}
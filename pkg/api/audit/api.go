@@ -0,0 +1,56 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/mycontroller-org/server/v2/pkg/store"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	auditTY "github.com/mycontroller-org/server/v2/pkg/types/audit"
+	"github.com/mycontroller-org/server/v2/pkg/utils"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultRetention = "720h" // 30 days
+)
+
+// List by filter and pagination
+func List(filters []storageTY.Filter, pagination *storageTY.Pagination) (*storageTY.Result, error) {
+	result := make([]auditTY.AuditLog, 0)
+	return store.STORAGE.Find(types.EntityAuditLog, &result, filters, pagination)
+}
+
+// Add a audit log entry
+func Add(entry *auditTY.AuditLog) error {
+	if entry.ID == "" {
+		entry.ID = utils.RandUUID()
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+	return store.STORAGE.Insert(types.EntityAuditLog, entry)
+}
+
+// Save a audit log entry, used on restore
+func Save(entry *auditTY.AuditLog) error {
+	if entry.ID == "" {
+		entry.ID = utils.RandUUID()
+	}
+	filters := []storageTY.Filter{
+		{Key: types.KeyID, Value: entry.ID},
+	}
+	return store.STORAGE.Upsert(types.EntityAuditLog, entry, filters)
+}
+
+// Purge removes the entries older than the retention duration
+func Purge(retention time.Duration) (int64, error) {
+	olderThan := time.Now().Add(-retention)
+	filters := []storageTY.Filter{{Key: types.KeyTimestamp, Operator: storageTY.OperatorLessThan, Value: olderThan}}
+	deleted, err := store.STORAGE.Delete(types.EntityAuditLog, filters)
+	if err != nil {
+		return 0, err
+	}
+	zap.L().Debug("purged audit log entries", zap.Int64("deleted", deleted), zap.String("retention", retention.String()))
+	return deleted, nil
+}
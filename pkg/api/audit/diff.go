@@ -0,0 +1,113 @@
+package audit
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	auditTY "github.com/mycontroller-org/server/v2/pkg/types/audit"
+	cloneUtils "github.com/mycontroller-org/server/v2/pkg/utils/clone"
+)
+
+const (
+	maskedValue = "***"
+)
+
+// fields not reported on the diff, updated on every save
+var ignoredFields = map[string]bool{
+	"modifiedOn": true,
+}
+
+// Diff returns the changes between the old and new version of an entity
+// nested maps reported with dot separated path, slices reported as a single value
+func Diff(entityID string, oldData, newData map[string]interface{}) []auditTY.Change {
+	oldValues := make(map[string]interface{})
+	newValues := make(map[string]interface{})
+	flatten("", oldData, oldValues)
+	flatten("", newData, newValues)
+
+	paths := make([]string, 0)
+	for path := range oldValues {
+		paths = append(paths, path)
+	}
+	for path := range newValues {
+		if _, found := oldValues[path]; !found {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	changes := make([]auditTY.Change, 0)
+	for _, path := range paths {
+		oldValue := oldValues[path]
+		newValue := newValues[path]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		if isSecret(path) {
+			oldValue, newValue = mask(oldValue), mask(newValue)
+		}
+		changes = append(changes, auditTY.Change{EntityID: entityID, Path: path, Old: oldValue, New: newValue})
+	}
+	return changes
+}
+
+// Mask replaces the secret values of the data
+func Mask(data interface{}) interface{} {
+	switch value := data.(type) {
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			if isSecret(key) {
+				masked[key] = mask(item)
+			} else {
+				masked[key] = Mask(item)
+			}
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			masked = append(masked, Mask(item))
+		}
+		return masked
+	default:
+		return data
+	}
+}
+
+func flatten(prefix string, data map[string]interface{}, out map[string]interface{}) {
+	for key, value := range data {
+		if prefix == "" && ignoredFields[key] {
+			continue
+		}
+		path := key
+		if prefix != "" {
+			path = fmt.Sprintf("%s.%s", prefix, key)
+		}
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flatten(path, nested, out)
+			continue
+		}
+		out[path] = Mask(value)
+	}
+}
+
+// isSecret returns true, if the last element of the path is a secret key
+func isSecret(path string) bool {
+	key := strings.ToLower(path[strings.LastIndex(path, ".")+1:])
+	for _, specialKey := range cloneUtils.DefaultSpecialKeys {
+		if key == specialKey {
+			return true
+		}
+	}
+	return false
+}
+
+func mask(value interface{}) interface{} {
+	if value == nil || value == "" {
+		return value
+	}
+	return maskedValue
+}
@@ -0,0 +1,259 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+
+	dataRepositoryAPI "github.com/mycontroller-org/server/v2/pkg/api/data_repository"
+	fwdPayloadAPI "github.com/mycontroller-org/server/v2/pkg/api/forward_payload"
+	handlerAPI "github.com/mycontroller-org/server/v2/pkg/api/handler"
+	scheduleAPI "github.com/mycontroller-org/server/v2/pkg/api/schedule"
+	taskAPI "github.com/mycontroller-org/server/v2/pkg/api/task"
+	"github.com/mycontroller-org/server/v2/pkg/json"
+	"github.com/mycontroller-org/server/v2/pkg/store"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	bundleTY "github.com/mycontroller-org/server/v2/pkg/types/bundle"
+	"github.com/mycontroller-org/server/v2/pkg/types/cmap"
+	roleTY "github.com/mycontroller-org/server/v2/pkg/types/role"
+	scheduleTY "github.com/mycontroller-org/server/v2/pkg/types/schedule"
+	taskTY "github.com/mycontroller-org/server/v2/pkg/types/task"
+	cloneUtil "github.com/mycontroller-org/server/v2/pkg/utils/clone"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// entity of the bundle with the storage functions
+type entity struct {
+	entityType string
+	id         string
+	item       interface{}
+	get        func(id string) (interface{}, error)
+	save       func() error
+}
+
+// Parse loads the bundle from yaml or json
+func Parse(data []byte) (*bundleTY.Bundle, error) {
+	bundle := &bundleTY.Bundle{}
+	err := yaml.Unmarshal(data, bundle)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle, error:%s", err.Error())
+	}
+	return bundle, nil
+}
+
+// Apply validates the bundle and saves the created and updated entities
+// entities are not saved on dry run or if there is a validation error
+// on a save failure, the remaining entities are not saved and the saved changes are marked as applied
+func Apply(mcApiContext *types.McApiContext, bundle *bundleTY.Bundle, dryRun bool) (*bundleTY.ApplyResult, error) {
+	result := &bundleTY.ApplyResult{
+		DryRun:   dryRun,
+		Changes:  make([]bundleTY.Change, 0),
+		Errors:   make([]string, 0),
+		Warnings: make([]string, 0),
+	}
+
+	validate(bundle, result)
+
+	entities := getEntities(bundle)
+	changed := make(map[int]entity) // index of the change => entity
+	for _, item := range entities {
+		if item.id == "" {
+			continue // reported on the validation
+		}
+		change, existing, err := getChange(item)
+		if err != nil {
+			return nil, err
+		}
+		verifyAccess(mcApiContext, item, existing, result)
+		result.Changes = append(result.Changes, *change)
+		if change.Action != bundleTY.ActionUnchanged {
+			changed[len(result.Changes)-1] = item
+		}
+	}
+
+	if dryRun || len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	for index := range result.Changes {
+		item, found := changed[index]
+		if !found {
+			continue
+		}
+		err := item.save()
+		if err != nil {
+			zap.L().Error("error on applying a bundle entity", zap.String("entityType", item.entityType), zap.String("id", item.id), zap.Error(err))
+			result.Errors = append(result.Errors, fmt.Sprintf("error on saving %s '%s', error:%s", item.entityType, item.id, err.Error()))
+			return result, nil
+		}
+		result.Changes[index].Applied = true
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// verifyAccess verifies the update permission of the entity
+// on label restricted permission, the labels of the bundle entity and the stored entity should be allowed
+func verifyAccess(mcApiContext *types.McApiContext, item entity, existing interface{}, result *bundleTY.ApplyResult) {
+	if mcApiContext == nil || mcApiContext.Access == nil {
+		return
+	}
+	allowed, labelSelectors := mcApiContext.Access.IsAllowed(item.entityType, roleTY.VerbUpdate)
+	if !allowed {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s '%s': permission denied", item.entityType, item.id))
+		return
+	}
+	if len(labelSelectors) == 0 {
+		return
+	}
+	if !roleTY.MatchesLabels(labelSelectors, getLabels(item.item)) ||
+		(existing != nil && !roleTY.MatchesLabels(labelSelectors, getLabels(existing))) {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s '%s': labels of the resource not allowed", item.entityType, item.id))
+	}
+}
+
+// getLabels returns the labels of the entity
+func getLabels(item interface{}) cmap.CustomStringMap {
+	labelsHolder := struct {
+		Labels cmap.CustomStringMap `json:"labels"`
+	}{}
+	data, err := json.Marshal(item)
+	if err != nil {
+		return nil
+	}
+	if err = json.Unmarshal(data, &labelsHolder); err != nil {
+		return nil
+	}
+	return labelsHolder.Labels
+}
+
+// returns the entities in the apply order, referenced entities saved first
+func getEntities(bundle *bundleTY.Bundle) []entity {
+	entities := make([]entity, 0)
+
+	for index := range bundle.Handlers {
+		cfg := &bundle.Handlers[index]
+		entities = append(entities, entity{
+			entityType: types.EntityHandler,
+			id:         cfg.ID,
+			item:       cfg,
+			get: func(id string) (interface{}, error) {
+				existing, err := handlerAPI.GetByID(id)
+				if err != nil {
+					return nil, err
+				}
+				// compare with the decrypted values
+				err = cloneUtil.UpdateSecrets(existing, store.CFG.Secret, "", false, cloneUtil.DefaultSpecialKeys)
+				return existing, err
+			},
+			save: func() error { return handlerAPI.SaveAndReload(cfg) },
+		})
+	}
+
+	for index := range bundle.DataRepositories {
+		cfg := &bundle.DataRepositories[index]
+		entities = append(entities, entity{
+			entityType: types.EntityDataRepository,
+			id:         cfg.ID,
+			item:       cfg,
+			get: func(id string) (interface{}, error) {
+				existing, err := dataRepositoryAPI.GetByID(id)
+				if err != nil {
+					return nil, err
+				}
+				err = cloneUtil.UpdateSecrets(existing, store.CFG.Secret, "", false, cloneUtil.DefaultSpecialKeys)
+				return existing, err
+			},
+			save: func() error { return dataRepositoryAPI.Save(cfg) },
+		})
+	}
+
+	for index := range bundle.ForwardPayloads {
+		cfg := &bundle.ForwardPayloads[index]
+		entities = append(entities, entity{
+			entityType: types.EntityForwardPayload,
+			id:         cfg.ID,
+			item:       cfg,
+			get: func(id string) (interface{}, error) {
+				return fwdPayloadAPI.Get([]storageTY.Filter{{Key: types.KeyID, Value: id}})
+			},
+			save: func() error { return fwdPayloadAPI.Save(cfg) },
+		})
+	}
+
+	for index := range bundle.Schedules {
+		cfg := &bundle.Schedules[index]
+		entities = append(entities, entity{
+			entityType: types.EntitySchedule,
+			id:         cfg.ID,
+			item:       cfg,
+			get:        func(id string) (interface{}, error) { return scheduleAPI.GetByID(id) },
+			save: func() error {
+				// keep the state of the existing schedule
+				cfg.State = &scheduleTY.State{}
+				if existing, err := scheduleAPI.GetByID(cfg.ID); err == nil && existing.State != nil {
+					cfg.State = existing.State
+				}
+				err := scheduleAPI.Save(cfg)
+				if err != nil {
+					return err
+				}
+				return scheduleAPI.Reload([]string{cfg.ID})
+			},
+		})
+	}
+
+	for index := range bundle.Tasks {
+		cfg := &bundle.Tasks[index]
+		entities = append(entities, entity{
+			entityType: types.EntityTask,
+			id:         cfg.ID,
+			item:       cfg,
+			get:        func(id string) (interface{}, error) { return taskAPI.GetByID(id) },
+			save: func() error {
+				// keep the state of the existing task
+				cfg.State = &taskTY.State{}
+				if existing, err := taskAPI.GetByID(cfg.ID); err == nil && existing.State != nil {
+					cfg.State = existing.State
+				}
+				err := taskAPI.Save(cfg)
+				if err != nil {
+					return err
+				}
+				return taskAPI.Reload([]string{cfg.ID})
+			},
+		})
+	}
+
+	return entities
+}
+
+// compares the bundle entity with the stored entity, returns the stored entity too
+func getChange(item entity) (*bundleTY.Change, interface{}, error) {
+	change := &bundleTY.Change{
+		EntityType: item.entityType,
+		ID:         item.id,
+		Diff:       make([]string, 0),
+	}
+
+	existing, err := item.get(item.id)
+	if err != nil {
+		if errors.Is(err, storageTY.ErrNoDocuments) {
+			change.Action = bundleTY.ActionCreate
+			return change, nil, nil
+		}
+		return nil, nil, fmt.Errorf("error on getting %s '%s', error:%s", item.entityType, item.id, err.Error())
+	}
+
+	diff, err := getDiff(existing, item.item)
+	if err != nil {
+		return nil, nil, err
+	}
+	change.Diff = diff
+	change.Action = bundleTY.ActionUnchanged
+	if len(diff) > 0 {
+		change.Action = bundleTY.ActionUpdate
+	}
+	return change, existing, nil
+}
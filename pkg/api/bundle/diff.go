@@ -0,0 +1,119 @@
+package bundle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mycontroller-org/server/v2/pkg/json"
+	cloneUtil "github.com/mycontroller-org/server/v2/pkg/utils/clone"
+	converterUtils "github.com/mycontroller-org/server/v2/pkg/utils/convertor"
+)
+
+// keys not managed by the bundle
+var ignoredKeys = []string{"modifiedOn", "state"}
+
+const maskedValue = "***"
+
+// getDiff returns the changed key paths, ex: "spec.interval: 10s => 30s"
+func getDiff(existing, updated interface{}) ([]string, error) {
+	existingMap, err := flatten(existing)
+	if err != nil {
+		return nil, err
+	}
+	updatedMap, err := flatten(updated)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]bool)
+	for key := range existingMap {
+		keys[key] = true
+	}
+	for key := range updatedMap {
+		keys[key] = true
+	}
+
+	diff := make([]string, 0)
+	for key := range keys {
+		oldValue, oldFound := existingMap[key]
+		newValue, newFound := updatedMap[key]
+		if oldValue == newValue || (isEmpty(oldValue) && isEmpty(newValue)) {
+			continue
+		}
+		if !oldFound {
+			oldValue = "<none>"
+		}
+		if !newFound {
+			newValue = "<none>"
+		}
+		if isSecretKey(key) {
+			oldValue, newValue = maskedValue, maskedValue
+		}
+		diff = append(diff, fmt.Sprintf("%s: %s => %s", key, oldValue, newValue))
+	}
+	sort.Strings(diff)
+	return diff, nil
+}
+
+// flatten converts the entity to key path and value map
+func flatten(entity interface{}) (map[string]string, error) {
+	dataBytes, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string]interface{})
+	err = json.Unmarshal(dataBytes, &data)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range ignoredKeys {
+		delete(data, key)
+	}
+
+	flattened := make(map[string]string)
+	flattenValue("", data, flattened)
+	return flattened, nil
+}
+
+func flattenValue(path string, value interface{}, flattened map[string]string) {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for key, item := range typedValue {
+			flattenValue(joinKey(path, key), item, flattened)
+		}
+
+	case []interface{}:
+		for index, item := range typedValue {
+			flattenValue(joinKey(path, fmt.Sprintf("%d", index)), item, flattened)
+		}
+
+	case nil:
+
+	default:
+		flattened[path] = converterUtils.ToString(typedValue)
+	}
+}
+
+// missing and zero values are not reported as a change
+func isEmpty(value string) bool {
+	return value == "" || value == "false" || value == "0"
+}
+
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func isSecretKey(path string) bool {
+	keys := strings.Split(strings.ToLower(path), ".")
+	key := keys[len(keys)-1]
+	for _, specialKey := range cloneUtil.DefaultSpecialKeys {
+		if key == specialKey {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	handlerAPI "github.com/mycontroller-org/server/v2/pkg/api/handler"
+	quickIdAPI "github.com/mycontroller-org/server/v2/pkg/api/quickid"
+	"github.com/mycontroller-org/server/v2/pkg/json"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	bundleTY "github.com/mycontroller-org/server/v2/pkg/types/bundle"
+	scheduleTY "github.com/mycontroller-org/server/v2/pkg/types/schedule"
+	taskTY "github.com/mycontroller-org/server/v2/pkg/types/task"
+	"github.com/mycontroller-org/server/v2/pkg/utils/expression"
+	"github.com/mycontroller-org/server/v2/pkg/utils/javascript"
+	quickIdUtils "github.com/mycontroller-org/server/v2/pkg/utils/quick_id"
+	yamlUtils "github.com/mycontroller-org/server/v2/pkg/utils/yaml"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+	handlerTY "github.com/mycontroller-org/server/v2/plugin/handler/types"
+)
+
+// validator keeps the ids available on the bundle
+type validator struct {
+	result *bundleTY.ApplyResult
+	ids    map[string]map[string]bool // entity type => ids
+}
+
+func (v *validator) addError(format string, args ...interface{}) {
+	v.result.Errors = append(v.result.Errors, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) addWarning(format string, args ...interface{}) {
+	v.result.Warnings = append(v.result.Warnings, fmt.Sprintf(format, args...))
+}
+
+// validate verifies the entities and the references, updates the issues on the result
+func validate(bundle *bundleTY.Bundle, result *bundleTY.ApplyResult) {
+	v := &validator{result: result, ids: make(map[string]map[string]bool)}
+
+	if bundle.APIVersion != bundleTY.APIVersionV1 {
+		v.addError("unsupported apiVersion '%s', supported: %s", bundle.APIVersion, bundleTY.APIVersionV1)
+		return
+	}
+
+	for _, cfg := range bundle.Handlers {
+		v.addID(types.EntityHandler, cfg.ID)
+		if cfg.Type == "" {
+			v.addError("handler '%s': type can not be empty", cfg.ID)
+		}
+	}
+	for _, cfg := range bundle.DataRepositories {
+		v.addID(types.EntityDataRepository, cfg.ID)
+	}
+	for _, cfg := range bundle.ForwardPayloads {
+		v.addID(types.EntityForwardPayload, cfg.ID)
+	}
+	for _, cfg := range bundle.Schedules {
+		v.addID(types.EntitySchedule, cfg.ID)
+	}
+	for _, cfg := range bundle.Tasks {
+		v.addID(types.EntityTask, cfg.ID)
+	}
+
+	for _, cfg := range bundle.ForwardPayloads {
+		v.validateQuickID(fmt.Sprintf("forward payload '%s': srcFieldId", cfg.ID), cfg.SrcFieldID)
+		v.validateQuickID(fmt.Sprintf("forward payload '%s': dstFieldId", cfg.ID), cfg.DstFieldID)
+	}
+
+	for index := range bundle.Schedules {
+		v.validateSchedule(&bundle.Schedules[index])
+	}
+
+	for index := range bundle.Tasks {
+		v.validateTask(&bundle.Tasks[index])
+	}
+}
+
+// addID reports the empty and the duplicate ids
+func (v *validator) addID(entityType, id string) {
+	if id == "" {
+		v.addError("%s: id can not be empty", entityType)
+		return
+	}
+	if v.ids[entityType] == nil {
+		v.ids[entityType] = make(map[string]bool)
+	}
+	if v.ids[entityType][id] {
+		v.addError("%s '%s': duplicate id", entityType, id)
+	}
+	v.ids[entityType][id] = true
+}
+
+func (v *validator) validateSchedule(cfg *scheduleTY.Config) {
+	name := fmt.Sprintf("schedule '%s'", cfg.ID)
+	if err := cfg.Validate(); err != nil {
+		v.addError("%s: %s", name, err.Error())
+	}
+	if cfg.CustomVariableType == scheduleTY.CustomVariableTypeJavascript {
+		v.validateJavascript(name, cfg.CustomVariableConfig.Javascript)
+	}
+	v.validateHandlers(name, cfg.Handlers)
+	v.validateVariables(name, cfg.Variables)
+}
+
+func (v *validator) validateTask(cfg *taskTY.Config) {
+	name := fmt.Sprintf("task '%s'", cfg.ID)
+	if err := cfg.Validate(); err != nil {
+		v.addError("%s: %s", name, err.Error())
+	}
+	if cfg.EvaluationType == taskTY.EvaluationTypeJavascript {
+		v.validateJavascript(name, cfg.EvaluationConfig.Javascript)
+	} else if cfg.EvaluationType == taskTY.EvaluationTypeExpression {
+		if _, err := expression.Compile(cfg.EvaluationConfig.Expression, cfg.ExpressionVariableNames()); err != nil {
+			v.addError("%s: invalid expression, %s", name, err.Error())
+		}
+	}
+	v.validateHandlers(name, cfg.Handlers)
+	v.validateActionHandlers(name, cfg.Actions)
+	v.validateVariables(name, cfg.Variables)
+}
+
+func (v *validator) validateActionHandlers(name string, actions []taskTY.Action) {
+	for _, action := range actions {
+		v.validateHandlers(name, action.Handlers)
+		v.validateHandlers(name, action.OnFailure)
+		v.validateActionHandlers(name, action.Then)
+		v.validateActionHandlers(name, action.Else)
+	}
+}
+
+func (v *validator) validateJavascript(name, script string) {
+	if err := javascript.Validate(script); err != nil {
+		v.addError("%s: invalid javascript, %s", name, err.Error())
+	}
+}
+
+// validateHandlers verifies the handlers available on the bundle or on the storage
+func (v *validator) validateHandlers(name string, handlers []string) {
+	for _, handlerID := range handlers {
+		if v.ids[types.EntityHandler][handlerID] {
+			continue
+		}
+		_, err := handlerAPI.GetByID(handlerID)
+		if err != nil {
+			if errors.Is(err, storageTY.ErrNoDocuments) {
+				v.addError("%s: handler '%s' not available", name, handlerID)
+			} else {
+				v.addError("%s: error on getting handler '%s', %s", name, handlerID, err.Error())
+			}
+		}
+	}
+}
+
+// validateVariables verifies the quick ids of the resource variables
+func (v *validator) validateVariables(name string, variables map[string]string) {
+	for variableName, value := range variables {
+		genericData := handlerTY.GenericData{}
+		if err := json.Unmarshal([]byte(value), &genericData); err != nil {
+			continue // plain or templated string
+		}
+		if !strings.HasPrefix(genericData.Type, handlerTY.DataTypeResource) {
+			continue
+		}
+		rsData := handlerTY.ResourceData{}
+		if err := yamlUtils.UnmarshalBase64Yaml(genericData.Data, &rsData); err != nil {
+			v.addError("%s: variable '%s': invalid resource data, %s", name, variableName, err.Error())
+			continue
+		}
+		if rsData.QuickID != "" {
+			quickID := fmt.Sprintf("%s:%s", rsData.ResourceType, rsData.QuickID)
+			v.validateQuickID(fmt.Sprintf("%s: variable '%s'", name, variableName), quickID)
+		}
+	}
+}
+
+// validateQuickID reports invalid quick id as an error and missing resource as a warning
+// resources like fields may appear later
+func (v *validator) validateQuickID(name, quickID string) {
+	resourceType, keys, err := quickIdUtils.EntityKeyValueMap(quickID)
+	if err != nil {
+		v.addError("%s: %s", name, err.Error())
+		return
+	}
+	if v.ids[resourceType][keys[types.KeyID]] {
+		return
+	}
+	if _, err := quickIdAPI.GetResources([]string{quickID}); err != nil {
+		v.addWarning("%s: resource '%s' not available, %s", name, quickID, err.Error())
+	}
+}
@@ -0,0 +1,82 @@
+package metric
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	fieldAPI "github.com/mycontroller-org/server/v2/pkg/api/field"
+	metricSVC "github.com/mycontroller-org/server/v2/pkg/service/database/metric"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	fieldTY "github.com/mycontroller-org/server/v2/pkg/types/field"
+	"github.com/mycontroller-org/server/v2/pkg/utils"
+	quickIdUL "github.com/mycontroller-org/server/v2/pkg/utils/quick_id"
+	metricTY "github.com/mycontroller-org/server/v2/plugin/database/metric/types"
+)
+
+// export and import formats
+const (
+	FormatCSV          = "csv"
+	FormatJSON         = "json"
+	FormatLineProtocol = "line_protocol"
+)
+
+// GetField returns the field of the quick id
+func GetField(quickID string) (*fieldTY.Field, error) {
+	resourceType, kvMap, err := quickIdUL.EntityKeyValueMap(quickID)
+	if err != nil {
+		return nil, err
+	}
+	if !utils.ContainsString(quickIdUL.QuickIDField, resourceType) {
+		return nil, fmt.Errorf("resource type not supported in metric. ResourceType:%s", resourceType)
+	}
+	return fieldAPI.GetByIDs(kvMap[types.KeyGatewayID], kvMap[types.KeyNodeID], kvMap[types.KeySourceID], kvMap[types.KeyFieldID])
+}
+
+// fieldTags returns the tags used on the metric database for a field
+func fieldTags(field *fieldTY.Field) map[string]string {
+	return map[string]string{
+		types.KeyID:        field.ID,
+		types.KeyGatewayID: field.GatewayID,
+		types.KeyNodeID:    field.NodeID,
+		types.KeySourceID:  field.SourceID,
+		types.KeyFieldID:   field.FieldID,
+	}
+}
+
+// fieldQuery returns the metric query for a field
+func fieldQuery(field *fieldTY.Field, query *metricTY.Query) *metricTY.QueryConfig {
+	individual := query.Clone()
+	individual.Name = field.ID
+	individual.MetricType = field.MetricType
+	individual.Tags = map[string]string{types.KeyID: field.ID}
+	individual.Policy = metricSVC.GetPolicyName(field.MetricType, field.Labels)
+	return &metricTY.QueryConfig{Individual: []metricTY.Query{individual}}
+}
+
+// parseTimestamp supports RFC3339 and unix epoch in seconds, milliseconds, microseconds or nanoseconds
+func parseTimestamp(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("empty timestamp")
+	}
+	if parsed, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return parsed, nil
+	}
+	epoch, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp:%s, supported formats: RFC3339, unix epoch", value)
+	}
+	// detect the epoch unit from the length
+	switch {
+	case epoch < 1e11:
+		return time.Unix(epoch, 0), nil
+	case epoch < 1e14:
+		return time.UnixMilli(epoch), nil
+	case epoch < 1e17:
+		return time.UnixMicro(epoch), nil
+	default:
+		return time.Unix(0, epoch), nil
+	}
+}
@@ -0,0 +1,135 @@
+package metric
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mycontroller-org/server/v2/pkg/json"
+	"github.com/mycontroller-org/server/v2/pkg/store"
+	fieldTY "github.com/mycontroller-org/server/v2/pkg/types/field"
+	converterUtils "github.com/mycontroller-org/server/v2/pkg/utils/convertor"
+	metricTY "github.com/mycontroller-org/server/v2/plugin/database/metric/types"
+)
+
+// Export returns the metric history of a field in the requested format
+// numeric metric types exported with aggregated windows, others with the stored values
+func Export(quickID string, query *metricTY.Query, format string) ([]byte, string, error) {
+	field, err := GetField(quickID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	result, err := store.METRIC.Query(fieldQuery(field, query))
+	if err != nil {
+		return nil, "", err
+	}
+	metrics := result[field.ID]
+
+	switch format {
+	case FormatCSV, "":
+		data, err := toCSV(metrics)
+		return data, "text/csv", err
+
+	case FormatJSON:
+		data, err := json.Marshal(metrics)
+		return data, "application/json", err
+
+	case FormatLineProtocol:
+		return toLineProtocol(field, metrics), "text/plain", nil
+
+	default:
+		return nil, "", fmt.Errorf("unsupported export format:%s", format)
+	}
+}
+
+// metricKeys returns all the metric keys sorted
+func metricKeys(metrics []metricTY.ResponseData) []string {
+	keysMap := make(map[string]bool)
+	for _, metric := range metrics {
+		for key := range metric.Metric {
+			keysMap[key] = true
+		}
+	}
+	keys := make([]string, 0, len(keysMap))
+	for key := range keysMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func toCSV(metrics []metricTY.ResponseData) ([]byte, error) {
+	keys := metricKeys(metrics)
+	buffer := &bytes.Buffer{}
+	writer := csv.NewWriter(buffer)
+	err := writer.Write(append([]string{"timestamp"}, keys...))
+	if err != nil {
+		return nil, err
+	}
+	for _, metric := range metrics {
+		row := []string{metric.Time.Format("2006-01-02T15:04:05.999999999Z07:00")}
+		for _, key := range keys {
+			value, found := metric.Metric[key]
+			if !found || value == nil {
+				row = append(row, "")
+				continue
+			}
+			row = append(row, converterUtils.ToString(value))
+		}
+		err = writer.Write(row)
+		if err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
+
+// toLineProtocol returns influxdb line protocol format
+// measurement is the metric type and tags are the field identifiers
+func toLineProtocol(field *fieldTY.Field, metrics []metricTY.ResponseData) []byte {
+	tags := fieldTags(field)
+	tagNames := make([]string, 0, len(tags))
+	for name := range tags {
+		tagNames = append(tagNames, name)
+	}
+	sort.Strings(tagNames)
+
+	var tagsBuilder strings.Builder
+	for _, name := range tagNames {
+		fmt.Fprintf(&tagsBuilder, ",%s=%s", escapeLineProtocol(strings.ToLower(name)), escapeLineProtocol(tags[name]))
+	}
+
+	keys := metricKeys(metrics)
+	buffer := &bytes.Buffer{}
+	for _, metric := range metrics {
+		fields := make([]string, 0)
+		for _, key := range keys {
+			value, found := metric.Metric[key]
+			if !found || value == nil {
+				continue
+			}
+			switch typedValue := value.(type) {
+			case string:
+				fields = append(fields, fmt.Sprintf(`%s="%s"`, escapeLineProtocol(key), strings.ReplaceAll(typedValue, `"`, `\"`)))
+			case bool:
+				fields = append(fields, fmt.Sprintf("%s=%t", escapeLineProtocol(key), typedValue))
+			default:
+				fields = append(fields, fmt.Sprintf("%s=%v", escapeLineProtocol(key), converterUtils.ToFloat(value)))
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		fmt.Fprintf(buffer, "%s%s %s %d\n", escapeLineProtocol(field.MetricType), tagsBuilder.String(), strings.Join(fields, ","), metric.Time.UnixNano())
+	}
+	return buffer.Bytes()
+}
+
+func escapeLineProtocol(value string) string {
+	replacer := strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+	return replacer.Replace(value)
+}
@@ -0,0 +1,396 @@
+package metric
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mycontroller-org/server/v2/pkg/json"
+	metricSVC "github.com/mycontroller-org/server/v2/pkg/service/database/metric"
+	"github.com/mycontroller-org/server/v2/pkg/store"
+	fieldTY "github.com/mycontroller-org/server/v2/pkg/types/field"
+	converterUtils "github.com/mycontroller-org/server/v2/pkg/utils/convertor"
+	metricTY "github.com/mycontroller-org/server/v2/plugin/database/metric/types"
+	"go.uber.org/zap"
+)
+
+// number of data points written in a single request
+const importBatchSize = 1000
+
+// aggregated columns of the exported data, in the order of preference
+var aggregatePreference = []string{"mean", "last", "max", "min"}
+
+// ImportResult of an import request
+type ImportResult struct {
+	Imported int64  `json:"imported"`
+	Skipped  int64  `json:"skipped"`
+	Message  string `json:"message"`
+}
+
+// point of the import data, value or fields should be present
+type point struct {
+	Time   time.Time
+	Value  interface{}
+	Fields map[string]interface{}
+}
+
+// Import writes the historical data of a field in to the metric database
+// supported formats:
+//
+//	csv: header with "timestamp" and "value" columns, if "value" column not present, "mean", "last", "max", "min" or the first non timestamp column will be used
+//	json: array of {"timestamp": .., "value": ..} or the exported format {"timestamp": .., "metric": {..}}
+//	line_protocol: influxdb line protocol, "value" field or the first field will be used
+func Import(quickID, format string, data []byte) (*ImportResult, error) {
+	field, err := GetField(quickID)
+	if err != nil {
+		return nil, err
+	}
+	if field.MetricType == metricTY.MetricTypeNone {
+		return nil, fmt.Errorf("metric type of the field is '%s', metric not stored for this field", field.MetricType)
+	}
+
+	var points []point
+	switch format {
+	case FormatCSV, "":
+		points, err = parseCSV(data)
+	case FormatJSON:
+		points, err = parseJSON(data)
+	case FormatLineProtocol:
+		points, err = parseLineProtocol(data)
+	default:
+		return nil, fmt.Errorf("unsupported import format:%s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{}
+	tags := fieldTags(field)
+	policy := metricSVC.GetPolicyName(field.MetricType, field.Labels)
+	batch := make([]*metricTY.InputData, 0, importBatchSize)
+	writeBatch := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := store.METRIC.WriteBatch(batch)
+		if err != nil {
+			result.Message = err.Error()
+			return fmt.Errorf("error on writing data, imported:%d, error:%s", result.Imported, err.Error())
+		}
+		result.Imported += int64(len(batch))
+		batch = batch[:0]
+		return nil
+	}
+
+	for _, p := range points {
+		fields, err := toMetricFields(field, &p)
+		if err != nil {
+			zap.L().Debug("skipping a data on import", zap.String("quickID", quickID), zap.Any("point", p), zap.Error(err))
+			result.Skipped++
+			continue
+		}
+		batch = append(batch, &metricTY.InputData{
+			MetricType: field.MetricType,
+			Time:       p.Time,
+			Tags:       tags,
+			Fields:     fields,
+			Policy:     policy,
+		})
+		if len(batch) >= importBatchSize {
+			if err := writeBatch(); err != nil {
+				return result, err
+			}
+		}
+	}
+	if err := writeBatch(); err != nil {
+		return result, err
+	}
+	result.Message = fmt.Sprintf("imported:%d, skipped:%d", result.Imported, result.Skipped)
+	return result, nil
+}
+
+// toMetricFields converts the value to the field metric type
+func toMetricFields(field *fieldTY.Field, p *point) (map[string]interface{}, error) {
+	if field.MetricType == metricTY.MetricTypeGEO {
+		if p.Fields != nil {
+			if _, found := p.Fields[metricTY.FieldLatitude]; found {
+				return map[string]interface{}{
+					metricTY.FieldLatitude:  converterUtils.ToFloat(p.Fields[metricTY.FieldLatitude]),
+					metricTY.FieldLongitude: converterUtils.ToFloat(p.Fields[metricTY.FieldLongitude]),
+					metricTY.FieldAltitude:  converterUtils.ToFloat(p.Fields[metricTY.FieldAltitude]),
+				}, nil
+			}
+		}
+		// latitude;longitude;altitude
+		parts := strings.Split(converterUtils.ToString(p.Value), ";")
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("invalid geo data: %v", p.Value)
+		}
+		fields := map[string]interface{}{metricTY.FieldAltitude: float64(0)}
+		for index, name := range []string{metricTY.FieldLatitude, metricTY.FieldLongitude, metricTY.FieldAltitude} {
+			if index >= len(parts) {
+				break
+			}
+			value, err := strconv.ParseFloat(strings.TrimSpace(parts[index]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid geo data: %v", p.Value)
+			}
+			fields[name] = value
+		}
+		return fields, nil
+	}
+
+	if p.Value == nil || p.Value == "" {
+		return nil, errors.New("empty value")
+	}
+
+	var value interface{}
+	switch field.MetricType {
+	case metricTY.MetricTypeBinary:
+		value = converterUtils.ToBool(p.Value)
+	case metricTY.MetricTypeGaugeFloat:
+		value = converterUtils.ToFloat(p.Value)
+	case metricTY.MetricTypeGauge, metricTY.MetricTypeCounter:
+		value = converterUtils.ToInteger(p.Value)
+	case metricTY.MetricTypeString:
+		value = converterUtils.ToString(p.Value)
+	default:
+		return nil, fmt.Errorf("unknown metricType: %s", field.MetricType)
+	}
+	return map[string]interface{}{metricTY.FieldValue: value}, nil
+}
+
+func parseCSV(data []byte) ([]point, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error on reading csv header: %s", err.Error())
+	}
+	timeIndex := -1
+	valueIndex := -1
+	for index, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		header[index] = column
+		switch column {
+		case "timestamp", "time":
+			timeIndex = index
+		case metricTY.FieldValue:
+			valueIndex = index
+		}
+	}
+	if timeIndex == -1 {
+		return nil, errors.New("timestamp column not found on the csv header")
+	}
+	// exported data keeps the aggregated columns
+	for _, key := range aggregatePreference {
+		if valueIndex != -1 {
+			break
+		}
+		for index, column := range header {
+			if column == key {
+				valueIndex = index
+				break
+			}
+		}
+	}
+	if valueIndex == -1 {
+		for index := range header {
+			if index != timeIndex {
+				valueIndex = index
+				break
+			}
+		}
+	}
+	if valueIndex == -1 {
+		return nil, errors.New("value column not found on the csv header")
+	}
+
+	points := make([]point, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) <= timeIndex {
+			continue
+		}
+		timestamp, err := parseTimestamp(record[timeIndex])
+		if err != nil {
+			return nil, err
+		}
+		p := point{Time: timestamp, Fields: make(map[string]interface{})}
+		for index, column := range header {
+			if index < len(record) && index != timeIndex {
+				p.Fields[column] = record[index]
+			}
+		}
+		if valueIndex < len(record) {
+			p.Value = record[valueIndex]
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+func parseJSON(data []byte) ([]point, error) {
+	items := make([]map[string]interface{}, 0)
+	err := json.Unmarshal(data, &items)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]point, 0, len(items))
+	for _, item := range items {
+		rawTime, found := item["timestamp"]
+		if !found {
+			rawTime = item["time"]
+		}
+		timestamp, err := parseTimestamp(converterUtils.ToString(rawTime))
+		if err != nil {
+			return nil, err
+		}
+		p := point{Time: timestamp, Fields: item}
+		// exported format keeps the values under metric
+		if metric, ok := item["metric"].(map[string]interface{}); ok {
+			p.Fields = metric
+		}
+		if value, found := p.Fields[metricTY.FieldValue]; found {
+			p.Value = value
+		} else {
+			for _, key := range aggregatePreference {
+				if value, found := p.Fields[key]; found {
+					p.Value = value
+					break
+				}
+			}
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+// parseLineProtocol parses influxdb line protocol
+// <measurement>[,<tag_key>=<tag_value>] <field_key>=<field_value>[,<field_key>=<field_value>] [<timestamp in ns>]
+// measurement and tags are not used, the values imported to the supplied field
+func parseLineProtocol(data []byte) ([]point, error) {
+	points := make([]point, 0)
+	for lineNumber, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// quotes are allowed as is on the measurement and tags
+		measurement, rest, found := cutUnescaped(line, ' ', false)
+		if !found || measurement == "" {
+			return nil, fmt.Errorf("invalid line protocol on line:%d", lineNumber+1)
+		}
+		fieldSet, timestamp, _ := cutUnescaped(strings.TrimLeft(rest, " "), ' ', true)
+
+		p := point{Time: time.Now(), Fields: make(map[string]interface{})}
+		firstKey, err := parseLineProtocolFields(fieldSet, p.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("%s on line:%d", err.Error(), lineNumber+1)
+		}
+		if timestamp = strings.TrimSpace(timestamp); timestamp != "" {
+			nanoSeconds, err := strconv.ParseInt(timestamp, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timestamp on line:%d", lineNumber+1)
+			}
+			p.Time = time.Unix(0, nanoSeconds)
+		}
+
+		if value, found := p.Fields[metricTY.FieldValue]; found {
+			p.Value = value
+		} else {
+			p.Value = p.Fields[firstKey]
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+// parseLineProtocolFields parses the field set into the fields, returns the first field key
+func parseLineProtocolFields(fieldSet string, fields map[string]interface{}) (string, error) {
+	if fieldSet == "" {
+		return "", errors.New("fields not available")
+	}
+	firstKey := ""
+	for {
+		key, rest, found := cutUnescaped(fieldSet, '=', false)
+		if !found || key == "" {
+			return "", fmt.Errorf("invalid field '%s'", fieldSet)
+		}
+		value, remaining, more := cutUnescaped(rest, ',', true)
+		if value == "" {
+			return "", fmt.Errorf("value missing for the field '%s'", key)
+		}
+		key = unescapeLineProtocol(key)
+		if firstKey == "" {
+			firstKey = key
+		}
+		fields[key] = parseLineProtocolValue(value)
+		if !more {
+			return firstKey, nil
+		}
+		if remaining == "" {
+			return "", errors.New("field missing after the comma")
+		}
+		fieldSet = remaining
+	}
+}
+
+// cutUnescaped returns the parts before and after the first unescaped separator
+// separators inside the double quotes are ignored, if the quotes enabled
+func cutUnescaped(value string, separator byte, quotes bool) (string, string, bool) {
+	escaped := false
+	quoted := false
+	for index := 0; index < len(value); index++ {
+		char := value[index]
+		switch {
+		case escaped:
+			escaped = false
+		case char == '\\':
+			escaped = true
+		case char == '"' && quotes:
+			quoted = !quoted
+		case char == separator && !quoted:
+			return value[:index], value[index+1:], true
+		}
+	}
+	return value, "", false
+}
+
+func unescapeLineProtocol(value string) string {
+	replacer := strings.NewReplacer(`\,`, ",", `\=`, "=", `\ `, " ", `\"`, `"`, `\\`, `\`)
+	return replacer.Replace(value)
+}
+
+func parseLineProtocolValue(value string) interface{} {
+	switch {
+	case strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) && len(value) >= 2:
+		return unescapeLineProtocol(value[1 : len(value)-1])
+	case value == "t" || value == "T" || value == "true" || value == "True" || value == "TRUE":
+		return true
+	case value == "f" || value == "F" || value == "false" || value == "False" || value == "FALSE":
+		return false
+	case strings.HasSuffix(value, "i") || strings.HasSuffix(value, "u"):
+		if number, err := strconv.ParseInt(value[:len(value)-1], 10, 64); err == nil {
+			return number
+		}
+	}
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		return number
+	}
+	return value
+}
@@ -8,6 +8,7 @@ import (
 	gatewayAPI "github.com/mycontroller-org/server/v2/pkg/api/gateway"
 	handlerAPI "github.com/mycontroller-org/server/v2/pkg/api/handler"
 	nodeAPI "github.com/mycontroller-org/server/v2/pkg/api/node"
+	sceneAPI "github.com/mycontroller-org/server/v2/pkg/api/scene"
 	scheduleAPI "github.com/mycontroller-org/server/v2/pkg/api/schedule"
 	sourceAPI "github.com/mycontroller-org/server/v2/pkg/api/source"
 	taskAPI "github.com/mycontroller-org/server/v2/pkg/api/task"
@@ -51,6 +52,9 @@ func GetResources(quickIDs []string) (map[string]interface{}, error) {
 		case types.EntityDataRepository:
 			item, err = dataRepoAPI.GetByID(keys[types.KeyID])
 
+		case types.EntityScene:
+			item, err = sceneAPI.GetByID(keys[types.KeyID])
+
 		default:
 			return nil, fmt.Errorf("unknown resource type: %s, quickID: %s", resourceType, quickID)
 		}
@@ -0,0 +1,71 @@
+package role
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mycontroller-org/server/v2/pkg/service/configuration"
+	"github.com/mycontroller-org/server/v2/pkg/store"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	roleTY "github.com/mycontroller-org/server/v2/pkg/types/role"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+)
+
+// List by filter and pagination, built-in roles are not included
+func List(filters []storageTY.Filter, pagination *storageTY.Pagination) (*storageTY.Result, error) {
+	result := make([]roleTY.Role, 0)
+	return store.STORAGE.Find(types.EntityRole, &result, filters, pagination)
+}
+
+// GetByID returns a role, includes built-in roles
+func GetByID(ID string) (*roleTY.Role, error) {
+	if builtInRole, found := roleTY.BuiltInRoles[ID]; found {
+		return &builtInRole, nil
+	}
+	result := &roleTY.Role{}
+	filters := []storageTY.Filter{
+		{Key: types.KeyID, Value: ID},
+	}
+	err := store.STORAGE.FindOne(types.EntityRole, result, filters)
+	return result, err
+}
+
+// Save a role
+func Save(role *roleTY.Role) error {
+	err := role.Validate()
+	if err != nil {
+		return err
+	}
+	if !configuration.PauseModifiedOnUpdate.IsSet() {
+		role.ModifiedOn = time.Now()
+	}
+	filters := []storageTY.Filter{
+		{Key: types.KeyID, Value: role.ID},
+	}
+	return store.STORAGE.Upsert(types.EntityRole, role, filters)
+}
+
+// Delete roles
+func Delete(IDs []string) (int64, error) {
+	for _, id := range IDs {
+		if _, found := roleTY.BuiltInRoles[id]; found {
+			return 0, fmt.Errorf("built-in role '%s' can not be deleted", id)
+		}
+	}
+	filters := []storageTY.Filter{{Key: types.KeyID, Operator: storageTY.OperatorIn, Value: IDs}}
+	return store.STORAGE.Delete(types.EntityRole, filters)
+}
+
+// GetPermissions returns the permissions of the roles
+// missing roles are reported as error
+func GetPermissions(roleIDs []string) ([]roleTY.Permission, error) {
+	permissions := make([]roleTY.Permission, 0)
+	for _, roleID := range roleIDs {
+		role, err := GetByID(roleID)
+		if err != nil {
+			return nil, fmt.Errorf("error on getting a role:%s, error:%s", roleID, err.Error())
+		}
+		permissions = append(permissions, role.Permissions...)
+	}
+	return permissions, nil
+}
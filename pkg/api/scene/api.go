@@ -0,0 +1,264 @@
+package scene
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	fieldAPI "github.com/mycontroller-org/server/v2/pkg/api/field"
+	"github.com/mycontroller-org/server/v2/pkg/service/configuration"
+	"github.com/mycontroller-org/server/v2/pkg/store"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	"github.com/mycontroller-org/server/v2/pkg/types/cmap"
+	fieldTY "github.com/mycontroller-org/server/v2/pkg/types/field"
+	roleTY "github.com/mycontroller-org/server/v2/pkg/types/role"
+	sceneTY "github.com/mycontroller-org/server/v2/pkg/types/scene"
+	converterUtils "github.com/mycontroller-org/server/v2/pkg/utils/convertor"
+	quickIdUtils "github.com/mycontroller-org/server/v2/pkg/utils/quick_id"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+	"go.uber.org/zap"
+)
+
+// maximum fields selected by labels on a scene
+const fieldsLimit = 1000
+
+// List by filter and pagination
+func List(filters []storageTY.Filter, pagination *storageTY.Pagination) (*storageTY.Result, error) {
+	result := make([]sceneTY.Config, 0)
+	return store.STORAGE.Find(types.EntityScene, &result, filters, pagination)
+}
+
+// Get returns a item
+func Get(filters []storageTY.Filter) (*sceneTY.Config, error) {
+	result := &sceneTY.Config{}
+	err := store.STORAGE.FindOne(types.EntityScene, result, filters)
+	return result, err
+}
+
+// GetByID returns a item by id
+func GetByID(id string) (*sceneTY.Config, error) {
+	filters := []storageTY.Filter{
+		{Key: types.KeyID, Value: id},
+	}
+	return Get(filters)
+}
+
+// Save verifies the field selectors and updates the scene
+func Save(data *sceneTY.Config) error {
+	if data.ID == "" {
+		return errors.New("'id' can not be empty")
+	}
+	if len(data.Fields) == 0 && len(data.FieldLabels) == 0 {
+		return errors.New("'fields' or 'fieldLabels' should be supplied")
+	}
+	for _, quickID := range data.Fields {
+		if err := verifyFieldQuickID(quickID); err != nil {
+			return err
+		}
+	}
+	for _, fieldValue := range data.Snapshot {
+		if err := verifyFieldQuickID(fieldValue.QuickID); err != nil {
+			return fmt.Errorf("invalid snapshot, %s", err.Error())
+		}
+	}
+
+	filters := []storageTY.Filter{
+		{Key: types.KeyID, Value: data.ID},
+	}
+	if !configuration.PauseModifiedOnUpdate.IsSet() {
+		data.ModifiedOn = time.Now()
+	}
+	return store.STORAGE.Upsert(types.EntityScene, data, filters)
+}
+
+// Delete items
+func Delete(IDs []string) (int64, error) {
+	filters := []storageTY.Filter{{Key: types.KeyID, Operator: storageTY.OperatorIn, Value: IDs}}
+	return store.STORAGE.Delete(types.EntityScene, filters)
+}
+
+// GetFields returns the fields selected by quick ids and labels, without duplicates
+func GetFields(scene *sceneTY.Config) ([]fieldTY.Field, error) {
+	fields := make([]fieldTY.Field, 0)
+	added := make(map[string]bool)
+
+	for _, quickID := range scene.Fields {
+		field, err := getField(quickID)
+		if err != nil {
+			return nil, err
+		}
+		if !added[field.ID] {
+			added[field.ID] = true
+			fields = append(fields, *field)
+		}
+	}
+
+	if len(scene.FieldLabels) > 0 {
+		filters := make([]storageTY.Filter, 0)
+		for key, value := range scene.FieldLabels {
+			filters = append(filters, storageTY.Filter{Key: fmt.Sprintf("labels.%s", key), Operator: storageTY.OperatorEqual, Value: value})
+		}
+		result, err := fieldAPI.List(filters, &storageTY.Pagination{Limit: fieldsLimit})
+		if err != nil {
+			return nil, err
+		}
+		if items, ok := result.Data.(*[]fieldTY.Field); ok {
+			for _, field := range *items {
+				if !added[field.ID] {
+					added[field.ID] = true
+					fields = append(fields, field)
+				}
+			}
+		}
+	}
+
+	return fields, nil
+}
+
+// Snapshot captures the current values of the scene fields
+// fields without a value are not included
+func Snapshot(id string) (*sceneTY.Config, error) {
+	scene, err := GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := GetFields(scene)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := make([]sceneTY.FieldValue, 0)
+	for index := range fields {
+		field := fields[index]
+		if field.Current.Value == nil {
+			zap.L().Debug("field value not available, excluded from the snapshot", zap.String("sceneId", scene.ID), zap.String("fieldId", field.ID))
+			continue
+		}
+		quickID, err := quickIdUtils.GetQuickID(field)
+		if err != nil {
+			return nil, err
+		}
+		snapshot = append(snapshot, sceneTY.FieldValue{QuickID: quickID, Value: converterUtils.ToString(field.Current.Value)})
+	}
+
+	scene.Snapshot = snapshot
+	scene.SnapshotOn = time.Now()
+	err = Save(scene)
+	if err != nil {
+		return nil, err
+	}
+	return scene, nil
+}
+
+// GetSnapshotFields returns the fields of the snapshot with the captured values
+// fails if any of the field is not available, to restore all the values or nothing
+func GetSnapshotFields(id string) ([]fieldTY.Field, []string, error) {
+	scene, err := GetByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(scene.Snapshot) == 0 {
+		return nil, nil, fmt.Errorf("snapshot not available on the scene '%s'", id)
+	}
+
+	fields := make([]fieldTY.Field, 0)
+	values := make([]string, 0)
+	for _, fieldValue := range scene.Snapshot {
+		field, err := getField(fieldValue.QuickID)
+		if err != nil {
+			return nil, nil, err
+		}
+		fields = append(fields, *field)
+		values = append(values, fieldValue.Value)
+	}
+	return fields, values, nil
+}
+
+// VerifyFieldAccess verifies the field permissions of the caller on the scene
+// selected fields requires read, snapshot values requires update permission
+func VerifyFieldAccess(access *roleTY.Access, scene *sceneTY.Config) error {
+	err := verifyFields(access, roleTY.VerbRead, scene.Fields, scene.FieldLabels)
+	if err != nil {
+		return err
+	}
+	return verifyFields(access, roleTY.VerbUpdate, snapshotQuickIDs(scene), nil)
+}
+
+// VerifyActionAccess verifies the field permissions of the caller on the scene action
+// snapshot reads the selected fields, restore updates the fields of the snapshot
+func VerifyActionAccess(access *roleTY.Access, id, sceneAction string) error {
+	scene, err := GetByID(id)
+	if err != nil {
+		return err
+	}
+	switch sceneAction {
+	case sceneTY.ActionSnapshot:
+		return verifyFields(access, roleTY.VerbRead, scene.Fields, scene.FieldLabels)
+	case sceneTY.ActionRestore:
+		return verifyFields(access, roleTY.VerbUpdate, snapshotQuickIDs(scene), nil)
+	}
+	return nil
+}
+
+// verifyFields verifies the verb on the fields
+// on label restricted permission, the field labels selector and the labels of each field should be allowed
+func verifyFields(access *roleTY.Access, verb string, quickIDs []string, fieldLabels cmap.CustomStringMap) error {
+	if access == nil {
+		return nil
+	}
+	if len(quickIDs) == 0 && len(fieldLabels) == 0 {
+		return nil
+	}
+	allowed, selectors := access.IsAllowed(types.EntityField, verb)
+	if !allowed {
+		return fmt.Errorf("permission denied to %s the fields", verb)
+	}
+	if len(selectors) == 0 {
+		return nil
+	}
+	if len(fieldLabels) > 0 && !roleTY.MatchesLabels(selectors, fieldLabels) {
+		return errors.New("field labels of the scene not allowed")
+	}
+	for _, quickID := range quickIDs {
+		field, err := getField(quickID)
+		if err != nil {
+			return err
+		}
+		if !roleTY.MatchesLabels(selectors, field.Labels) {
+			return fmt.Errorf("labels of the field '%s' not allowed", quickID)
+		}
+	}
+	return nil
+}
+
+func snapshotQuickIDs(scene *sceneTY.Config) []string {
+	quickIDs := make([]string, 0)
+	for _, fieldValue := range scene.Snapshot {
+		quickIDs = append(quickIDs, fieldValue.QuickID)
+	}
+	return quickIDs
+}
+
+func getField(quickID string) (*fieldTY.Field, error) {
+	_, keys, err := quickIdUtils.EntityKeyValueMap(quickID)
+	if err != nil {
+		return nil, err
+	}
+	field, err := fieldAPI.GetByIDs(keys[types.KeyGatewayID], keys[types.KeyNodeID], keys[types.KeySourceID], keys[types.KeyFieldID])
+	if err != nil {
+		return nil, fmt.Errorf("error on getting the field '%s', error:%s", quickID, err.Error())
+	}
+	return field, nil
+}
+
+func verifyFieldQuickID(quickID string) error {
+	resourceType, _, err := quickIdUtils.EntityKeyValueMap(quickID)
+	if err != nil {
+		return err
+	}
+	if resourceType != types.EntityField {
+		return fmt.Errorf("field quick id expected, received '%s'", quickID)
+	}
+	return nil
+}
@@ -26,6 +26,10 @@ func Get(filters []storageTY.Filter) (*scheduleTY.Config, error) {
 
 // Save a scheduler details
 func Save(schedule *scheduleTY.Config) error {
+	err := schedule.Validate()
+	if err != nil {
+		return err
+	}
 	eventType := eventTY.TypeUpdated
 	if schedule.ID == "" {
 		schedule.ID = utils.RandUUID()
@@ -35,7 +39,7 @@ func Save(schedule *scheduleTY.Config) error {
 	filters := []storageTY.Filter{
 		{Key: types.KeyID, Value: schedule.ID},
 	}
-	err := store.STORAGE.Upsert(types.EntitySchedule, schedule, filters)
+	err = store.STORAGE.Upsert(types.EntitySchedule, schedule, filters)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,91 @@
+package scriptmodule
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/mycontroller-org/server/v2/pkg/service/configuration"
+	"github.com/mycontroller-org/server/v2/pkg/store"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	scriptModuleTY "github.com/mycontroller-org/server/v2/pkg/types/script_module"
+	"github.com/mycontroller-org/server/v2/pkg/utils/javascript"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+)
+
+// module id used on require("id"), should not contain path separators
+var validID = regexp.MustCompile(`^[a-zA-Z0-9_\-.]+$`)
+
+// List by filter and pagination
+func List(filters []storageTY.Filter, pagination *storageTY.Pagination) (*storageTY.Result, error) {
+	result := make([]scriptModuleTY.Config, 0)
+	return store.STORAGE.Find(types.EntityScriptModule, &result, filters, pagination)
+}
+
+// Get returns a item
+func Get(filters []storageTY.Filter) (*scriptModuleTY.Config, error) {
+	result := &scriptModuleTY.Config{}
+	err := store.STORAGE.FindOne(types.EntityScriptModule, result, filters)
+	return result, err
+}
+
+// GetByID returns a item by id
+func GetByID(id string) (*scriptModuleTY.Config, error) {
+	filters := []storageTY.Filter{
+		{Key: types.KeyID, Value: id},
+	}
+	return Get(filters)
+}
+
+// Save verifies the script and updates the module
+func Save(data *scriptModuleTY.Config) error {
+	if data.ID == "" {
+		return errors.New("'id' can not be empty")
+	}
+	if !validID.MatchString(data.ID) {
+		return fmt.Errorf("invalid id '%s', allowed characters: a-z, A-Z, 0-9, '_', '-' and '.'", data.ID)
+	}
+	// compiles as the require function does, to report the syntax errors on save
+	_, err := goja.Compile(data.ID, "(function(exports, require, module) {"+data.Script+"\n})", false)
+	if err != nil {
+		return fmt.Errorf("invalid script, error:%s", err.Error())
+	}
+
+	filters := []storageTY.Filter{
+		{Key: types.KeyID, Value: data.ID},
+	}
+	if !configuration.PauseModifiedOnUpdate.IsSet() {
+		data.ModifiedOn = time.Now()
+	}
+	err = store.STORAGE.Upsert(types.EntityScriptModule, data, filters)
+	if err != nil {
+		return err
+	}
+	javascript.ResetModules()
+	return nil
+}
+
+// Delete items
+func Delete(IDs []string) (int64, error) {
+	filters := []storageTY.Filter{{Key: types.KeyID, Operator: storageTY.OperatorIn, Value: IDs}}
+	deleted, err := store.STORAGE.Delete(types.EntityScriptModule, filters)
+	if err != nil {
+		return 0, err
+	}
+	javascript.ResetModules()
+	return deleted, nil
+}
+
+// GetSource returns the script of the module, used as javascript module loader
+func GetSource(id string) (string, error) {
+	module, err := GetByID(id)
+	if err != nil {
+		if errors.Is(err, storageTY.ErrNoDocuments) {
+			return "", javascript.ErrModuleNotFound
+		}
+		return "", err
+	}
+	return module.Script, nil
+}
@@ -0,0 +1,108 @@
+package secret
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mycontroller-org/server/v2/pkg/service/configuration"
+	"github.com/mycontroller-org/server/v2/pkg/store"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	secretTY "github.com/mycontroller-org/server/v2/pkg/types/secret"
+	cloneUtil "github.com/mycontroller-org/server/v2/pkg/utils/clone"
+	"github.com/mycontroller-org/server/v2/pkg/utils/hashed"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+)
+
+var (
+	// secret reference format, "${secret:<id>}"
+	referenceRegex = regexp.MustCompile(`\$\{secret:([a-zA-Z0-9_.\-]+)\}`)
+	idRegex        = regexp.MustCompile(`^[a-zA-Z0-9_.\-]+$`)
+)
+
+// List by filter and pagination
+func List(filters []storageTY.Filter, pagination *storageTY.Pagination) (*storageTY.Result, error) {
+	result := make([]secretTY.Secret, 0)
+	return store.STORAGE.Find(types.EntitySecret, &result, filters, pagination)
+}
+
+// GetByID returns a secret, value is encrypted
+func GetByID(ID string) (*secretTY.Secret, error) {
+	result := &secretTY.Secret{}
+	filters := []storageTY.Filter{
+		{Key: types.KeyID, Value: ID},
+	}
+	err := store.STORAGE.FindOne(types.EntitySecret, result, filters)
+	return result, err
+}
+
+// Save a secret, value will be encrypted
+// existing value retained, if the value is empty or redacted
+func Save(secret *secretTY.Secret) error {
+	if !idRegex.MatchString(secret.ID) {
+		return fmt.Errorf("invalid secret id '%s', allowed characters: a-z, A-Z, 0-9, '_', '.', '-'", secret.ID)
+	}
+
+	if secret.Value == "" || secret.Value == secretTY.RedactedValue {
+		oldSecret, err := GetByID(secret.ID)
+		if err != nil {
+			return errors.New("secret value can not be empty")
+		}
+		secret.Value = oldSecret.Value
+	}
+
+	encryptedValue, err := hashed.Encrypt(secret.Value, store.CFG.Secret, "")
+	if err != nil {
+		return err
+	}
+	secret.Value = encryptedValue
+
+	if !configuration.PauseModifiedOnUpdate.IsSet() {
+		secret.ModifiedOn = time.Now()
+	}
+	filters := []storageTY.Filter{
+		{Key: types.KeyID, Value: secret.ID},
+	}
+	return store.STORAGE.Upsert(types.EntitySecret, secret, filters)
+}
+
+// Delete secrets
+func Delete(IDs []string) (int64, error) {
+	filters := []storageTY.Filter{{Key: types.KeyID, Operator: storageTY.OperatorIn, Value: IDs}}
+	return store.STORAGE.Delete(types.EntitySecret, filters)
+}
+
+// GetValue returns the decrypted value of a secret
+func GetValue(ID string) (string, error) {
+	secret, err := GetByID(ID)
+	if err != nil {
+		return "", fmt.Errorf("error on getting a secret:%s, error:%s", ID, err.Error())
+	}
+	return hashed.Decrypt(secret.Value, store.CFG.Secret, "")
+}
+
+// ResolveReferences replaces the secret references with the actual values
+// should be called on a copy of the configuration, just before loading a plugin
+func ResolveReferences(source interface{}) error {
+	return cloneUtil.UpdateStrings(source, ResolveString)
+}
+
+// ResolveString replaces the secret references in the string
+func ResolveString(value string) (string, error) {
+	if !strings.Contains(value, "${secret:") {
+		return value, nil
+	}
+	var resolveErr error
+	resolved := referenceRegex.ReplaceAllStringFunc(value, func(reference string) string {
+		ID := referenceRegex.FindStringSubmatch(reference)[1]
+		secretValue, err := GetValue(ID)
+		if err != nil {
+			resolveErr = err
+			return reference
+		}
+		return secretValue
+	})
+	return resolved, resolveErr
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	sessionAPI "github.com/mycontroller-org/server/v2/pkg/api/session"
 	"github.com/mycontroller-org/server/v2/pkg/service/configuration"
 	"github.com/mycontroller-org/server/v2/pkg/store"
 	types "github.com/mycontroller-org/server/v2/pkg/types"
@@ -77,9 +78,24 @@ func Save(token *svcTokenTY.ServiceToken) error {
 	return store.STORAGE.Upsert(types.EntityServiceToken, token, filters)
 }
 
-// Delete items
+// Delete items, revokes the sessions created with the tokens
 func Delete(IDs []string) (int64, error) {
 	filters := []storageTY.Filter{{Key: types.KeyID, Operator: storageTY.OperatorIn, Value: IDs}}
+	result, err := List(filters, nil)
+	if err != nil {
+		return 0, err
+	}
+	if tokens, ok := result.Data.(*[]svcTokenTY.ServiceToken); ok {
+		tokenIDs := make([]string, 0, len(*tokens))
+		for _, token := range *tokens {
+			tokenIDs = append(tokenIDs, token.Token.ID)
+		}
+		if len(tokenIDs) > 0 {
+			if _, err = sessionAPI.DeleteByServiceTokenIDs(tokenIDs); err != nil {
+				return 0, err
+			}
+		}
+	}
 	return store.STORAGE.Delete(types.EntityServiceToken, filters)
 }
 
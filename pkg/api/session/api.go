@@ -0,0 +1,150 @@
+package session
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mycontroller-org/server/v2/pkg/store"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	sessionTY "github.com/mycontroller-org/server/v2/pkg/types/session"
+	"github.com/mycontroller-org/server/v2/pkg/utils"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+)
+
+const (
+	cacheValidity = time.Minute // active sessions are verified on the storage after this duration
+)
+
+var (
+	// active sessions cache, avoids the storage lookup on every request
+	activeCache = make(map[string]time.Time)
+	cacheMutex  sync.Mutex
+)
+
+// List by filter and pagination
+func List(filters []storageTY.Filter, pagination *storageTY.Pagination) (*storageTY.Result, error) {
+	result := make([]sessionTY.Session, 0)
+	return store.STORAGE.Find(types.EntitySession, &result, filters, pagination)
+}
+
+// GetByID returns a session
+func GetByID(ID string) (*sessionTY.Session, error) {
+	result := &sessionTY.Session{}
+	filters := []storageTY.Filter{
+		{Key: types.KeyID, Value: ID},
+	}
+	err := store.STORAGE.FindOne(types.EntitySession, result, filters)
+	return result, err
+}
+
+// Create a session
+func Create(session *sessionTY.Session) error {
+	if session.ID == "" {
+		session.ID = utils.RandUUID()
+	}
+	if session.CreatedOn.IsZero() {
+		session.CreatedOn = time.Now()
+	}
+	return store.STORAGE.Insert(types.EntitySession, session)
+}
+
+// Save a session, used on restore
+func Save(session *sessionTY.Session) error {
+	filters := []storageTY.Filter{
+		{Key: types.KeyID, Value: session.ID},
+	}
+	return store.STORAGE.Upsert(types.EntitySession, session, filters)
+}
+
+// IsActive returns true, if the session available and not expired
+func IsActive(ID string) bool {
+	cacheMutex.Lock()
+	verifiedOn, found := activeCache[ID]
+	cacheMutex.Unlock()
+	if found && time.Since(verifiedOn) < cacheValidity {
+		return true
+	}
+
+	session, err := GetByID(ID)
+	if err != nil || session.ExpiresOn.Before(time.Now()) {
+		removeFromCache(ID)
+		return false
+	}
+
+	cacheMutex.Lock()
+	activeCache[ID] = time.Now()
+	cacheMutex.Unlock()
+	return true
+}
+
+// Delete sessions, revokes the tokens
+func Delete(IDs []string) (int64, error) {
+	removeFromCache(IDs...)
+	filters := []storageTY.Filter{{Key: types.KeyID, Operator: storageTY.OperatorIn, Value: IDs}}
+	return store.STORAGE.Delete(types.EntitySession, filters)
+}
+
+// DeleteByUserIDs revokes all the sessions of the users
+func DeleteByUserIDs(userIDs []string) (int64, error) {
+	return deleteByFilter(storageTY.Filter{Key: types.KeyUserID, Operator: storageTY.OperatorIn, Value: userIDs})
+}
+
+// DeleteByServiceTokenIDs revokes all the sessions created with the service tokens
+func DeleteByServiceTokenIDs(tokenIDs []string) (int64, error) {
+	return deleteByFilter(storageTY.Filter{Key: types.KeyServiceTokenID, Operator: storageTY.OperatorIn, Value: tokenIDs})
+}
+
+// PurgeExpired removes the expired sessions
+// time based filters are not supported on all the storage databases, hence filtered here
+func PurgeExpired() (int64, error) {
+	sessions, err := list(nil)
+	if err != nil {
+		return 0, err
+	}
+	IDs := make([]string, 0)
+	for _, session := range sessions {
+		if session.ExpiresOn.Before(time.Now()) {
+			IDs = append(IDs, session.ID)
+		}
+	}
+	if len(IDs) == 0 {
+		return 0, nil
+	}
+	return Delete(IDs)
+}
+
+func deleteByFilter(filter storageTY.Filter) (int64, error) {
+	sessions, err := list([]storageTY.Filter{filter})
+	if err != nil {
+		return 0, err
+	}
+	IDs := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		IDs = append(IDs, session.ID)
+	}
+	if len(IDs) == 0 {
+		return 0, nil
+	}
+	return Delete(IDs)
+}
+
+func list(filters []storageTY.Filter) ([]sessionTY.Session, error) {
+	result, err := List(filters, nil)
+	if err != nil {
+		return nil, err
+	}
+	sessions, ok := result.Data.(*[]sessionTY.Session)
+	if !ok {
+		return nil, fmt.Errorf("invalid session list type:%T", result.Data)
+	}
+	return *sessions, nil
+}
+
+func removeFromCache(IDs ...string) {
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+	for _, id := range IDs {
+		delete(activeCache, id)
+	}
+}
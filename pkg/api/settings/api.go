@@ -8,6 +8,7 @@ import (
 
 	"github.com/mycontroller-org/server/v2/pkg/json"
 	"github.com/mycontroller-org/server/v2/pkg/service/configuration"
+	metricSVC "github.com/mycontroller-org/server/v2/pkg/service/database/metric"
 	systemJobsHelper "github.com/mycontroller-org/server/v2/pkg/service/system_jobs/helper_utils"
 	"github.com/mycontroller-org/server/v2/pkg/store"
 	types "github.com/mycontroller-org/server/v2/pkg/types"
@@ -15,6 +16,7 @@ import (
 	webHandlerTY "github.com/mycontroller-org/server/v2/pkg/types/web_handler"
 	"github.com/mycontroller-org/server/v2/pkg/utils"
 	cloneUtil "github.com/mycontroller-org/server/v2/pkg/utils/clone"
+	"github.com/mycontroller-org/server/v2/pkg/utils/javascript"
 	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
 	"go.uber.org/zap"
 )
@@ -82,6 +84,14 @@ func GetByID(ID string) (*settingsTY.Settings, error) {
 		}
 		specStruct = settings
 
+	case settingsTY.KeyMetricPolicies:
+		settings := settingsTY.MetricPolicies{}
+		err = utils.MapToStruct(utils.TagNameNone, result.Spec, &settings)
+		if err != nil {
+			return nil, err
+		}
+		specStruct = settings
+
 	default:
 
 	}
@@ -115,6 +125,12 @@ func UpdateSettings(settings *settingsTY.Settings) error {
 	case settingsTY.KeySystemSettings:
 		return UpdateSystemSettings(settings)
 
+	case settingsTY.KeyMetricPolicies:
+		return UpdateMetricPolicies(settings)
+
+	case settingsTY.KeyScriptRuntime:
+		return UpdateScriptRuntime(settings)
+
 	case settingsTY.KeySystemJobs,
 		settingsTY.KeyVersion,
 		settingsTY.KeySystemBackupLocations,
@@ -173,6 +189,12 @@ func UpdateSystemSettings(settings *settingsTY.Settings) error {
 	// post node state updater job change event
 	systemJobsHelper.PostEvent(systemJobsHelper.JobTypeNodeStateUpdater)
 
+	// post audit log purge job change event
+	systemJobsHelper.PostEvent(systemJobsHelper.JobTypeAuditLogPurger)
+
+	// post task history purge job change event
+	systemJobsHelper.PostEvent(systemJobsHelper.JobTypeTaskHistoryPurger)
+
 	return nil
 }
 
@@ -344,3 +366,72 @@ func UpdateJwtAccessSecret() error {
 
 	return os.Setenv(webHandlerTY.EnvJwtAccessSecret, systemSecret.JwtAccessSecret)
 }
+
+// UpdateMetricPolicies applies the policies on the metric database and saves into disk
+func UpdateMetricPolicies(settings *settingsTY.Settings) error {
+	metricPolicies := &settingsTY.MetricPolicies{}
+	err := utils.MapToStruct(utils.TagNameNone, settings.Spec, metricPolicies)
+	if err != nil {
+		return err
+	}
+
+	err = metricSVC.UpdatePolicies(metricPolicies.Policies)
+	if err != nil {
+		return err
+	}
+	return update(settings)
+}
+
+// LoadMetricPolicies loads the policies from the database and applies on the metric database
+func LoadMetricPolicies() error {
+	settings, err := GetByID(settingsTY.KeyMetricPolicies)
+	if err != nil {
+		if err == storageTY.ErrNoDocuments {
+			return nil
+		}
+		return err
+	}
+
+	metricPolicies := &settingsTY.MetricPolicies{}
+	err = utils.MapToStruct(utils.TagNameNone, settings.Spec, metricPolicies)
+	if err != nil {
+		return err
+	}
+	return metricSVC.UpdatePolicies(metricPolicies.Policies)
+}
+
+// UpdateScriptRuntime applies the limits on the javascript runtime and saves into disk
+func UpdateScriptRuntime(settings *settingsTY.Settings) error {
+	scriptRuntime := &settingsTY.ScriptRuntime{}
+	err := utils.MapToStruct(utils.TagNameNone, settings.Spec, scriptRuntime)
+	if err != nil {
+		return err
+	}
+
+	err = javascript.UpdateConfig(*scriptRuntime)
+	if err != nil {
+		return err
+	}
+	if !configuration.PauseModifiedOnUpdate.IsSet() {
+		settings.ModifiedOn = time.Now()
+	}
+	return update(settings)
+}
+
+// LoadScriptRuntime loads the javascript runtime settings from the database and applies
+func LoadScriptRuntime() error {
+	settings, err := GetByID(settingsTY.KeyScriptRuntime)
+	if err != nil {
+		if err == storageTY.ErrNoDocuments {
+			return nil
+		}
+		return err
+	}
+
+	scriptRuntime := &settingsTY.ScriptRuntime{}
+	err = utils.MapToStruct(utils.TagNameNone, settings.Spec, scriptRuntime)
+	if err != nil {
+		return err
+	}
+	return javascript.UpdateConfig(*scriptRuntime)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	settingsAPI "github.com/mycontroller-org/server/v2/pkg/api/settings"
+	metricSVC "github.com/mycontroller-org/server/v2/pkg/service/database/metric"
 	"github.com/mycontroller-org/server/v2/pkg/store"
 	types "github.com/mycontroller-org/server/v2/pkg/types"
 	settingsTY "github.com/mycontroller-org/server/v2/pkg/types/settings"
@@ -28,14 +29,15 @@ func init() {
 }
 
 type Status struct {
-	Hostname          string           `json:"hostname"`
-	DocumentationURL  string           `json:"documentationUrl"`
-	Login             settingsTY.Login `json:"login"`
-	StartTime         time.Time        `json:"startTime"`
-	ServerTime        time.Time        `json:"serverTime"`
-	Uptime            uint64           `json:"uptime"` // in seconds
-	MetricsDBDisabled bool             `json:"metricsDBDisabled"`
-	Language          string           `json:"language"`
+	Hostname          string                 `json:"hostname"`
+	DocumentationURL  string                 `json:"documentationUrl"`
+	Login             settingsTY.Login       `json:"login"`
+	StartTime         time.Time              `json:"startTime"`
+	ServerTime        time.Time              `json:"serverTime"`
+	Uptime            uint64                 `json:"uptime"` // in seconds
+	MetricsDBDisabled bool                   `json:"metricsDBDisabled"`
+	Language          string                 `json:"language"`
+	MetricSpool       *metricSVC.SpoolStatus `json:"metricSpool,omitempty"`
 }
 
 func get(minimal bool) Status {
@@ -55,6 +57,9 @@ func get(minimal bool) Status {
 		status.ServerTime = time.Now()
 		status.StartTime = startTime
 		status.Uptime = uint64(time.Since(startTime).Seconds())
+
+		spoolStatus := metricSVC.GetSpoolStatus()
+		status.MetricSpool = &spoolStatus
 	}
 
 	// include login message
@@ -8,6 +8,7 @@ import (
 	taskTY "github.com/mycontroller-org/server/v2/pkg/types/task"
 	"github.com/mycontroller-org/server/v2/pkg/utils"
 	busUtils "github.com/mycontroller-org/server/v2/pkg/utils/bus_utils"
+	"github.com/mycontroller-org/server/v2/pkg/utils/expression"
 	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
 )
 
@@ -26,6 +27,16 @@ func Get(filters []storageTY.Filter) (*taskTY.Config, error) {
 
 // Save a task details
 func Save(task *taskTY.Config) error {
+	err := task.Validate()
+	if err != nil {
+		return err
+	}
+	if task.EvaluationType == taskTY.EvaluationTypeExpression {
+		_, err = expression.Compile(task.EvaluationConfig.Expression, task.ExpressionVariableNames())
+		if err != nil {
+			return err
+		}
+	}
 	eventType := eventTY.TypeUpdated
 	if task.ID == "" {
 		task.ID = utils.RandUUID()
@@ -34,7 +45,7 @@ func Save(task *taskTY.Config) error {
 	filters := []storageTY.Filter{
 		{Key: types.KeyID, Value: task.ID},
 	}
-	err := store.STORAGE.Upsert(types.EntityTask, task, filters)
+	err = store.STORAGE.Upsert(types.EntityTask, task, filters)
 	if err != nil {
 		return err
 	}
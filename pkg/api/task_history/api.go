@@ -0,0 +1,107 @@
+package taskhistory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mycontroller-org/server/v2/pkg/store"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	taskHistoryTY "github.com/mycontroller-org/server/v2/pkg/types/task_history"
+	"github.com/mycontroller-org/server/v2/pkg/utils"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultRetention = "720h" // 30 days
+)
+
+// handler results are updated concurrently
+var updateMutex sync.Mutex
+
+// List by filter and pagination
+func List(filters []storageTY.Filter, pagination *storageTY.Pagination) (*storageTY.Result, error) {
+	result := make([]taskHistoryTY.Entry, 0)
+	return store.STORAGE.Find(types.EntityTaskHistory, &result, filters, pagination)
+}
+
+// GetByID returns a entry
+func GetByID(id string) (*taskHistoryTY.Entry, error) {
+	filters := []storageTY.Filter{
+		{Key: types.KeyID, Value: id},
+	}
+	result := &taskHistoryTY.Entry{}
+	err := store.STORAGE.FindOne(types.EntityTaskHistory, result, filters)
+	return result, err
+}
+
+// Add a task history entry
+func Add(entry *taskHistoryTY.Entry) error {
+	if entry.ID == "" {
+		entry.ID = utils.RandUUID()
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+	return store.STORAGE.Insert(types.EntityTaskHistory, entry)
+}
+
+// Save a task history entry, used on restore
+func Save(entry *taskHistoryTY.Entry) error {
+	if entry.ID == "" {
+		entry.ID = utils.RandUUID()
+	}
+	filters := []storageTY.Filter{
+		{Key: types.KeyID, Value: entry.ID},
+	}
+	return store.STORAGE.Upsert(types.EntityTaskHistory, entry, filters)
+}
+
+// SetHandlerResult updates the handler result on the entry
+func SetHandlerResult(id string, result *taskHistoryTY.HandlerResult) error {
+	updateMutex.Lock()
+	defer updateMutex.Unlock()
+
+	entry, err := GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	updated := false
+	for index := range entry.Handlers {
+		if entry.Handlers[index].HandlerID == result.HandlerID {
+			entry.Handlers[index] = *result
+			updated = true
+			break
+		}
+	}
+	if !updated {
+		entry.Handlers = append(entry.Handlers, *result)
+	}
+	return Save(entry)
+}
+
+// AddActionResult appends the action result on the entry
+func AddActionResult(id string, result *taskHistoryTY.ActionResult) error {
+	updateMutex.Lock()
+	defer updateMutex.Unlock()
+
+	entry, err := GetByID(id)
+	if err != nil {
+		return err
+	}
+	entry.Actions = append(entry.Actions, *result)
+	return Save(entry)
+}
+
+// Purge removes the entries older than the retention duration
+func Purge(retention time.Duration) (int64, error) {
+	olderThan := time.Now().Add(-retention)
+	filters := []storageTY.Filter{{Key: types.KeyTimestamp, Operator: storageTY.OperatorLessThan, Value: olderThan}}
+	deleted, err := store.STORAGE.Delete(types.EntityTaskHistory, filters)
+	if err != nil {
+		return 0, err
+	}
+	zap.L().Debug("purged task history entries", zap.Int64("deleted", deleted), zap.String("retention", retention.String()))
+	return deleted, nil
+}
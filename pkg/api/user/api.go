@@ -2,12 +2,16 @@ package user
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
+	roleAPI "github.com/mycontroller-org/server/v2/pkg/api/role"
+	sessionAPI "github.com/mycontroller-org/server/v2/pkg/api/session"
 	"github.com/mycontroller-org/server/v2/pkg/service/configuration"
 	"github.com/mycontroller-org/server/v2/pkg/store"
 	types "github.com/mycontroller-org/server/v2/pkg/types"
+	roleTY "github.com/mycontroller-org/server/v2/pkg/types/role"
 	userTY "github.com/mycontroller-org/server/v2/pkg/types/user"
 	"github.com/mycontroller-org/server/v2/pkg/utils"
 	"github.com/mycontroller-org/server/v2/pkg/utils/hashed"
@@ -71,8 +75,11 @@ func Save(user *userTY.User) error {
 	return store.STORAGE.Upsert(types.EntityUser, user, filters)
 }
 
-// Delete items
+// Delete items, revokes the sessions of the users
 func Delete(IDs []string) (int64, error) {
+	if _, err := sessionAPI.DeleteByUserIDs(IDs); err != nil {
+		return 0, err
+	}
 	filters := []storageTY.Filter{{Key: types.KeyID, Operator: storageTY.OperatorIn, Value: IDs}}
 	return store.STORAGE.Delete(types.EntityUser, filters)
 }
@@ -119,3 +126,85 @@ func UpdateProfile(userData *userTY.UserProfileUpdate) error {
 
 	return Save(&user)
 }
+
+// SaveWithPassword creates or updates a user, used by administrators
+// supplied password will be hashed, existing password retained if the password is empty
+func SaveWithPassword(user *userTY.User) error {
+	if strings.TrimSpace(user.Username) == "" {
+		return errors.New("username can not be empty")
+	}
+	if len(user.Roles) == 0 {
+		return errors.New("at least one role required")
+	}
+	for _, roleID := range user.Roles {
+		if _, err := roleAPI.GetByID(roleID); err != nil {
+			return fmt.Errorf("invalid role:%s, error:%s", roleID, err.Error())
+		}
+	}
+
+	// verify the username is not used by another user
+	existingUser, err := GetByUsername(user.Username)
+	if err == nil && existingUser.ID != user.ID {
+		return fmt.Errorf("username '%s' is already in use", user.Username)
+	}
+
+	// two factor details are managed by the user, administrator can update only the required flag
+	// external identity details are managed by the login flow
+	twoFactor := userTY.TwoFactor{Required: user.TwoFactor.Required}
+	provider, externalID := "", ""
+	if user.ID != "" {
+		if oldUser, err := GetByID(user.ID); err == nil {
+			twoFactor = oldUser.TwoFactor
+			twoFactor.Required = user.TwoFactor.Required
+			provider, externalID = oldUser.Provider, oldUser.ExternalID
+		}
+	}
+	user.TwoFactor = twoFactor
+	user.Provider = provider
+	user.ExternalID = externalID
+
+	password := strings.TrimSpace(user.Password)
+	if password != "" && user.Provider != "" {
+		return errors.New("password can not be set to the users of an external identity provider")
+	} else if password != "" {
+		hashedPassword, err := hashed.GenerateHash(password)
+		if err != nil {
+			return err
+		}
+		user.Password = hashedPassword
+	} else if user.ID != "" {
+		oldUser, err := GetByID(user.ID)
+		if err != nil {
+			return fmt.Errorf("unable to get user with id:%s, error:%s", user.ID, err.Error())
+		}
+		user.Password = oldUser.Password
+	} else {
+		return errors.New("password can not be empty")
+	}
+	return Save(user)
+}
+
+// UpdateMissingRoles assigns admin role to the users without roles
+// users created before the role based access control had full access
+func UpdateMissingRoles() error {
+	result, err := List(nil, nil)
+	if err != nil {
+		return err
+	}
+	users, ok := result.Data.(*[]userTY.User)
+	if !ok {
+		return fmt.Errorf("invalid user list type:%T", result.Data)
+	}
+	for index := range *users {
+		user := (*users)[index]
+		if len(user.Roles) > 0 {
+			continue
+		}
+		user.Roles = []string{roleTY.RoleAdmin}
+		err = Save(&user)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
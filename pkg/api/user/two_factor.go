@@ -0,0 +1,221 @@
+package user
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/mycontroller-org/server/v2/pkg/store"
+	userTY "github.com/mycontroller-org/server/v2/pkg/types/user"
+	"github.com/mycontroller-org/server/v2/pkg/utils/hashed"
+	"github.com/mycontroller-org/server/v2/pkg/utils/totp"
+)
+
+const (
+	twoFactorIssuer       = "MyController"
+	recoveryCodesCount    = 10
+	recoveryCodeLength    = 10
+	recoveryCodeAlphabets = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+var (
+	// ErrTwoFactorCodeRequired returned on the login, if the code is not supplied
+	ErrTwoFactorCodeRequired = errors.New("two factor authentication code required")
+	// ErrTwoFactorInvalidCode returned on the login, if the supplied code is not valid
+	ErrTwoFactorInvalidCode = errors.New("invalid two factor authentication code")
+)
+
+// BeginTwoFactorEnrollment generates a new secret, should be confirmed with a valid code
+func BeginTwoFactorEnrollment(userID string) (*userTY.TwoFactorEnrollment, error) {
+	user, err := GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactor.Enabled {
+		return nil, errors.New("two factor authentication is enabled already")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encryptedSecret, err := hashed.Encrypt(secret, store.CFG.Secret, "")
+	if err != nil {
+		return nil, err
+	}
+	user.TwoFactor.PendingSecret = encryptedSecret
+	err = Save(&user)
+	if err != nil {
+		return nil, err
+	}
+
+	account := user.Username
+	if user.Email != "" {
+		account = user.Email
+	}
+	return &userTY.TwoFactorEnrollment{
+		Secret: secret,
+		URI:    totp.GetProvisioningURI(twoFactorIssuer, account, secret),
+	}, nil
+}
+
+// ConfirmTwoFactorEnrollment enables two factor authentication and returns the recovery codes
+func ConfirmTwoFactorEnrollment(userID, code string) ([]string, error) {
+	user, err := GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactor.PendingSecret == "" {
+		return nil, errors.New("two factor enrollment not started")
+	}
+
+	secret, err := hashed.Decrypt(user.TwoFactor.PendingSecret, store.CFG.Secret, "")
+	if err != nil {
+		return nil, err
+	}
+	valid, step := totp.Validate(secret, code, time.Now())
+	if !valid {
+		return nil, ErrTwoFactorInvalidCode
+	}
+
+	recoveryCodes, hashedCodes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	user.TwoFactor.Enabled = true
+	user.TwoFactor.Secret = user.TwoFactor.PendingSecret
+	user.TwoFactor.PendingSecret = ""
+	user.TwoFactor.RecoveryCodes = hashedCodes
+	user.TwoFactor.LastUsedStep = step
+	err = Save(&user)
+	if err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+// DisableTwoFactor disables two factor authentication, requires a valid code
+func DisableTwoFactor(userID, code string) error {
+	user, err := GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactor.Enabled {
+		return errors.New("two factor authentication is not enabled")
+	}
+	if user.TwoFactor.Required {
+		return errors.New("two factor authentication is required by the administrator")
+	}
+	err = VerifyTwoFactor(&user, code)
+	if err != nil {
+		return err
+	}
+	user.TwoFactor = userTY.TwoFactor{Required: user.TwoFactor.Required}
+	return Save(&user)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes, requires a valid code
+func RegenerateRecoveryCodes(userID, code string) ([]string, error) {
+	user, err := GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TwoFactor.Enabled {
+		return nil, errors.New("two factor authentication is not enabled")
+	}
+	err = VerifyTwoFactor(&user, code)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, hashedCodes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	user.TwoFactor.RecoveryCodes = hashedCodes
+	err = Save(&user)
+	if err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+// ResetTwoFactor removes the two factor authentication of the users, used by administrators
+// the required flag retained, users have to enroll again on the next login
+func ResetTwoFactor(IDs []string) error {
+	for _, id := range IDs {
+		user, err := GetByID(id)
+		if err != nil {
+			return fmt.Errorf("error on getting a user:%s, error:%s", id, err.Error())
+		}
+		user.TwoFactor = userTY.TwoFactor{Required: user.TwoFactor.Required}
+		err = Save(&user)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyTwoFactor verifies the time based code or a recovery code
+// used recovery code will be removed, used time step will be recorded
+func VerifyTwoFactor(user *userTY.User, code string) error {
+	if !user.TwoFactor.Enabled {
+		return nil
+	}
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return ErrTwoFactorCodeRequired
+	}
+
+	secret, err := hashed.Decrypt(user.TwoFactor.Secret, store.CFG.Secret, "")
+	if err != nil {
+		return err
+	}
+	if valid, step := totp.Validate(secret, code, time.Now()); valid {
+		if step <= user.TwoFactor.LastUsedStep {
+			return ErrTwoFactorInvalidCode
+		}
+		user.TwoFactor.LastUsedStep = step
+		return Save(user)
+	}
+
+	// verify the recovery codes
+	code = strings.ToLower(strings.ReplaceAll(code, "-", ""))
+	for index, hashedCode := range user.TwoFactor.RecoveryCodes {
+		if hashed.IsValidPassword(hashedCode, code) {
+			user.TwoFactor.RecoveryCodes = append(user.TwoFactor.RecoveryCodes[:index], user.TwoFactor.RecoveryCodes[index+1:]...)
+			return Save(user)
+		}
+	}
+	return ErrTwoFactorInvalidCode
+}
+
+// generateRecoveryCodes returns the recovery codes and the hashed recovery codes
+func generateRecoveryCodes() ([]string, []string, error) {
+	recoveryCodes := make([]string, 0, recoveryCodesCount)
+	hashedCodes := make([]string, 0, recoveryCodesCount)
+	max := big.NewInt(int64(len(recoveryCodeAlphabets)))
+	for count := 0; count < recoveryCodesCount; count++ {
+		code := make([]byte, recoveryCodeLength)
+		for index := range code {
+			position, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return nil, nil, err
+			}
+			code[index] = recoveryCodeAlphabets[position.Int64()]
+		}
+		hashedCode, err := hashed.GenerateHash(string(code))
+		if err != nil {
+			return nil, nil, err
+		}
+		// formatted as "xxxxx-xxxxx" to make it readable
+		recoveryCodes = append(recoveryCodes, fmt.Sprintf("%s-%s", code[:recoveryCodeLength/2], code[recoveryCodeLength/2:]))
+		hashedCodes = append(hashedCodes, hashedCode)
+	}
+	return recoveryCodes, hashedCodes, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/mycontroller-org/server/v2/pkg/json"
 	types "github.com/mycontroller-org/server/v2/pkg/types"
 	backupTY "github.com/mycontroller-org/server/v2/pkg/types/backup"
+	secretTY "github.com/mycontroller-org/server/v2/pkg/types/secret"
 	userTY "github.com/mycontroller-org/server/v2/pkg/types/user"
 	"github.com/mycontroller-org/server/v2/pkg/utils"
 	"github.com/mycontroller-org/server/v2/pkg/utils/concurrency"
@@ -123,6 +124,20 @@ func dump(targetDir, entityName string, index int, data interface{}, storageExpo
 			zap.L().Error("error on converting the data to user slice, continue with default data type", zap.String("inputType", fmt.Sprintf("%T", data)))
 		}
 	}
+	// update secret to secretWithValue to keep the encrypted value on the json export
+	if entityName == types.EntitySecret {
+		if secrets, ok := data.(*[]secretTY.Secret); ok {
+			secretsWithValue := make([]secretTY.SecretWithValue, 0)
+			for _, secret := range *secrets {
+				secretsWithValue = append(secretsWithValue, secretTY.SecretWithValue(secret))
+			}
+			if len(secretsWithValue) > 0 {
+				data = secretsWithValue
+			}
+		} else {
+			zap.L().Error("error on converting the data to secret slice, continue with default data type", zap.String("inputType", fmt.Sprintf("%T", data)))
+		}
+	}
 	var dataBytes []byte
 	var err error
 	switch storageExportType {
@@ -1,6 +1,7 @@
 package br_map
 
 import (
+	auditAPI "github.com/mycontroller-org/server/v2/pkg/api/audit"
 	dashboardAPI "github.com/mycontroller-org/server/v2/pkg/api/dashboard"
 	dataRepositoryAPI "github.com/mycontroller-org/server/v2/pkg/api/data_repository"
 	fieldAPI "github.com/mycontroller-org/server/v2/pkg/api/field"
@@ -9,11 +10,17 @@ import (
 	gatewayAPI "github.com/mycontroller-org/server/v2/pkg/api/gateway"
 	notificationHandlerAPI "github.com/mycontroller-org/server/v2/pkg/api/handler"
 	nodeAPI "github.com/mycontroller-org/server/v2/pkg/api/node"
+	roleAPI "github.com/mycontroller-org/server/v2/pkg/api/role"
+	sceneAPI "github.com/mycontroller-org/server/v2/pkg/api/scene"
 	scheduleAPI "github.com/mycontroller-org/server/v2/pkg/api/schedule"
+	scriptModuleAPI "github.com/mycontroller-org/server/v2/pkg/api/script_module"
+	secretAPI "github.com/mycontroller-org/server/v2/pkg/api/secret"
 	svcTokenAPI "github.com/mycontroller-org/server/v2/pkg/api/service_token"
+	sessionAPI "github.com/mycontroller-org/server/v2/pkg/api/session"
 	settingsAPI "github.com/mycontroller-org/server/v2/pkg/api/settings"
 	sourceAPI "github.com/mycontroller-org/server/v2/pkg/api/source"
 	taskAPI "github.com/mycontroller-org/server/v2/pkg/api/task"
+	taskHistoryAPI "github.com/mycontroller-org/server/v2/pkg/api/task_history"
 	userAPI "github.com/mycontroller-org/server/v2/pkg/api/user"
 	vaAPI "github.com/mycontroller-org/server/v2/pkg/api/virtual_assistant"
 	vdAPI "github.com/mycontroller-org/server/v2/pkg/api/virtual_device"
@@ -39,5 +46,12 @@ var (
 		types.EntityVirtualAssistant: vaAPI.List,
 		types.EntityVirtualDevice:    vdAPI.List,
 		types.EntityServiceToken:     svcTokenAPI.List,
+		types.EntityRole:             roleAPI.List,
+		types.EntityAuditLog:         auditAPI.List,
+		types.EntityTaskHistory:      taskHistoryAPI.List,
+		types.EntitySession:          sessionAPI.List,
+		types.EntitySecret:           secretAPI.List,
+		types.EntityScriptModule:     scriptModuleAPI.List,
+		types.EntityScene:            sceneAPI.List,
 	}
 )
@@ -3,6 +3,7 @@ package br_map
 import (
 	"fmt"
 
+	auditAPI "github.com/mycontroller-org/server/v2/pkg/api/audit"
 	dashboardAPI "github.com/mycontroller-org/server/v2/pkg/api/dashboard"
 	dataRepositoryAPI "github.com/mycontroller-org/server/v2/pkg/api/data_repository"
 	fieldAPI "github.com/mycontroller-org/server/v2/pkg/api/field"
@@ -11,15 +12,22 @@ import (
 	gwAPI "github.com/mycontroller-org/server/v2/pkg/api/gateway"
 	handlerAPI "github.com/mycontroller-org/server/v2/pkg/api/handler"
 	nodeAPI "github.com/mycontroller-org/server/v2/pkg/api/node"
+	roleAPI "github.com/mycontroller-org/server/v2/pkg/api/role"
+	sceneAPI "github.com/mycontroller-org/server/v2/pkg/api/scene"
 	scheduleAPI "github.com/mycontroller-org/server/v2/pkg/api/schedule"
+	scriptModuleAPI "github.com/mycontroller-org/server/v2/pkg/api/script_module"
+	secretAPI "github.com/mycontroller-org/server/v2/pkg/api/secret"
 	svcTokenAPI "github.com/mycontroller-org/server/v2/pkg/api/service_token"
+	sessionAPI "github.com/mycontroller-org/server/v2/pkg/api/session"
 	settingsAPI "github.com/mycontroller-org/server/v2/pkg/api/settings"
 	sourceAPI "github.com/mycontroller-org/server/v2/pkg/api/source"
 	taskAPI "github.com/mycontroller-org/server/v2/pkg/api/task"
+	taskHistoryAPI "github.com/mycontroller-org/server/v2/pkg/api/task_history"
 	userAPI "github.com/mycontroller-org/server/v2/pkg/api/user"
 	vaAPI "github.com/mycontroller-org/server/v2/pkg/api/virtual_assistant"
 	vdAPI "github.com/mycontroller-org/server/v2/pkg/api/virtual_device"
 	types "github.com/mycontroller-org/server/v2/pkg/types"
+	auditTY "github.com/mycontroller-org/server/v2/pkg/types/audit"
 	backupTY "github.com/mycontroller-org/server/v2/pkg/types/backup"
 	dashboardTY "github.com/mycontroller-org/server/v2/pkg/types/dashboard"
 	dataRepositoryTY "github.com/mycontroller-org/server/v2/pkg/types/data_repository"
@@ -27,11 +35,17 @@ import (
 	firmwareTY "github.com/mycontroller-org/server/v2/pkg/types/firmware"
 	fwdPayloadTY "github.com/mycontroller-org/server/v2/pkg/types/forward_payload"
 	nodeTY "github.com/mycontroller-org/server/v2/pkg/types/node"
+	roleTY "github.com/mycontroller-org/server/v2/pkg/types/role"
+	sceneTY "github.com/mycontroller-org/server/v2/pkg/types/scene"
 	scheduleTY "github.com/mycontroller-org/server/v2/pkg/types/schedule"
+	scriptModuleTY "github.com/mycontroller-org/server/v2/pkg/types/script_module"
+	secretTY "github.com/mycontroller-org/server/v2/pkg/types/secret"
 	svcTokenTY "github.com/mycontroller-org/server/v2/pkg/types/service_token"
+	sessionTY "github.com/mycontroller-org/server/v2/pkg/types/session"
 	settingsTY "github.com/mycontroller-org/server/v2/pkg/types/settings"
 	sourceTY "github.com/mycontroller-org/server/v2/pkg/types/source"
 	taskTY "github.com/mycontroller-org/server/v2/pkg/types/task"
+	taskHistoryTY "github.com/mycontroller-org/server/v2/pkg/types/task_history"
 	userTY "github.com/mycontroller-org/server/v2/pkg/types/user"
 	vaTY "github.com/mycontroller-org/server/v2/pkg/types/virtual_assistant"
 	vdTY "github.com/mycontroller-org/server/v2/pkg/types/virtual_device"
@@ -201,5 +215,75 @@ var (
 				return fmt.Errorf("invalid type:%T", data)
 			},
 		},
+
+		types.EntityRole: {
+			EntityType: roleTY.Role{},
+			API: func(data interface{}) error {
+				if input, ok := data.(roleTY.Role); ok {
+					return roleAPI.Save(&input)
+				}
+				return fmt.Errorf("invalid type:%T", data)
+			},
+		},
+
+		types.EntityAuditLog: {
+			EntityType: auditTY.AuditLog{},
+			API: func(data interface{}) error {
+				if input, ok := data.(auditTY.AuditLog); ok {
+					return auditAPI.Save(&input)
+				}
+				return fmt.Errorf("invalid type:%T", data)
+			},
+		},
+
+		types.EntityTaskHistory: {
+			EntityType: taskHistoryTY.Entry{},
+			API: func(data interface{}) error {
+				if input, ok := data.(taskHistoryTY.Entry); ok {
+					return taskHistoryAPI.Save(&input)
+				}
+				return fmt.Errorf("invalid type:%T", data)
+			},
+		},
+
+		types.EntitySession: {
+			EntityType: sessionTY.Session{},
+			API: func(data interface{}) error {
+				if input, ok := data.(sessionTY.Session); ok {
+					return sessionAPI.Save(&input)
+				}
+				return fmt.Errorf("invalid type:%T", data)
+			},
+		},
+
+		types.EntitySecret: {
+			EntityType: secretTY.Secret{},
+			API: func(data interface{}) error {
+				if input, ok := data.(secretTY.Secret); ok {
+					return secretAPI.Save(&input)
+				}
+				return fmt.Errorf("invalid type:%T", data)
+			},
+		},
+
+		types.EntityScriptModule: {
+			EntityType: scriptModuleTY.Config{},
+			API: func(data interface{}) error {
+				if input, ok := data.(scriptModuleTY.Config); ok {
+					return scriptModuleAPI.Save(&input)
+				}
+				return fmt.Errorf("invalid type:%T", data)
+			},
+		},
+
+		types.EntityScene: {
+			EntityType: sceneTY.Config{},
+			API: func(data interface{}) error {
+				if input, ok := data.(sceneTY.Config); ok {
+					return sceneAPI.Save(&input)
+				}
+				return fmt.Errorf("invalid type:%T", data)
+			},
+		},
 	}
 )
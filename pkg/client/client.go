@@ -0,0 +1,82 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+
+	json "github.com/mycontroller-org/server/v2/pkg/json"
+	handlerTY "github.com/mycontroller-org/server/v2/pkg/types/web_handler"
+	httpUtils "github.com/mycontroller-org/server/v2/pkg/utils/http_client_json"
+)
+
+const (
+	apiLogin   = "/api/user/login"
+	apiVersion = "/api/version"
+	apiOpenAPI = "/api/openapi.json"
+)
+
+// Client of the MyController server rest api
+type Client struct {
+	ServerAddress string
+	Token         string
+	Insecure      bool
+	Timeout       string
+}
+
+// New returns a client, token can be empty and set with login
+func New(serverAddress, token string, insecure bool) *Client {
+	return &Client{ServerAddress: serverAddress, Token: token, Insecure: insecure}
+}
+
+// Login with the username and the password, code required if two factor authentication enabled
+// received token will be used on the following requests
+func (c *Client) Login(username, password, code, expiresIn string) (*handlerTY.JwtTokenResponse, error) {
+	return c.login(&handlerTY.UserLogin{Username: username, Password: password, Code: code, ExpiresIn: expiresIn})
+}
+
+// LoginWithServiceToken exchanges the service token with a jwt token
+func (c *Client) LoginWithServiceToken(serviceToken, expiresIn string) (*handlerTY.JwtTokenResponse, error) {
+	return c.login(&handlerTY.UserLogin{SvcToken: serviceToken, ExpiresIn: expiresIn})
+}
+
+func (c *Client) login(request *handlerTY.UserLogin) (*handlerTY.JwtTokenResponse, error) {
+	result := &handlerTY.JwtTokenResponse{}
+	err := c.Do(http.MethodPost, apiLogin, nil, request, result)
+	if err != nil {
+		return nil, err
+	}
+	c.Token = result.Token
+	return result, nil
+}
+
+// Version returns the server version details
+func (c *Client) Version() (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	err := c.Do(http.MethodGet, apiVersion, nil, nil, &result)
+	return result, err
+}
+
+// OpenAPI returns the OpenAPI specification of the server
+func (c *Client) OpenAPI() (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	err := c.Do(http.MethodGet, apiOpenAPI, nil, nil, &result)
+	return result, err
+}
+
+// Do executes a request and loads the response into the result, result can be nil
+// can be used for the apis not covered on this client
+func (c *Client) Do(method, api string, queryParams map[string]interface{}, body, result interface{}) error {
+	client := httpUtils.New(c.Insecure, c.Timeout)
+	headers := map[string]string{}
+	if c.Token != "" {
+		headers[handlerTY.HeaderAuthorization] = c.Token
+	}
+	res, err := client.ExecuteJson(fmt.Sprintf("%s%s", c.ServerAddress, api), method, headers, queryParams, body, http.StatusOK)
+	if err != nil {
+		return err
+	}
+	if result == nil || len(res.Body) == 0 {
+		return nil
+	}
+	return json.Unmarshal(res.Body, result)
+}
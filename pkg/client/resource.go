@@ -0,0 +1,101 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	json "github.com/mycontroller-org/server/v2/pkg/json"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+)
+
+// ListResult typed list response
+type ListResult[T any] struct {
+	Count  int64 `json:"count"`
+	Limit  int64 `json:"limit"`
+	Offset int64 `json:"offset"`
+	Data   []T   `json:"data"`
+}
+
+// Resource typed operations of a rest resource
+type Resource[T any] struct {
+	client *Client
+	path   string
+}
+
+func newResource[T any](client *Client, path string) *Resource[T] {
+	return &Resource[T]{client: client, path: path}
+}
+
+// List returns the entities, filters and pagination are optional
+func (r *Resource[T]) List(filters []storageTY.Filter, pagination *storageTY.Pagination) (*ListResult[T], error) {
+	queryParams, err := toQueryParams(filters, pagination)
+	if err != nil {
+		return nil, err
+	}
+	result := &ListResult[T]{}
+	err = r.client.Do(http.MethodGet, r.path, queryParams, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Get returns an entity
+func (r *Resource[T]) Get(id string) (*T, error) {
+	result := new(T)
+	err := r.client.Do(http.MethodGet, fmt.Sprintf("%s/%s", r.path, url.PathEscape(id)), nil, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Save creates or updates an entity
+func (r *Resource[T]) Save(entity *T) error {
+	return r.client.Do(http.MethodPost, r.path, nil, entity, nil)
+}
+
+// Delete removes the entities
+func (r *Resource[T]) Delete(ids ...string) error {
+	return r.client.Do(http.MethodDelete, r.path, nil, ids, nil)
+}
+
+// Enable the entities, supported only on the resources those can be enabled
+func (r *Resource[T]) Enable(ids ...string) error {
+	return r.client.Do(http.MethodPost, r.path+"/enable", nil, ids, nil)
+}
+
+// Disable the entities, supported only on the resources those can be disabled
+func (r *Resource[T]) Disable(ids ...string) error {
+	return r.client.Do(http.MethodPost, r.path+"/disable", nil, ids, nil)
+}
+
+// Reload the entities, supported only on the resources those can be reloaded
+func (r *Resource[T]) Reload(ids ...string) error {
+	return r.client.Do(http.MethodPost, r.path+"/reload", nil, ids, nil)
+}
+
+// toQueryParams converts filters and pagination to the query parameters of the list api
+func toQueryParams(filters []storageTY.Filter, pagination *storageTY.Pagination) (map[string]interface{}, error) {
+	queryParams := map[string]interface{}{}
+	if len(filters) > 0 {
+		filterBytes, err := json.Marshal(filters)
+		if err != nil {
+			return nil, err
+		}
+		queryParams["filter"] = string(filterBytes)
+	}
+	if pagination != nil {
+		queryParams["limit"] = pagination.Limit
+		queryParams["offset"] = pagination.Offset
+		if len(pagination.SortBy) > 0 {
+			sortBytes, err := json.Marshal(pagination.SortBy)
+			if err != nil {
+				return nil, err
+			}
+			queryParams["sortBy"] = string(sortBytes)
+		}
+	}
+	return queryParams, nil
+}
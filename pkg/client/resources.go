@@ -0,0 +1,130 @@
+package client
+
+import (
+	auditTY "github.com/mycontroller-org/server/v2/pkg/types/audit"
+	dashboardTY "github.com/mycontroller-org/server/v2/pkg/types/dashboard"
+	dataRepositoryTY "github.com/mycontroller-org/server/v2/pkg/types/data_repository"
+	fieldTY "github.com/mycontroller-org/server/v2/pkg/types/field"
+	firmwareTY "github.com/mycontroller-org/server/v2/pkg/types/firmware"
+	fwdPayloadTY "github.com/mycontroller-org/server/v2/pkg/types/forward_payload"
+	nodeTY "github.com/mycontroller-org/server/v2/pkg/types/node"
+	roleTY "github.com/mycontroller-org/server/v2/pkg/types/role"
+	sceneTY "github.com/mycontroller-org/server/v2/pkg/types/scene"
+	scheduleTY "github.com/mycontroller-org/server/v2/pkg/types/schedule"
+	scriptModuleTY "github.com/mycontroller-org/server/v2/pkg/types/script_module"
+	secretTY "github.com/mycontroller-org/server/v2/pkg/types/secret"
+	svcTokenTY "github.com/mycontroller-org/server/v2/pkg/types/service_token"
+	sourceTY "github.com/mycontroller-org/server/v2/pkg/types/source"
+	taskTY "github.com/mycontroller-org/server/v2/pkg/types/task"
+	taskHistoryTY "github.com/mycontroller-org/server/v2/pkg/types/task_history"
+	userTY "github.com/mycontroller-org/server/v2/pkg/types/user"
+	vaTY "github.com/mycontroller-org/server/v2/pkg/types/virtual_assistant"
+	vdTY "github.com/mycontroller-org/server/v2/pkg/types/virtual_device"
+	gwTY "github.com/mycontroller-org/server/v2/plugin/gateway/types"
+	handlerTY "github.com/mycontroller-org/server/v2/plugin/handler/types"
+)
+
+// Gateways api
+func (c *Client) Gateways() *Resource[gwTY.Config] {
+	return newResource[gwTY.Config](c, "/api/gateway")
+}
+
+// Nodes api
+func (c *Client) Nodes() *Resource[nodeTY.Node] {
+	return newResource[nodeTY.Node](c, "/api/node")
+}
+
+// Sources api
+func (c *Client) Sources() *Resource[sourceTY.Source] {
+	return newResource[sourceTY.Source](c, "/api/source")
+}
+
+// Fields api
+func (c *Client) Fields() *Resource[fieldTY.Field] {
+	return newResource[fieldTY.Field](c, "/api/field")
+}
+
+// Firmwares api
+func (c *Client) Firmwares() *Resource[firmwareTY.Firmware] {
+	return newResource[firmwareTY.Firmware](c, "/api/firmware")
+}
+
+// Dashboards api
+func (c *Client) Dashboards() *Resource[dashboardTY.Config] {
+	return newResource[dashboardTY.Config](c, "/api/dashboard")
+}
+
+// ForwardPayloads api
+func (c *Client) ForwardPayloads() *Resource[fwdPayloadTY.Config] {
+	return newResource[fwdPayloadTY.Config](c, "/api/forwardpayload")
+}
+
+// Tasks api
+func (c *Client) Tasks() *Resource[taskTY.Config] {
+	return newResource[taskTY.Config](c, "/api/task")
+}
+
+// Handlers api
+func (c *Client) Handlers() *Resource[handlerTY.Config] {
+	return newResource[handlerTY.Config](c, "/api/handler")
+}
+
+// Schedules api
+func (c *Client) Schedules() *Resource[scheduleTY.Config] {
+	return newResource[scheduleTY.Config](c, "/api/schedule")
+}
+
+// DataRepositories api
+func (c *Client) DataRepositories() *Resource[dataRepositoryTY.Config] {
+	return newResource[dataRepositoryTY.Config](c, "/api/datarepository")
+}
+
+// VirtualDevices api
+func (c *Client) VirtualDevices() *Resource[vdTY.VirtualDevice] {
+	return newResource[vdTY.VirtualDevice](c, "/api/virtualdevice")
+}
+
+// VirtualAssistants api
+func (c *Client) VirtualAssistants() *Resource[vaTY.Config] {
+	return newResource[vaTY.Config](c, "/api/virtualassistant")
+}
+
+// ServiceTokens api, use create and update apis via Do to manage the tokens
+func (c *Client) ServiceTokens() *Resource[svcTokenTY.ServiceToken] {
+	return newResource[svcTokenTY.ServiceToken](c, "/api/servicetoken")
+}
+
+// Roles api
+func (c *Client) Roles() *Resource[roleTY.Role] {
+	return newResource[roleTY.Role](c, "/api/role")
+}
+
+// Users api
+func (c *Client) Users() *Resource[userTY.User] {
+	return newResource[userTY.User](c, "/api/user")
+}
+
+// AuditLogs api, read only
+func (c *Client) AuditLogs() *Resource[auditTY.AuditLog] {
+	return newResource[auditTY.AuditLog](c, "/api/audit")
+}
+
+// TaskHistory api, read only
+func (c *Client) TaskHistory() *Resource[taskHistoryTY.Entry] {
+	return newResource[taskHistoryTY.Entry](c, "/api/taskhistory")
+}
+
+// Secrets api, values are not returned
+func (c *Client) Secrets() *Resource[secretTY.Secret] {
+	return newResource[secretTY.Secret](c, "/api/secret")
+}
+
+// ScriptModules api
+func (c *Client) ScriptModules() *Resource[scriptModuleTY.Config] {
+	return newResource[scriptModuleTY.Config](c, "/api/scriptmodule")
+}
+
+// Scenes api
+func (c *Client) Scenes() *Resource[sceneTY.Config] {
+	return newResource[sceneTY.Config](c, "/api/scene")
+}
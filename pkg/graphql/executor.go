@@ -0,0 +1,554 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+
+	json "github.com/mycontroller-org/server/v2/pkg/json"
+	"github.com/mycontroller-org/server/v2/pkg/utils/convertor"
+)
+
+const typeNameField = "__typename"
+
+// orderedMap keeps the fields in the requested order on the response
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (m *orderedMap) set(key string, value interface{}) {
+	if _, found := m.values[key]; !found {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// MarshalJSON implementation
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBufferString("{")
+	for index, key := range m.keys {
+		if index > 0 {
+			buffer.WriteByte(',')
+		}
+		keyBytes, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		valueBytes, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(keyBytes)
+		buffer.WriteByte(':')
+		buffer.Write(valueBytes)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+type executor struct {
+	schema         *Schema
+	ctx            context.Context
+	doc            *document
+	variables      map[string]interface{}
+	errors         []*Error
+	resolveCount   int  // resolver calls and list items of the request
+	budgetExceeded bool // reported once, remaining fields are not resolved
+	fieldCount     map[*Field]int
+}
+
+// Execute executes a query request
+func (s *Schema) Execute(ctx context.Context, request *Request) *Response {
+	ex, op, err := s.prepare(ctx, request)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	switch op.operationType {
+	case OperationQuery:
+		data := ex.executeSelections(s.Query, nil, op.selections, nil)
+		return &Response{Data: data, Errors: ex.errors}
+
+	case OperationSubscription:
+		return errorResponse(fmt.Errorf("subscriptions supported only on the websocket"))
+
+	default:
+		return errorResponse(fmt.Errorf("operation '%s' is not supported", op.operationType))
+	}
+}
+
+// Subscribe executes a subscription request, returns a channel of responses
+// the channel will be closed, when the context is done or the source closes
+// query operations returns a single response
+func (s *Schema) Subscribe(ctx context.Context, request *Request) (<-chan *Response, error) {
+	ex, op, err := s.prepare(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if op.operationType == OperationQuery {
+		responseChannel := make(chan *Response, 1)
+		data := ex.executeSelections(s.Query, nil, op.selections, nil)
+		responseChannel <- &Response{Data: data, Errors: ex.errors}
+		close(responseChannel)
+		return responseChannel, nil
+	}
+	if op.operationType != OperationSubscription {
+		return nil, fmt.Errorf("operation '%s' is not supported", op.operationType)
+	}
+	if s.Subscription == nil {
+		return nil, fmt.Errorf("subscriptions are not supported")
+	}
+
+	keys, fields := ex.collectFields(s.Subscription.Name, op.selections, map[string]bool{})
+	if len(ex.errors) > 0 {
+		return nil, ex.errors[0]
+	}
+	if len(keys) != 1 {
+		return nil, fmt.Errorf("subscription should select only one field")
+	}
+	rootField := fields[keys[0]][0]
+	definition, found := s.Subscription.Fields[rootField.name]
+	if !found || definition.Subscribe == nil {
+		return nil, fmt.Errorf("subscription '%s' is not available", rootField.name)
+	}
+
+	sourceChannel, err := definition.Subscribe(ResolveParams{Context: ctx, Args: ex.resolveArguments(rootField.arguments)})
+	if err != nil {
+		return nil, err
+	}
+
+	responseChannel := make(chan *Response)
+	go func() {
+		defer close(responseChannel)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case value, ok := <-sourceChannel:
+				if !ok {
+					return
+				}
+				// executes the selections on each event with a fresh executor
+				eventExecutor := &executor{schema: s, ctx: ctx, doc: ex.doc, variables: ex.variables}
+				data := &orderedMap{values: make(map[string]interface{})}
+				key := rootField.responseKey()
+				data.set(key, eventExecutor.completeValue(definition.Type, value, rootField.selections, []interface{}{key}))
+				select {
+				case responseChannel <- &Response{Data: data, Errors: eventExecutor.errors}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return responseChannel, nil
+}
+
+// prepare parses the query and returns the executor and the selected operation
+func (s *Schema) prepare(ctx context.Context, request *Request) (*executor, *operation, error) {
+	doc, err := parse(request.Query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var selected *operation
+	for _, op := range doc.operations {
+		if request.OperationName == "" || op.name == request.OperationName {
+			if selected != nil {
+				return nil, nil, fmt.Errorf("operation name required, when the query contains multiple operations")
+			}
+			selected = op
+		}
+	}
+	if selected == nil {
+		return nil, nil, fmt.Errorf("operation '%s' not found", request.OperationName)
+	}
+
+	// fragments spreading itself are expanded on each level of the execution
+	if name := fragmentCycle(doc); name != "" {
+		return nil, nil, fmt.Errorf("fragment '%s' spreads itself", name)
+	}
+
+	if s.MaxDepth > 0 {
+		if depth := selectionsDepth(doc, selected.selections, map[string]bool{}, map[string]int{}); depth > s.MaxDepth {
+			return nil, nil, fmt.Errorf("query depth %d exceeds the limit %d", depth, s.MaxDepth)
+		}
+	}
+
+	// update variables, with the default values
+	variables := make(map[string]interface{})
+	for _, definition := range selected.variables {
+		if value, found := request.Variables[definition.name]; found {
+			variables[definition.name] = value
+		} else if definition.defaultValue != nil {
+			variables[definition.name] = definition.defaultValue
+		}
+	}
+
+	ex := &executor{schema: s, ctx: ctx, doc: doc, variables: variables}
+	return ex, selected, nil
+}
+
+func (ex *executor) addError(err error, path []interface{}) {
+	ex.errors = append(ex.errors, &Error{Message: err.Error(), Path: path})
+}
+
+// consume counts a resolver call or a list item, returns false when the budget exceeded
+func (ex *executor) consume(path []interface{}) bool {
+	if ex.budgetExceeded {
+		return false
+	}
+	ex.resolveCount++
+	if ex.schema.MaxResolve > 0 && ex.resolveCount > ex.schema.MaxResolve {
+		ex.budgetExceeded = true
+		ex.addError(fmt.Errorf("query exceeds the limit of %d resolved fields and items", ex.schema.MaxResolve), path)
+		return false
+	}
+	return true
+}
+
+// consumeField counts a resolver call of the field, returns false when the field limit exceeded
+// exceeded limit reported once for each field
+func (ex *executor) consumeField(definition *Field, name string, path []interface{}) bool {
+	if definition.MaxResolve <= 0 {
+		return true
+	}
+	if ex.fieldCount == nil {
+		ex.fieldCount = make(map[*Field]int)
+	}
+	ex.fieldCount[definition]++
+	if ex.fieldCount[definition] > definition.MaxResolve {
+		if ex.fieldCount[definition] == definition.MaxResolve+1 {
+			ex.addError(fmt.Errorf("query exceeds the limit of %d calls on the field '%s'", definition.MaxResolve, name), path)
+		}
+		return false
+	}
+	return true
+}
+
+// executeSelections resolves the selected fields of an object
+// object can be nil for the json values, fields resolved from the json representation
+func (ex *executor) executeSelections(object *Object, source interface{}, selections []selection, path []interface{}) *orderedMap {
+	typeName := ""
+	if object != nil {
+		typeName = object.Name
+	}
+	keys, fields := ex.collectFields(typeName, selections, map[string]bool{})
+
+	result := &orderedMap{values: make(map[string]interface{})}
+	var sourceMap map[string]interface{}
+	var sourceMapErr error
+	for _, key := range keys {
+		fieldPath := appendPath(path, key)
+		selected := fields[key][0]
+
+		// merge the sub selections of the fields with the same response key
+		subSelections := make([]selection, 0)
+		for _, f := range fields[key] {
+			subSelections = append(subSelections, f.selections...)
+		}
+
+		if selected.name == typeNameField {
+			result.set(key, typeName)
+			continue
+		}
+
+		var definition *Field
+		if object != nil {
+			definition = object.Fields[selected.name]
+		}
+
+		var value interface{}
+		var err error
+		childType := ""
+		if definition != nil && definition.Resolve != nil {
+			if !ex.consumeField(definition, selected.name, fieldPath) || !ex.consume(fieldPath) {
+				result.set(key, nil)
+				continue
+			}
+			childType = definition.Type
+			value, err = definition.Resolve(ResolveParams{Context: ex.ctx, Source: source, Args: ex.resolveArguments(selected.arguments)})
+		} else if definition == nil && object != nil && source == nil {
+			err = fmt.Errorf("field '%s' not found on '%s'", selected.name, object.Name)
+		} else {
+			if definition != nil {
+				childType = definition.Type
+			}
+			if sourceMap == nil && sourceMapErr == nil {
+				sourceMap, sourceMapErr = toMap(source)
+			}
+			if sourceMapErr != nil {
+				err = sourceMapErr
+			} else {
+				value = sourceMap[selected.name]
+			}
+		}
+		if err != nil {
+			ex.addError(err, fieldPath)
+			result.set(key, nil)
+			continue
+		}
+		result.set(key, ex.completeValue(childType, value, subSelections, fieldPath))
+	}
+	return result
+}
+
+// completeValue executes the selections on the value
+// without selections, the value returned as is
+func (ex *executor) completeValue(typeName string, value interface{}, selections []selection, path []interface{}) interface{} {
+	if value == nil || len(selections) == 0 {
+		return value
+	}
+
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		items := make([]interface{}, 0, rv.Len())
+		for index := 0; index < rv.Len(); index++ {
+			if !ex.consume(appendPath(path, index)) {
+				break
+			}
+			items = append(items, ex.completeValue(typeName, rv.Index(index).Interface(), selections, appendPath(path, index)))
+		}
+		return items
+	}
+
+	return ex.executeSelections(ex.schema.objects[typeName], value, selections, path)
+}
+
+// collectFields returns the response keys in order and the fields of each key
+// fragments and the skip, include directives are processed here
+func (ex *executor) collectFields(typeName string, selections []selection, visitedFragments map[string]bool) ([]string, map[string][]*field) {
+	keys := make([]string, 0)
+	fields := make(map[string][]*field)
+
+	add := func(subKeys []string, subFields map[string][]*field) {
+		for _, key := range subKeys {
+			if _, found := fields[key]; !found {
+				keys = append(keys, key)
+			}
+			fields[key] = append(fields[key], subFields[key]...)
+		}
+	}
+
+	for _, item := range selections {
+		switch selected := item.(type) {
+		case *field:
+			if !ex.isIncluded(selected.directives) {
+				continue
+			}
+			key := selected.responseKey()
+			if _, found := fields[key]; !found {
+				keys = append(keys, key)
+			}
+			fields[key] = append(fields[key], selected)
+
+		case *fragmentSpread:
+			if !ex.isIncluded(selected.directives) || visitedFragments[selected.name] {
+				continue
+			}
+			visitedFragments[selected.name] = true
+			definition, found := ex.doc.fragments[selected.name]
+			if !found {
+				ex.addError(fmt.Errorf("fragment '%s' not found", selected.name), nil)
+				continue
+			}
+			if !matchesType(definition.typeCondition, typeName) {
+				continue
+			}
+			add(ex.collectFields(typeName, definition.selections, visitedFragments))
+
+		case *inlineFragment:
+			if !ex.isIncluded(selected.directives) || !matchesType(selected.typeCondition, typeName) {
+				continue
+			}
+			add(ex.collectFields(typeName, selected.selections, visitedFragments))
+		}
+	}
+	return keys, fields
+}
+
+// selectionsDepth returns the maximum depth of the nested fields, fragments are expanded
+// depth of the fragments cached, to avoid the repeated expansion
+func selectionsDepth(doc *document, selections []selection, visitedFragments map[string]bool, fragmentsDepth map[string]int) int {
+	maxDepth := 0
+	for _, item := range selections {
+		depth := 0
+		switch selected := item.(type) {
+		case *field:
+			depth = 1 + selectionsDepth(doc, selected.selections, visitedFragments, fragmentsDepth)
+
+		case *fragmentSpread:
+			if cached, found := fragmentsDepth[selected.name]; found {
+				depth = cached
+				break
+			}
+			definition, found := doc.fragments[selected.name]
+			if !found || visitedFragments[selected.name] {
+				continue
+			}
+			visitedFragments[selected.name] = true
+			depth = selectionsDepth(doc, definition.selections, visitedFragments, fragmentsDepth)
+			delete(visitedFragments, selected.name)
+			fragmentsDepth[selected.name] = depth
+
+		case *inlineFragment:
+			depth = selectionsDepth(doc, selected.selections, visitedFragments, fragmentsDepth)
+		}
+		if depth > maxDepth {
+			maxDepth = depth
+		}
+	}
+	return maxDepth
+}
+
+// fragmentCycle returns the name of a fragment spreading itself, directly or via the other fragments
+// empty, if there is no cycle
+func fragmentCycle(doc *document) string {
+	// fragments on the current path and the fragments verified already
+	onPath := make(map[string]bool)
+	verified := make(map[string]bool)
+
+	var visit func(selections []selection) string
+	visit = func(selections []selection) string {
+		for _, item := range selections {
+			switch selected := item.(type) {
+			case *field:
+				if name := visit(selected.selections); name != "" {
+					return name
+				}
+
+			case *inlineFragment:
+				if name := visit(selected.selections); name != "" {
+					return name
+				}
+
+			case *fragmentSpread:
+				if onPath[selected.name] {
+					return selected.name
+				}
+				definition, found := doc.fragments[selected.name]
+				if !found || verified[selected.name] {
+					continue
+				}
+				onPath[selected.name] = true
+				if name := visit(definition.selections); name != "" {
+					return name
+				}
+				delete(onPath, selected.name)
+				verified[selected.name] = true
+			}
+		}
+		return ""
+	}
+
+	for name, definition := range doc.fragments {
+		if verified[name] {
+			continue
+		}
+		onPath[name] = true
+		if cycle := visit(definition.selections); cycle != "" {
+			return cycle
+		}
+		delete(onPath, name)
+		verified[name] = true
+	}
+	return ""
+}
+
+// isIncluded evaluates the skip and include directives
+func (ex *executor) isIncluded(directives []*directive) bool {
+	for _, d := range directives {
+		condition := convertor.ToBool(ex.resolveValue(d.arguments["if"]))
+		switch d.name {
+		case "skip":
+			if condition {
+				return false
+			}
+		case "include":
+			if !condition {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// resolveArguments returns the arguments with the variables values
+func (ex *executor) resolveArguments(arguments map[string]interface{}) map[string]interface{} {
+	resolved := make(map[string]interface{})
+	for name, value := range arguments {
+		resolved[name] = ex.resolveValue(value)
+	}
+	return resolved
+}
+
+func (ex *executor) resolveValue(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case variable:
+		return ex.variables[string(typedValue)]
+
+	case enumValue:
+		return string(typedValue)
+
+	case []interface{}:
+		items := make([]interface{}, 0, len(typedValue))
+		for _, item := range typedValue {
+			items = append(items, ex.resolveValue(item))
+		}
+		return items
+
+	case map[string]interface{}:
+		object := make(map[string]interface{})
+		for key, item := range typedValue {
+			object[key] = ex.resolveValue(item)
+		}
+		return object
+	}
+	return value
+}
+
+// matchesType returns true, if the type condition can be applied on the type
+// json values are not typed, all the conditions applied
+func matchesType(typeCondition, typeName string) bool {
+	return typeCondition == "" || typeName == "" || typeCondition == typeName
+}
+
+// toMap returns the json representation of the value as a map
+func toMap(value interface{}) (map[string]interface{}, error) {
+	if mapValue, ok := value.(map[string]interface{}); ok {
+		return mapValue, nil
+	}
+	if value == nil {
+		return map[string]interface{}{}, nil
+	}
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]interface{})
+	err = json.Unmarshal(valueBytes, &result)
+	if err != nil {
+		return nil, fmt.Errorf("selections not supported on the value type: %T", value)
+	}
+	return result, nil
+}
+
+func appendPath(path []interface{}, element interface{}) []interface{} {
+	newPath := make([]interface{}, 0, len(path)+1)
+	newPath = append(newPath, path...)
+	return append(newPath, element)
+}
+
+func errorResponse(err error) *Response {
+	return &Response{Errors: []*Error{{Message: err.Error()}}}
+}
@@ -0,0 +1,230 @@
+package graphql
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mycontroller-org/server/v2/pkg/json"
+)
+
+type testNode struct {
+	ID     string            `json:"id"`
+	Labels map[string]string `json:"labels"`
+}
+
+// testSchema returns a schema with the recursive node type
+// resolveCount counts the resolver calls of the request
+func testSchema(resolveCount *int) *Schema {
+	node := &Object{Name: "Node", Fields: map[string]*Field{}}
+	node.Fields["parent"] = &Field{
+		Type: "Node",
+		Resolve: func(params ResolveParams) (interface{}, error) {
+			*resolveCount++
+			return &testNode{ID: "parent-" + params.Source.(*testNode).ID}, nil
+		},
+	}
+	query := &Object{Name: "Query", Fields: map[string]*Field{
+		"node": {
+			Type: "Node",
+			Resolve: func(params ResolveParams) (interface{}, error) {
+				*resolveCount++
+				return &testNode{ID: params.GetString("id"), Labels: map[string]string{"room": "kitchen"}}, nil
+			},
+		},
+		"nodes": {
+			Type: "Node",
+			Resolve: func(params ResolveParams) (interface{}, error) {
+				*resolveCount++
+				nodes := make([]*testNode, 0)
+				for index := int64(0); index < params.GetInt("limit", 10); index++ {
+					nodes = append(nodes, &testNode{ID: strings.Repeat("n", int(index)+1)})
+				}
+				return nodes, nil
+			},
+		},
+	}}
+	return NewSchema(query, nil, node)
+}
+
+func execute(t *testing.T, schema *Schema, request *Request) (map[string]interface{}, []*Error) {
+	response := schema.Execute(context.TODO(), request)
+	if response.Data == nil {
+		return nil, response.Errors
+	}
+	data := make(map[string]interface{})
+	if err := json.ToStruct(response.Data, &data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return data, response.Errors
+}
+
+func TestExecuteQuery(t *testing.T) {
+	count := 0
+	schema := testSchema(&count)
+	request := &Request{
+		Query:     `query Q($id: String) { kitchen: node(id: $id) { id labels parent { id } } }`,
+		Variables: map[string]interface{}{"id": "n1"},
+	}
+	data, errors := execute(t, schema, request)
+	if len(errors) > 0 {
+		t.Fatalf("unexpected errors: %v", errors[0])
+	}
+	kitchen, _ := data["kitchen"].(map[string]interface{})
+	if kitchen["id"] != "n1" {
+		t.Errorf("id 'n1' expected, received: %v", kitchen["id"])
+	}
+	if parent, _ := kitchen["parent"].(map[string]interface{}); parent["id"] != "parent-n1" {
+		t.Errorf("parent 'parent-n1' expected, received: %v", kitchen["parent"])
+	}
+	if labels, _ := kitchen["labels"].(map[string]interface{}); labels["room"] != "kitchen" {
+		t.Errorf("labels from the json representation expected, received: %v", kitchen["labels"])
+	}
+}
+
+func TestExecuteMalformedQuery(t *testing.T) {
+	count := 0
+	schema := testSchema(&count)
+	queries := []string{
+		`{ node(id: "n1") { id }`,
+		`{ node(id: "n1) { id } }`,
+		`mutation { node { id } }`,
+		`subscription { node { id } }`,
+		`query A { node { id } } query B { nodes { id } }`,
+	}
+	for _, query := range queries {
+		data, errors := execute(t, schema, &Request{Query: query})
+		if data != nil || len(errors) != 1 {
+			t.Errorf("query: %s, single error without data expected, received data: %v, errors: %v", query, data, errors)
+		}
+	}
+	if count != 0 {
+		t.Errorf("resolvers should not be called on the malformed queries, calls: %d", count)
+	}
+}
+
+func TestExecuteUnknownField(t *testing.T) {
+	count := 0
+	schema := testSchema(&count)
+	data, errors := execute(t, schema, &Request{Query: `{ gateway { id } node(id: "n1") { id } }`})
+	if len(errors) != 1 || !strings.Contains(errors[0].Message, "'gateway' not found") {
+		t.Fatalf("field not found error expected, received: %v", errors)
+	}
+	if data["gateway"] != nil || data["node"] == nil {
+		t.Errorf("other fields should be resolved, received: %v", data)
+	}
+}
+
+func TestExecuteDepthLimit(t *testing.T) {
+	count := 0
+	schema := testSchema(&count)
+	schema.MaxDepth = 5
+
+	nested := func(depth int) string {
+		// node is the first level, each parent adds a level, id is the last level
+		return `{ node(id: "n1") { ` + strings.Repeat("parent { ", depth-2) + "id" + strings.Repeat(" }", depth-2) + " } }"
+	}
+
+	_, errors := execute(t, schema, &Request{Query: nested(5)})
+	if len(errors) > 0 {
+		t.Fatalf("query within the depth limit, unexpected errors: %v", errors[0])
+	}
+
+	count = 0
+	data, errors := execute(t, schema, &Request{Query: nested(6)})
+	if data != nil || len(errors) != 1 || !strings.Contains(errors[0].Message, "exceeds the limit 5") {
+		t.Fatalf("depth limit error expected, received data: %v, errors: %v", data, errors)
+	}
+	if count != 0 {
+		t.Errorf("resolvers should not be called on the rejected query, calls: %d", count)
+	}
+
+	// fragments are expanded on the depth calculation
+	query := `{ node(id: "n1") { ...a } } fragment a on Node { parent { ...b } } fragment b on Node { parent { parent { parent { id } } } }`
+	_, errors = execute(t, schema, &Request{Query: query})
+	if len(errors) != 1 || !strings.Contains(errors[0].Message, "exceeds the limit") {
+		t.Errorf("depth limit error expected with the fragments, received: %v", errors)
+	}
+
+	// recursive fragments are rejected, expanded on each level otherwise
+	queries := []string{
+		`{ node(id: "n1") { ...a } } fragment a on Node { parent { ...a } }`,
+		`{ node(id: "n1") { ...a } } fragment a on Node { parent { ...b } } fragment b on Node { ... on Node { ...a } }`,
+	}
+	for _, query := range queries {
+		count = 0
+		data, errors = execute(t, schema, &Request{Query: query})
+		if data != nil || len(errors) != 1 || !strings.Contains(errors[0].Message, "spreads itself") {
+			t.Errorf("query: %s, fragment cycle error expected, received: %v", query, errors)
+		}
+		if count != 0 {
+			t.Errorf("resolvers should not be called on the rejected query, calls: %d", count)
+		}
+	}
+
+	// same fragment on the multiple fields is not a cycle
+	query = `{ a: node(id: "a") { ...f parent { ...f } } } fragment f on Node { id }`
+	_, errors = execute(t, schema, &Request{Query: query})
+	if len(errors) > 0 {
+		t.Errorf("unexpected errors on the repeated fragment: %v", errors[0])
+	}
+}
+
+func TestExecuteResolveLimit(t *testing.T) {
+	count := 0
+	schema := testSchema(&count)
+	schema.MaxResolve = 20
+
+	// 1 resolver call and 10 list items
+	_, errors := execute(t, schema, &Request{Query: `{ nodes(limit: 10) { id } }`})
+	if len(errors) > 0 {
+		t.Fatalf("query within the resolve limit, unexpected errors: %v", errors[0])
+	}
+
+	// 1 resolver call, 10 list items and 10 parent calls
+	count = 0
+	data, errors := execute(t, schema, &Request{Query: `{ nodes(limit: 10) { id parent { id } } }`})
+	if len(errors) != 1 || !strings.Contains(errors[0].Message, "limit of 20") {
+		t.Fatalf("single resolve limit error expected, received: %v", errors)
+	}
+	if count > schema.MaxResolve {
+		t.Errorf("resolver calls should not exceed the limit, calls: %d", count)
+	}
+	if data == nil {
+		t.Errorf("partial data expected")
+	}
+
+	// aliases are counted on each field
+	count = 0
+	query := `{ a: node(id: "a") { id } b: node(id: "b") { id } c: node(id: "c") { id } }`
+	schema.MaxResolve = 2
+	data, errors = execute(t, schema, &Request{Query: query})
+	if len(errors) != 1 || count != 2 {
+		t.Fatalf("single resolve limit error and 2 resolver calls expected, received errors: %v, calls: %d", errors, count)
+	}
+	if data["a"] == nil || data["b"] == nil || data["c"] != nil {
+		t.Errorf("fields resolved till the limit expected, received: %v", data)
+	}
+}
+
+func TestExecuteFieldResolveLimit(t *testing.T) {
+	count := 0
+	schema := testSchema(&count)
+	schema.objects["Node"].Fields["parent"].MaxResolve = 3
+
+	data, errors := execute(t, schema, &Request{Query: `{ nodes(limit: 5) { id parent { id } } }`})
+	if len(errors) != 1 || !strings.Contains(errors[0].Message, "limit of 3 calls on the field 'parent'") {
+		t.Fatalf("single field limit error expected, received: %v", errors)
+	}
+	// 1 nodes call and 3 parent calls
+	if count != 4 {
+		t.Errorf("4 resolver calls expected, calls: %d", count)
+	}
+	nodes, _ := data["nodes"].([]interface{})
+	if len(nodes) != 5 {
+		t.Fatalf("5 nodes expected, received: %v", data["nodes"])
+	}
+	if node, _ := nodes[3].(map[string]interface{}); node["id"] == nil || node["parent"] != nil {
+		t.Errorf("id without parent expected after the limit, received: %v", nodes[3])
+	}
+}
@@ -0,0 +1,213 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// token kinds
+const (
+	tokenEOF = iota
+	tokenPunctuator
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind     int
+	value    string
+	position int
+}
+
+type lexer struct {
+	source   string
+	position int
+}
+
+// next returns the next token, ignores whitespaces, commas and comments
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+	if l.position >= len(l.source) {
+		return token{kind: tokenEOF, position: l.position}, nil
+	}
+
+	start := l.position
+	char := l.source[l.position]
+	switch {
+	case strings.HasPrefix(l.source[l.position:], "..."):
+		l.position += 3
+		return token{kind: tokenPunctuator, value: "...", position: start}, nil
+
+	case strings.IndexByte("!$&()=:@[]{}|", char) != -1:
+		l.position++
+		return token{kind: tokenPunctuator, value: string(char), position: start}, nil
+
+	case char == '_' || isLetter(char):
+		for l.position < len(l.source) && isNameChar(l.source[l.position]) {
+			l.position++
+		}
+		return token{kind: tokenName, value: l.source[start:l.position], position: start}, nil
+
+	case char == '-' || isDigit(char):
+		return l.readNumber()
+
+	case char == '"':
+		if strings.HasPrefix(l.source[l.position:], `"""`) {
+			return l.readBlockString()
+		}
+		return l.readString()
+	}
+	return token{}, fmt.Errorf("unexpected character '%c' at position %d", char, start)
+}
+
+func (l *lexer) skipIgnored() {
+	for l.position < len(l.source) {
+		switch l.source[l.position] {
+		case ' ', '\t', '\n', '\r', ',':
+			l.position++
+		case '#':
+			for l.position < len(l.source) && l.source[l.position] != '\n' && l.source[l.position] != '\r' {
+				l.position++
+			}
+		default:
+			// unicode BOM
+			if strings.HasPrefix(l.source[l.position:], "\uFEFF") {
+				l.position += len("\uFEFF")
+				continue
+			}
+			return
+		}
+	}
+}
+
+func (l *lexer) readNumber() (token, error) {
+	start := l.position
+	isFloat := false
+	if l.source[l.position] == '-' {
+		l.position++
+	}
+	l.readDigits()
+	if l.position < len(l.source) && l.source[l.position] == '.' {
+		isFloat = true
+		l.position++
+		l.readDigits()
+	}
+	if l.position < len(l.source) && (l.source[l.position] == 'e' || l.source[l.position] == 'E') {
+		isFloat = true
+		l.position++
+		if l.position < len(l.source) && (l.source[l.position] == '+' || l.source[l.position] == '-') {
+			l.position++
+		}
+		l.readDigits()
+	}
+	value := l.source[start:l.position]
+	if isFloat {
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return token{}, fmt.Errorf("invalid number '%s' at position %d", value, start)
+		}
+		return token{kind: tokenFloat, value: value, position: start}, nil
+	}
+	if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+		return token{}, fmt.Errorf("invalid number '%s' at position %d", value, start)
+	}
+	return token{kind: tokenInt, value: value, position: start}, nil
+}
+
+func (l *lexer) readDigits() {
+	for l.position < len(l.source) && isDigit(l.source[l.position]) {
+		l.position++
+	}
+}
+
+func (l *lexer) readString() (token, error) {
+	start := l.position
+	l.position++ // opening quote
+	var builder strings.Builder
+	for l.position < len(l.source) {
+		char := l.source[l.position]
+		switch char {
+		case '"':
+			l.position++
+			return token{kind: tokenString, value: builder.String(), position: start}, nil
+
+		case '\n', '\r':
+			return token{}, fmt.Errorf("unterminated string at position %d", start)
+
+		case '\\':
+			if l.position+1 >= len(l.source) {
+				return token{}, fmt.Errorf("unterminated string at position %d", start)
+			}
+			escaped := l.source[l.position+1]
+			l.position += 2
+			switch escaped {
+			case '"', '\\', '/':
+				builder.WriteByte(escaped)
+			case 'b':
+				builder.WriteByte('\b')
+			case 'f':
+				builder.WriteByte('\f')
+			case 'n':
+				builder.WriteByte('\n')
+			case 'r':
+				builder.WriteByte('\r')
+			case 't':
+				builder.WriteByte('\t')
+			case 'u':
+				if l.position+4 > len(l.source) {
+					return token{}, fmt.Errorf("invalid unicode escape at position %d", l.position)
+				}
+				code, err := strconv.ParseUint(l.source[l.position:l.position+4], 16, 32)
+				if err != nil {
+					return token{}, fmt.Errorf("invalid unicode escape at position %d", l.position)
+				}
+				builder.WriteRune(rune(code))
+				l.position += 4
+			default:
+				return token{}, fmt.Errorf("invalid escape character '%c' at position %d", escaped, l.position-1)
+			}
+
+		default:
+			r, size := utf8.DecodeRuneInString(l.source[l.position:])
+			builder.WriteRune(r)
+			l.position += size
+		}
+	}
+	return token{}, fmt.Errorf("unterminated string at position %d", start)
+}
+
+func (l *lexer) readBlockString() (token, error) {
+	start := l.position
+	l.position += 3
+	var builder strings.Builder
+	for l.position < len(l.source) {
+		remaining := l.source[l.position:]
+		switch {
+		case strings.HasPrefix(remaining, `\"""`):
+			builder.WriteString(`"""`)
+			l.position += 4
+		case strings.HasPrefix(remaining, `"""`):
+			l.position += 3
+			return token{kind: tokenString, value: strings.TrimSpace(builder.String()), position: start}, nil
+		default:
+			builder.WriteByte(l.source[l.position])
+			l.position++
+		}
+	}
+	return token{}, fmt.Errorf("unterminated block string at position %d", start)
+}
+
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
+
+func isLetter(char byte) bool {
+	return (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
+
+func isNameChar(char byte) bool {
+	return char == '_' || isLetter(char) || isDigit(char)
+}
@@ -0,0 +1,494 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// operation types
+const (
+	OperationQuery        = "query"
+	OperationMutation     = "mutation"
+	OperationSubscription = "subscription"
+)
+
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+type operation struct {
+	operationType string
+	name          string
+	variables     []*variableDefinition
+	selections    []selection
+}
+
+type variableDefinition struct {
+	name         string
+	defaultValue interface{}
+}
+
+type fragment struct {
+	name          string
+	typeCondition string
+	selections    []selection
+}
+
+// selection can be a *field, *fragmentSpread or *inlineFragment
+type selection interface{}
+
+type field struct {
+	alias      string
+	name       string
+	arguments  map[string]interface{}
+	directives []*directive
+	selections []selection
+}
+
+// responseKey returns the key of the field in the response
+func (f *field) responseKey() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+type fragmentSpread struct {
+	name       string
+	directives []*directive
+}
+
+type inlineFragment struct {
+	typeCondition string
+	directives    []*directive
+	selections    []selection
+}
+
+type directive struct {
+	name      string
+	arguments map[string]interface{}
+}
+
+// variable reference on the argument values, resolved on the execution
+type variable string
+
+// enum value on the argument values, used as a string
+type enumValue string
+
+type parser struct {
+	lexer   *lexer
+	current token
+}
+
+// parse converts the query to a document
+func parse(query string) (*document, error) {
+	p := &parser{lexer: &lexer{source: query}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &document{fragments: make(map[string]*fragment)}
+	for p.current.kind != tokenEOF {
+		switch {
+		case p.peek(tokenPunctuator, "{"):
+			selections, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, &operation{operationType: OperationQuery, selections: selections})
+
+		case p.peek(tokenName, "fragment"):
+			f, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, found := doc.fragments[f.name]; found {
+				return nil, fmt.Errorf("duplicate fragment '%s'", f.name)
+			}
+			doc.fragments[f.name] = f
+
+		case p.peek(tokenName, OperationQuery), p.peek(tokenName, OperationMutation), p.peek(tokenName, OperationSubscription):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+
+		default:
+			return nil, p.unexpected()
+		}
+	}
+	if len(doc.operations) == 0 {
+		return nil, fmt.Errorf("no operation found")
+	}
+	return doc, nil
+}
+
+func (p *parser) advance() error {
+	t, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.current = t
+	return nil
+}
+
+func (p *parser) peek(kind int, value string) bool {
+	return p.current.kind == kind && p.current.value == value
+}
+
+// skip moves to the next token, if the current token matches
+func (p *parser) skip(kind int, value string) (bool, error) {
+	if !p.peek(kind, value) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+func (p *parser) expect(kind int, value string) error {
+	if !p.peek(kind, value) {
+		return p.unexpected()
+	}
+	return p.advance()
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.current.kind != tokenName {
+		return "", p.unexpected()
+	}
+	name := p.current.value
+	return name, p.advance()
+}
+
+func (p *parser) unexpected() error {
+	if p.current.kind == tokenEOF {
+		return fmt.Errorf("unexpected end of the query")
+	}
+	return fmt.Errorf("unexpected '%s' at position %d", p.current.value, p.current.position)
+}
+
+func (p *parser) parseOperation() (*operation, error) {
+	op := &operation{operationType: p.current.value}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.current.kind == tokenName {
+		op.name = p.current.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	if found, err := p.skip(tokenPunctuator, "("); err != nil {
+		return nil, err
+	} else if found {
+		for !p.peek(tokenPunctuator, ")") {
+			definition, err := p.parseVariableDefinition()
+			if err != nil {
+				return nil, err
+			}
+			op.variables = append(op.variables, definition)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	// directives on the operations are not used
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.selections = selections
+	return op, nil
+}
+
+func (p *parser) parseVariableDefinition() (*variableDefinition, error) {
+	if err := p.expect(tokenPunctuator, "$"); err != nil {
+		return nil, err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expect(tokenPunctuator, ":"); err != nil {
+		return nil, err
+	}
+	// types are not validated, resolvers converts the received values
+	if err = p.parseType(); err != nil {
+		return nil, err
+	}
+	definition := &variableDefinition{name: name}
+	if found, err := p.skip(tokenPunctuator, "="); err != nil {
+		return nil, err
+	} else if found {
+		value, err := p.parseValue(true)
+		if err != nil {
+			return nil, err
+		}
+		definition.defaultValue = value
+	}
+	return definition, nil
+}
+
+func (p *parser) parseType() error {
+	if found, err := p.skip(tokenPunctuator, "["); err != nil {
+		return err
+	} else if found {
+		if err = p.parseType(); err != nil {
+			return err
+		}
+		if err = p.expect(tokenPunctuator, "]"); err != nil {
+			return err
+		}
+	} else if _, err = p.expectName(); err != nil {
+		return err
+	}
+	_, err := p.skip(tokenPunctuator, "!")
+	return err
+}
+
+func (p *parser) parseFragment() (*fragment, error) {
+	if err := p.advance(); err != nil { // fragment keyword
+		return nil, err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expect(tokenName, "on"); err != nil {
+		return nil, err
+	}
+	typeCondition, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	return &fragment{name: name, typeCondition: typeCondition, selections: selections}, nil
+}
+
+func (p *parser) parseSelectionSet() ([]selection, error) {
+	if err := p.expect(tokenPunctuator, "{"); err != nil {
+		return nil, err
+	}
+	selections := make([]selection, 0)
+	for !p.peek(tokenPunctuator, "}") {
+		item, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, item)
+	}
+	if len(selections) == 0 {
+		return nil, fmt.Errorf("empty selection set at position %d", p.current.position)
+	}
+	return selections, p.advance()
+}
+
+func (p *parser) parseSelection() (selection, error) {
+	if found, err := p.skip(tokenPunctuator, "..."); err != nil {
+		return nil, err
+	} else if found {
+		// fragment spread
+		if p.current.kind == tokenName && p.current.value != "on" {
+			spread := &fragmentSpread{name: p.current.value}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			directives, err := p.parseDirectives()
+			if err != nil {
+				return nil, err
+			}
+			spread.directives = directives
+			return spread, nil
+		}
+
+		// inline fragment
+		inline := &inlineFragment{}
+		if found, err := p.skip(tokenName, "on"); err != nil {
+			return nil, err
+		} else if found {
+			typeCondition, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			inline.typeCondition = typeCondition
+		}
+		directives, err := p.parseDirectives()
+		if err != nil {
+			return nil, err
+		}
+		inline.directives = directives
+		selections, err := p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+		inline.selections = selections
+		return inline, nil
+	}
+	return p.parseField()
+}
+
+func (p *parser) parseField() (*field, error) {
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	f := &field{name: name}
+	if found, err := p.skip(tokenPunctuator, ":"); err != nil {
+		return nil, err
+	} else if found {
+		f.alias = name
+		if f.name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.arguments, err = p.parseArguments(); err != nil {
+		return nil, err
+	}
+	if f.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if p.peek(tokenPunctuator, "{") {
+		if f.selections, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+func (p *parser) parseArguments() (map[string]interface{}, error) {
+	arguments := make(map[string]interface{})
+	if found, err := p.skip(tokenPunctuator, "("); err != nil || !found {
+		return arguments, err
+	}
+	for !p.peek(tokenPunctuator, ")") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expect(tokenPunctuator, ":"); err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue(false)
+		if err != nil {
+			return nil, err
+		}
+		arguments[name] = value
+	}
+	return arguments, p.advance()
+}
+
+func (p *parser) parseDirectives() ([]*directive, error) {
+	directives := make([]*directive, 0)
+	for p.peek(tokenPunctuator, "@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		arguments, err := p.parseArguments()
+		if err != nil {
+			return nil, err
+		}
+		directives = append(directives, &directive{name: name, arguments: arguments})
+	}
+	return directives, nil
+}
+
+// parseValue returns the value, variables are not allowed on the constant values
+func (p *parser) parseValue(isConstant bool) (interface{}, error) {
+	current := p.current
+	switch current.kind {
+	case tokenPunctuator:
+		switch current.value {
+		case "$":
+			if isConstant {
+				return nil, p.unexpected()
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			name, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			return variable(name), nil
+
+		case "[":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			items := make([]interface{}, 0)
+			for !p.peek(tokenPunctuator, "]") {
+				item, err := p.parseValue(isConstant)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+			}
+			return items, p.advance()
+
+		case "{":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			object := make(map[string]interface{})
+			for !p.peek(tokenPunctuator, "}") {
+				name, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+				if err = p.expect(tokenPunctuator, ":"); err != nil {
+					return nil, err
+				}
+				value, err := p.parseValue(isConstant)
+				if err != nil {
+					return nil, err
+				}
+				object[name] = value
+			}
+			return object, p.advance()
+		}
+
+	case tokenInt:
+		value, _ := strconv.ParseInt(current.value, 10, 64)
+		return value, p.advance()
+
+	case tokenFloat:
+		value, _ := strconv.ParseFloat(current.value, 64)
+		return value, p.advance()
+
+	case tokenString:
+		return current.value, p.advance()
+
+	case tokenName:
+		var value interface{}
+		switch current.value {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		case "null":
+			value = nil
+		default:
+			value = enumValue(current.value)
+		}
+		return value, p.advance()
+	}
+	return nil, p.unexpected()
+}
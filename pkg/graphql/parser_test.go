@@ -0,0 +1,68 @@
+package graphql
+
+import (
+	"testing"
+)
+
+func TestParseValidQueries(t *testing.T) {
+	queries := []string{
+		`{ nodes { id } }`,
+		`query Nodes($limit: Int = 10) { nodes(limit: $limit, filter: [{k: "name", o: "eq", v: "kitchen"}]) { id name: labels } }`,
+		`query { node(id: "n1") { ...nodeFields } } fragment nodeFields on Node { id gatewayId }`,
+		`{ node(id: "n1") { ... on Node { id } __typename } }`,
+		`{ node(id: "n1") { id @skip(if: true) labels @include(if: false) } }`,
+		`subscription { fieldUpdated(labels: {room: "kitchen"}) { id current { value } } }`,
+		"# comment\n{ nodes { id } }",
+		`{ a(x: -1.5e3, y: true, z: null, s: """block "string" """, e: ENUM_VALUE) }`,
+	}
+	for _, query := range queries {
+		if _, err := parse(query); err != nil {
+			t.Errorf("query: %s, unexpected error: %v", query, err)
+		}
+	}
+}
+
+func TestParseMalformedQueries(t *testing.T) {
+	queries := map[string]string{
+		"empty":                  ``,
+		"only comment":           "# comment",
+		"unclosed selection":     `{ nodes { id }`,
+		"extra closing brace":    `{ nodes { id } } }`,
+		"empty selection":        `{ }`,
+		"unclosed arguments":     `{ node(id: "n1" { id } }`,
+		"missing argument":       `{ node(id: ) { id } }`,
+		"unterminated string":    `{ node(id: "n1) { id } }`,
+		"invalid number":         `{ node(limit: 1e) { id } }`,
+		"invalid character":      `{ node ^ { id } }`,
+		"variable in default":    `query ($a: Int = $b) { nodes { id } }`,
+		"duplicate fragment":     `{ ...f } fragment f on Query { nodes } fragment f on Query { nodes }`,
+		"fragment only":          `fragment f on Node { id }`,
+		"missing fragment on":    `{ ...f } fragment f Node { id }`,
+		"directive without name": `{ nodes @ { id } }`,
+	}
+	for name, query := range queries {
+		if _, err := parse(query); err == nil {
+			t.Errorf("%s: error expected, query: %s", name, query)
+		}
+	}
+}
+
+func TestParseAliasAndArguments(t *testing.T) {
+	doc, err := parse(`query Q($id: String) { first: node(id: $id, kind: SENSOR) { id } }`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(doc.operations) != 1 || doc.operations[0].name != "Q" {
+		t.Fatalf("operation 'Q' expected, received: %+v", doc.operations)
+	}
+	f, ok := doc.operations[0].selections[0].(*field)
+	if !ok {
+		t.Fatalf("field expected, received: %T", doc.operations[0].selections[0])
+	}
+	if f.responseKey() != "first" || f.name != "node" {
+		t.Errorf("alias 'first' of 'node' expected, received: %s of %s", f.alias, f.name)
+	}
+	if f.arguments["id"] != variable("id") || f.arguments["kind"] != enumValue("SENSOR") {
+		t.Errorf("unexpected arguments: %+v", f.arguments)
+	}
+}
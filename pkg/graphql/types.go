@@ -0,0 +1,126 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/mycontroller-org/server/v2/pkg/utils/convertor"
+)
+
+// ResolveFunc returns the value of a field
+type ResolveFunc func(params ResolveParams) (interface{}, error)
+
+// SubscribeFunc returns a channel of the source values of a subscription
+// the channel should be closed, when the context is done
+type SubscribeFunc func(params ResolveParams) (<-chan interface{}, error)
+
+// ResolveParams of a resolver
+type ResolveParams struct {
+	Context context.Context
+	Source  interface{}            // parent value
+	Args    map[string]interface{} // arguments of the field, variables resolved
+}
+
+// Field of an object
+// fields not defined on an object, resolved from the json representation of the source value
+type Field struct {
+	Type       string // object type name of the value, empty for the scalar and json values
+	Resolve    ResolveFunc
+	Subscribe  SubscribeFunc // used only on the subscription fields
+	MaxResolve int           // resolver calls of the field on a request, zero for no limit
+}
+
+// Object type
+type Object struct {
+	Name   string
+	Fields map[string]*Field
+}
+
+// limits of a request, applied on a query and on each subscription event
+const (
+	DefaultMaxDepth   = 10    // nested selections depth
+	DefaultMaxResolve = 10000 // resolver calls and list items
+)
+
+// Schema holds the root objects and the referenced object types
+type Schema struct {
+	Query        *Object
+	Subscription *Object
+	MaxDepth     int // requests with deeper selections are rejected
+	MaxResolve   int // execution stopped, when the resolver calls and list items exceed
+	objects      map[string]*Object
+}
+
+// NewSchema returns a schema
+func NewSchema(query, subscription *Object, objects ...*Object) *Schema {
+	schema := &Schema{
+		Query:        query,
+		Subscription: subscription,
+		MaxDepth:     DefaultMaxDepth,
+		MaxResolve:   DefaultMaxResolve,
+		objects:      make(map[string]*Object),
+	}
+	for _, object := range append(objects, query, subscription) {
+		if object != nil {
+			schema.objects[object.Name] = object
+		}
+	}
+	return schema
+}
+
+// Request of the graphql api
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Response of the graphql api
+type Response struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Error of a request or a field
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// GetString returns the argument as a string
+func (p *ResolveParams) GetString(name string) string {
+	value, found := p.Args[name]
+	if !found || value == nil {
+		return ""
+	}
+	return convertor.ToString(value)
+}
+
+// GetStrings returns the argument as a string slice, a single value converted to a slice
+func (p *ResolveParams) GetStrings(name string) []string {
+	value, found := p.Args[name]
+	if !found || value == nil {
+		return nil
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return []string{convertor.ToString(value)}
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		values = append(values, convertor.ToString(item))
+	}
+	return values
+}
+
+// GetInt returns the argument as an integer
+func (p *ResolveParams) GetInt(name string, defaultValue int64) int64 {
+	value, found := p.Args[name]
+	if !found || value == nil {
+		return defaultValue
+	}
+	return convertor.ToInteger(value)
+}
@@ -0,0 +1,189 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+)
+
+const (
+	contentTypeJSON = "application/json"
+	securityBearer  = "bearerAuth"
+)
+
+var (
+	// path parameter with regex, example: "{id:.*}"
+	pathParamRegex = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+	// actions on the ids of a resource
+	resourceActions = []string{"enable", "disable", "reload"}
+)
+
+// Route registered on the http router
+type Route struct {
+	Path    string
+	Methods []string
+}
+
+// Generate returns the OpenAPI document of the routes
+// isPublic reports the paths those are not required authentication
+func Generate(routes []Route, version string, isPublic func(path string) bool) *Document {
+	sb := newSchemaBuilder()
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "MyController Server API",
+			Description: "list operations support the query parameters filter, sortBy, limit and offset",
+			Version:     version,
+		},
+		Paths: make(map[string]PathItem),
+		Components: Components{
+			SecuritySchemes: map[string]SecurityScheme{
+				securityBearer: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+		Security: []map[string][]string{{securityBearer: {}}},
+	}
+
+	tags := make(map[string]bool)
+	for _, route := range routes {
+		path := pathParamRegex.ReplaceAllString(route.Path, "{$1}")
+		if !strings.HasPrefix(path, "/api/") {
+			continue
+		}
+		pathItem, found := doc.Paths[path]
+		if !found {
+			pathItem = make(PathItem)
+			doc.Paths[path] = pathItem
+		}
+		methods := route.Methods
+		if len(methods) == 0 { // handles all the methods, example: websocket
+			methods = []string{http.MethodGet}
+		}
+		for _, method := range methods {
+			operation := getOperation(sb, method, path)
+			if isPublic != nil && isPublic(path) {
+				operation.Security = []map[string][]string{}
+			}
+			for _, tag := range operation.Tags {
+				tags[tag] = true
+			}
+			pathItem[strings.ToLower(method)] = operation
+		}
+	}
+
+	for tag := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: tag})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+
+	doc.Components.Schemas = sb.components
+	return doc
+}
+
+// getOperation returns the operation, request and response schemas resolved for the resources
+func getOperation(sb *schemaBuilder, method, path string) *Operation {
+	operation := &Operation{
+		OperationID: getOperationID(method, path),
+		Summary:     method + " " + path,
+		Parameters:  getPathParameters(path),
+		Responses: map[string]Response{
+			"200":     {Description: "success", Content: jsonContent(&Schema{})},
+			"default": {Description: "error", Content: map[string]MediaType{"text/plain": {Schema: &Schema{Type: "string"}}}},
+		},
+	}
+	if method == http.MethodPost {
+		operation.RequestBody = &RequestBody{Content: jsonContent(&Schema{})}
+	}
+
+	resource := getResource(path)
+	if resource == nil {
+		operation.Tags = []string{getTagName(path)}
+		return operation
+	}
+	operation.Tags = []string{resource.Name}
+	entitySchema := sb.ToSchema(resource.Type)
+	idsSchema := &Schema{Type: "array", Items: &Schema{Type: "string"}}
+	typeName := strings.ToUpper(resource.Name[:1]) + resource.Name[1:]
+
+	switch {
+	case method == http.MethodGet && path == resource.Path:
+		operation.OperationID = "list" + typeName
+		operation.Summary = "list " + resource.Name
+		operation.Parameters = append(operation.Parameters, getListParameters()...)
+		operation.Responses["200"] = Response{Description: "success", Content: jsonContent(listResultSchema(sb, entitySchema))}
+
+	case method == http.MethodGet && path == resource.Path+"/{id}":
+		operation.OperationID = "get" + typeName
+		operation.Summary = "get a " + resource.Name
+		operation.Responses["200"] = Response{Description: "success", Content: jsonContent(entitySchema)}
+
+	case method == http.MethodPost && path == resource.Path:
+		operation.OperationID = "save" + typeName
+		operation.Summary = "create or update a " + resource.Name
+		operation.RequestBody = &RequestBody{Required: true, Content: jsonContent(entitySchema)}
+
+	case method == http.MethodDelete && path == resource.Path:
+		operation.OperationID = "delete" + typeName
+		operation.Summary = "delete " + resource.Name + " by ids"
+		operation.RequestBody = &RequestBody{Required: true, Content: jsonContent(idsSchema)}
+
+	case method == http.MethodPost:
+		for _, action := range resourceActions {
+			if path == resource.Path+"/"+action {
+				operation.OperationID = action + typeName
+				operation.Summary = action + " " + resource.Name + " by ids"
+				operation.RequestBody = &RequestBody{Required: true, Content: jsonContent(idsSchema)}
+			}
+		}
+	}
+	return operation
+}
+
+// getOperationID returns id from the method and the path, example: "postUserTwofactorEnroll"
+func getOperationID(method, path string) string {
+	id := strings.ToLower(method)
+	for _, element := range strings.FieldsFunc(strings.TrimPrefix(path, "/api/"), func(r rune) bool {
+		return r == '/' || r == '-' || r == '{' || r == '}' || r == '.'
+	}) {
+		id += strings.ToUpper(element[:1]) + element[1:]
+	}
+	return id
+}
+
+// getTagName returns the first element of the path after "/api/"
+func getTagName(path string) string {
+	return strings.SplitN(strings.TrimPrefix(path, "/api/"), "/", 2)[0]
+}
+
+func getPathParameters(path string) []Parameter {
+	parameters := make([]Parameter, 0)
+	for _, match := range pathParamRegex.FindAllStringSubmatch(path, -1) {
+		parameters = append(parameters, Parameter{Name: match[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+	return parameters
+}
+
+// getListParameters returns the filter and pagination parameters of the list operations
+func getListParameters() []Parameter {
+	return []Parameter{
+		{Name: "filter", In: "query", Description: `json array of filters, example: [{"k":"labels.location","o":"eq","v":"kitchen"}]`, Schema: &Schema{Type: "string"}},
+		{Name: "sortBy", In: "query", Description: `json array of sort options, example: [{"f":"id","o":"asc"}]`, Schema: &Schema{Type: "string"}},
+		{Name: "limit", In: "query", Description: "number of items, default 50", Schema: &Schema{Type: "integer", Format: "int64"}},
+		{Name: "offset", In: "query", Schema: &Schema{Type: "integer", Format: "int64"}},
+	}
+}
+
+// listResultSchema returns the result schema with the entities as data
+func listResultSchema(sb *schemaBuilder, entitySchema *Schema) *Schema {
+	schema := sb.structSchema(reflect.TypeOf(storageTY.Result{}))
+	schema.Properties["data"] = &Schema{Type: "array", Items: entitySchema}
+	return schema
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{contentTypeJSON: {Schema: schema}}
+}
@@ -0,0 +1,77 @@
+package openapi
+
+import (
+	"reflect"
+
+	auditTY "github.com/mycontroller-org/server/v2/pkg/types/audit"
+	dashboardTY "github.com/mycontroller-org/server/v2/pkg/types/dashboard"
+	dataRepositoryTY "github.com/mycontroller-org/server/v2/pkg/types/data_repository"
+	fieldTY "github.com/mycontroller-org/server/v2/pkg/types/field"
+	firmwareTY "github.com/mycontroller-org/server/v2/pkg/types/firmware"
+	fwdPayloadTY "github.com/mycontroller-org/server/v2/pkg/types/forward_payload"
+	nodeTY "github.com/mycontroller-org/server/v2/pkg/types/node"
+	roleTY "github.com/mycontroller-org/server/v2/pkg/types/role"
+	sceneTY "github.com/mycontroller-org/server/v2/pkg/types/scene"
+	scheduleTY "github.com/mycontroller-org/server/v2/pkg/types/schedule"
+	scriptModuleTY "github.com/mycontroller-org/server/v2/pkg/types/script_module"
+	secretTY "github.com/mycontroller-org/server/v2/pkg/types/secret"
+	svcTokenTY "github.com/mycontroller-org/server/v2/pkg/types/service_token"
+	sessionTY "github.com/mycontroller-org/server/v2/pkg/types/session"
+	sourceTY "github.com/mycontroller-org/server/v2/pkg/types/source"
+	taskTY "github.com/mycontroller-org/server/v2/pkg/types/task"
+	taskHistoryTY "github.com/mycontroller-org/server/v2/pkg/types/task_history"
+	userTY "github.com/mycontroller-org/server/v2/pkg/types/user"
+	vaTY "github.com/mycontroller-org/server/v2/pkg/types/virtual_assistant"
+	vdTY "github.com/mycontroller-org/server/v2/pkg/types/virtual_device"
+	gwTY "github.com/mycontroller-org/server/v2/plugin/gateway/types"
+	handlerTY "github.com/mycontroller-org/server/v2/plugin/handler/types"
+)
+
+// Resource details of a rest resource
+// standard operations: list "GET <path>", get "GET <path>/{id}", save "POST <path>" and delete "DELETE <path>"
+type Resource struct {
+	Name string // used as tag
+	Path string
+	Type reflect.Type
+}
+
+// Resources served by the rest api
+var Resources = []Resource{
+	{Name: "gateway", Path: "/api/gateway", Type: reflect.TypeOf(gwTY.Config{})},
+	{Name: "node", Path: "/api/node", Type: reflect.TypeOf(nodeTY.Node{})},
+	{Name: "source", Path: "/api/source", Type: reflect.TypeOf(sourceTY.Source{})},
+	{Name: "field", Path: "/api/field", Type: reflect.TypeOf(fieldTY.Field{})},
+	{Name: "firmware", Path: "/api/firmware", Type: reflect.TypeOf(firmwareTY.Firmware{})},
+	{Name: "dashboard", Path: "/api/dashboard", Type: reflect.TypeOf(dashboardTY.Config{})},
+	{Name: "forwardpayload", Path: "/api/forwardpayload", Type: reflect.TypeOf(fwdPayloadTY.Config{})},
+	{Name: "task", Path: "/api/task", Type: reflect.TypeOf(taskTY.Config{})},
+	{Name: "handler", Path: "/api/handler", Type: reflect.TypeOf(handlerTY.Config{})},
+	{Name: "schedule", Path: "/api/schedule", Type: reflect.TypeOf(scheduleTY.Config{})},
+	{Name: "datarepository", Path: "/api/datarepository", Type: reflect.TypeOf(dataRepositoryTY.Config{})},
+	{Name: "virtualdevice", Path: "/api/virtualdevice", Type: reflect.TypeOf(vdTY.VirtualDevice{})},
+	{Name: "virtualassistant", Path: "/api/virtualassistant", Type: reflect.TypeOf(vaTY.Config{})},
+	{Name: "servicetoken", Path: "/api/servicetoken", Type: reflect.TypeOf(svcTokenTY.ServiceToken{})},
+	{Name: "role", Path: "/api/role", Type: reflect.TypeOf(roleTY.Role{})},
+	{Name: "user", Path: "/api/user", Type: reflect.TypeOf(userTY.User{})},
+	{Name: "audit", Path: "/api/audit", Type: reflect.TypeOf(auditTY.AuditLog{})},
+	{Name: "taskhistory", Path: "/api/taskhistory", Type: reflect.TypeOf(taskHistoryTY.Entry{})},
+	{Name: "secret", Path: "/api/secret", Type: reflect.TypeOf(secretTY.Secret{})},
+	{Name: "scriptmodule", Path: "/api/scriptmodule", Type: reflect.TypeOf(scriptModuleTY.Config{})},
+	{Name: "scene", Path: "/api/scene", Type: reflect.TypeOf(sceneTY.Config{})},
+	{Name: "session", Path: "/api/session", Type: reflect.TypeOf(sessionTY.Session{})},
+	{Name: "session", Path: "/api/session/servicetoken", Type: reflect.TypeOf(svcTokenTY.ServiceToken{})},
+}
+
+// getResource returns the resource of the path, longest path wins
+func getResource(path string) *Resource {
+	var matched *Resource
+	for index := range Resources {
+		resource := &Resources[index]
+		if path == resource.Path || len(path) > len(resource.Path) && path[:len(resource.Path)+1] == resource.Path+"/" {
+			if matched == nil || len(resource.Path) > len(matched.Path) {
+				matched = resource
+			}
+		}
+	}
+	return matched
+}
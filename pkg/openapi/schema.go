@@ -0,0 +1,146 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemaBuilder converts the go types to json schemas, structs are added as components
+type schemaBuilder struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+	types      map[string]reflect.Type
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+		types:      make(map[string]reflect.Type),
+	}
+}
+
+// componentName returns the schema name of a struct, example: "gateway.Config"
+// for the packages named as "types", parent directory name used
+func (sb *schemaBuilder) componentName(t reflect.Type) string {
+	if name, found := sb.names[t]; found {
+		return name
+	}
+	elements := strings.Split(t.PkgPath(), "/")
+	pkgName := elements[len(elements)-1]
+	if pkgName == "types" && len(elements) > 1 && elements[len(elements)-2] != "pkg" {
+		pkgName = elements[len(elements)-2]
+	}
+	name := pkgName + "." + t.Name()
+	// on conflict, use the full package path
+	if _, found := sb.types[name]; found {
+		name = strings.ReplaceAll(strings.TrimPrefix(t.PkgPath(), "github.com/mycontroller-org/server/v2/"), "/", "_") + "." + t.Name()
+	}
+	sb.names[t] = name
+	sb.types[name] = t
+	return name
+}
+
+// ToSchema returns the schema of the type
+func (sb *schemaBuilder) ToSchema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+
+	// custom formatted values, example: custom date time
+	case t.Kind() != reflect.Struct && t.Implements(marshalerType):
+		return &Schema{}
+
+	// struct value marshalled as string, example: CustomDate
+	case t.Kind() == reflect.Struct && t.Implements(marshalerType):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+
+	case reflect.String:
+		return &Schema{Type: "string"}
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: sb.ToSchema(t.Elem())}
+
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: sb.ToSchema(t.Elem())}
+
+	case reflect.Struct:
+		if t.Name() == "" { // anonymous struct
+			return sb.structSchema(t)
+		}
+		name := sb.componentName(t)
+		if _, found := sb.components[name]; !found {
+			sb.components[name] = &Schema{} // placeholder, avoids the recursion on self referenced types
+			sb.components[name] = sb.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+
+	default: // interface and others
+		return &Schema{}
+	}
+}
+
+// structSchema returns the properties of a struct, embedded fields are flattened
+func (sb *schemaBuilder) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for index := 0; index < t.NumField(); index++ {
+		field := t.Field(index)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag := field.Tag.Get("json"); tag != "" {
+			tagName := strings.Split(tag, ",")[0]
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		} else if field.Anonymous {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for key, value := range sb.structSchema(embedded).Properties {
+					schema.Properties[key] = value
+				}
+				continue
+			}
+		}
+		schema.Properties[name] = sb.ToSchema(field.Type)
+	}
+	return schema
+}
@@ -0,0 +1,548 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	converterUtils "github.com/mycontroller-org/server/v2/pkg/utils/convertor"
+	metricTY "github.com/mycontroller-org/server/v2/plugin/database/metric/types"
+)
+
+// expression functions
+const (
+	FunctionSum        = "sum"
+	FunctionMean       = "mean"
+	FunctionMin        = "min"
+	FunctionMax        = "max"
+	FunctionAbs        = "abs"
+	FunctionDerivative = "derivative"
+	FunctionRate       = "rate"
+	FunctionIntegral   = "integral"
+	FunctionCumSum     = "cumsum"
+)
+
+// point of a series
+type point struct {
+	Time  time.Time
+	Value float64
+}
+
+// value of an expression node, either a scalar or a series
+type value struct {
+	isSeries bool
+	scalar   float64
+	series   []point
+}
+
+// node of the parsed expression
+type node interface {
+	eval(env *environment) (*value, error)
+}
+
+// environment holds the query results referenced by the expression
+type environment struct {
+	results  map[string][]metricTY.ResponseData
+	function string
+}
+
+// series returns the values of the query result, sorted by time
+func (env *environment) series(name string) ([]point, error) {
+	data, found := env.results[name]
+	if !found {
+		return nil, fmt.Errorf("query result not found for the reference:%s", name)
+	}
+	points := make([]point, 0, len(data))
+	for _, item := range data {
+		raw, found := item.Metric[env.function]
+		if !found {
+			raw, found = item.Metric[metricTY.FieldValue]
+		}
+		if !found || raw == nil {
+			continue
+		}
+		points = append(points, point{Time: item.Time, Value: converterUtils.ToFloat(raw)})
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+	return points, nil
+}
+
+type numberNode struct {
+	value float64
+}
+
+func (n *numberNode) eval(env *environment) (*value, error) {
+	return &value{scalar: n.value}, nil
+}
+
+type referenceNode struct {
+	name string
+}
+
+func (n *referenceNode) eval(env *environment) (*value, error) {
+	points, err := env.series(n.name)
+	if err != nil {
+		return nil, err
+	}
+	return &value{isSeries: true, series: points}, nil
+}
+
+type durationNode struct {
+	duration time.Duration
+}
+
+func (n *durationNode) eval(env *environment) (*value, error) {
+	return &value{scalar: n.duration.Seconds()}, nil
+}
+
+type unaryNode struct {
+	operand node
+}
+
+func (n *unaryNode) eval(env *environment) (*value, error) {
+	v, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	return apply(v, func(x float64) float64 { return -x }), nil
+}
+
+type binaryNode struct {
+	operator    byte
+	left, right node
+}
+
+func (n *binaryNode) eval(env *environment) (*value, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	var fn func(a, b float64) float64
+	switch n.operator {
+	case '+':
+		fn = func(a, b float64) float64 { return a + b }
+	case '-':
+		fn = func(a, b float64) float64 { return a - b }
+	case '*':
+		fn = func(a, b float64) float64 { return a * b }
+	case '/':
+		fn = func(a, b float64) float64 { return a / b }
+	default:
+		return nil, fmt.Errorf("unsupported operator:%c", n.operator)
+	}
+
+	switch {
+	case !left.isSeries && !right.isSeries:
+		return &value{scalar: fn(left.scalar, right.scalar)}, nil
+
+	case left.isSeries && !right.isSeries:
+		return apply(left, func(x float64) float64 { return fn(x, right.scalar) }), nil
+
+	case !left.isSeries && right.isSeries:
+		return apply(right, func(x float64) float64 { return fn(left.scalar, x) }), nil
+
+	default:
+		// only the points present on both the series
+		rightValues := make(map[int64]float64)
+		for _, p := range right.series {
+			rightValues[p.Time.UnixNano()] = p.Value
+		}
+		points := make([]point, 0)
+		for _, p := range left.series {
+			if rv, found := rightValues[p.Time.UnixNano()]; found {
+				points = append(points, point{Time: p.Time, Value: fn(p.Value, rv)})
+			}
+		}
+		return &value{isSeries: true, series: points}, nil
+	}
+}
+
+type functionNode struct {
+	name      string
+	arguments []node
+}
+
+func (n *functionNode) eval(env *environment) (*value, error) {
+	args := make([]*value, 0, len(n.arguments))
+	for _, argNode := range n.arguments {
+		arg, err := argNode.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+
+	switch n.name {
+	case FunctionSum, FunctionMean, FunctionMin, FunctionMax:
+		if len(args) == 0 {
+			return nil, fmt.Errorf("%s: at least one argument required", n.name)
+		}
+		seriesList := make([][]point, 0, len(args))
+		for _, arg := range args {
+			if !arg.isSeries {
+				return nil, fmt.Errorf("%s: supports only series arguments", n.name)
+			}
+			seriesList = append(seriesList, arg.series)
+		}
+		return &value{isSeries: true, series: aggregateSeries(n.name, seriesList)}, nil
+
+	case FunctionAbs:
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s: expects one argument", n.name)
+		}
+		return apply(args[0], math.Abs), nil
+
+	case FunctionDerivative, FunctionRate, FunctionIntegral:
+		if len(args) < 1 || len(args) > 2 || !args[0].isSeries {
+			return nil, fmt.Errorf("%s: expects a series and an optional unit, example: %s(power, 1h)", n.name, n.name)
+		}
+		unit := time.Second.Seconds()
+		if len(args) == 2 {
+			if args[1].isSeries || args[1].scalar <= 0 {
+				return nil, fmt.Errorf("%s: invalid unit", n.name)
+			}
+			unit = args[1].scalar
+		}
+		switch n.name {
+		case FunctionIntegral:
+			return &value{isSeries: true, series: integral(args[0].series, unit)}, nil
+		default:
+			return &value{isSeries: true, series: derivative(args[0].series, unit, n.name == FunctionRate)}, nil
+		}
+
+	case FunctionCumSum:
+		if len(args) != 1 || !args[0].isSeries {
+			return nil, fmt.Errorf("%s: expects one series argument", n.name)
+		}
+		points := make([]point, 0, len(args[0].series))
+		total := float64(0)
+		for _, p := range args[0].series {
+			total += p.Value
+			points = append(points, point{Time: p.Time, Value: total})
+		}
+		return &value{isSeries: true, series: points}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported function:%s", n.name)
+	}
+}
+
+// apply executes the function on a scalar or on all the points of a series
+func apply(v *value, fn func(float64) float64) *value {
+	if !v.isSeries {
+		return &value{scalar: fn(v.scalar)}
+	}
+	points := make([]point, 0, len(v.series))
+	for _, p := range v.series {
+		points = append(points, point{Time: p.Time, Value: fn(p.Value)})
+	}
+	return &value{isSeries: true, series: points}
+}
+
+// aggregateSeries merges the series points with the same timestamp
+func aggregateSeries(function string, seriesList [][]point) []point {
+	type accumulator struct {
+		time  time.Time
+		count int
+		value float64
+	}
+	accumulators := make(map[int64]*accumulator)
+	for _, series := range seriesList {
+		for _, p := range series {
+			key := p.Time.UnixNano()
+			acc, found := accumulators[key]
+			if !found {
+				accumulators[key] = &accumulator{time: p.Time, count: 1, value: p.Value}
+				continue
+			}
+			acc.count++
+			switch function {
+			case FunctionMin:
+				acc.value = math.Min(acc.value, p.Value)
+			case FunctionMax:
+				acc.value = math.Max(acc.value, p.Value)
+			default: // sum and mean
+				acc.value += p.Value
+			}
+		}
+	}
+
+	points := make([]point, 0, len(accumulators))
+	for _, acc := range accumulators {
+		v := acc.value
+		if function == FunctionMean {
+			v = v / float64(acc.count)
+		}
+		points = append(points, point{Time: acc.time, Value: v})
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+	return points
+}
+
+// derivative returns the change per unit between the consecutive points
+// on rate, decreased value considered as a counter reset
+func derivative(series []point, unit float64, isRate bool) []point {
+	points := make([]point, 0)
+	for index := 1; index < len(series); index++ {
+		previous, current := series[index-1], series[index]
+		elapsed := current.Time.Sub(previous.Time).Seconds()
+		if elapsed <= 0 {
+			continue
+		}
+		diff := current.Value - previous.Value
+		if isRate && diff < 0 {
+			diff = current.Value
+		}
+		points = append(points, point{Time: current.Time, Value: diff / (elapsed / unit)})
+	}
+	return points
+}
+
+// integral returns the area of each point, value * elapsed time from the previous point in units
+// the first point uses the interval of the next point
+// example: integral(power_in_watt, 1h) returns the watt-hour consumed on each window
+func integral(series []point, unit float64) []point {
+	points := make([]point, 0)
+	for index := range series {
+		var elapsed float64
+		switch {
+		case index > 0:
+			elapsed = series[index].Time.Sub(series[index-1].Time).Seconds()
+		case len(series) > 1:
+			elapsed = series[1].Time.Sub(series[0].Time).Seconds()
+		default:
+			continue
+		}
+		points = append(points, point{Time: series[index].Time, Value: series[index].Value * (elapsed / unit)})
+	}
+	return points
+}
+
+// evaluateExpression parses and evaluates the expression with the query results
+func evaluateExpression(expression *metricTY.Expression, results map[string][]metricTY.ResponseData) ([]metricTY.ResponseData, error) {
+	root, err := parseExpression(expression.Expression)
+	if err != nil {
+		return nil, err
+	}
+	function := expression.Function
+	if function == "" {
+		function = FunctionMean
+	}
+	v, err := root.eval(&environment{results: results, function: function})
+	if err != nil {
+		return nil, err
+	}
+	if !v.isSeries {
+		return nil, errors.New("expression should refer at least a query result")
+	}
+
+	data := make([]metricTY.ResponseData, 0, len(v.series))
+	for _, p := range v.series {
+		if math.IsNaN(p.Value) || math.IsInf(p.Value, 0) {
+			continue
+		}
+		data = append(data, metricTY.ResponseData{
+			Time:       p.Time,
+			MetricType: metricTY.MetricTypeGaugeFloat,
+			Metric:     map[string]interface{}{function: p.Value},
+		})
+	}
+	return data, nil
+}
+
+// parser of the expression
+// grammar:
+//
+//	expression := term (("+" | "-") term)*
+//	term       := unary (("*" | "/") unary)*
+//	unary      := "-" unary | primary
+//	primary    := number | duration | reference | function "(" expression ("," expression)* ")" | "(" expression ")"
+//
+// reference is a query name, names with special characters can be double quoted
+type parser struct {
+	input    string
+	position int
+}
+
+func parseExpression(input string) (node, error) {
+	p := &parser{input: input}
+	root, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.position < len(p.input) {
+		return nil, fmt.Errorf("unexpected character '%c' at position %d", p.input[p.position], p.position)
+	}
+	return root, nil
+}
+
+func (p *parser) skipSpaces() {
+	for p.position < len(p.input) && unicode.IsSpace(rune(p.input[p.position])) {
+		p.position++
+	}
+}
+
+func (p *parser) peek() byte {
+	p.skipSpaces()
+	if p.position < len(p.input) {
+		return p.input[p.position]
+	}
+	return 0
+}
+
+func (p *parser) parseExpression() (node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		operator := p.peek()
+		if operator != '+' && operator != '-' {
+			return left, nil
+		}
+		p.position++
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{operator: operator, left: left, right: right}
+	}
+}
+
+func (p *parser) parseTerm() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		operator := p.peek()
+		if operator != '*' && operator != '/' {
+			return left, nil
+		}
+		p.position++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{operator: operator, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.peek() == '-' {
+		p.position++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	ch := p.peek()
+	switch {
+	case ch == 0:
+		return nil, errors.New("unexpected end of the expression")
+
+	case ch == '(':
+		p.position++
+		inner, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("missing ')' at position %d", p.position)
+		}
+		p.position++
+		return inner, nil
+
+	case ch == '"':
+		p.position++
+		end := strings.IndexByte(p.input[p.position:], '"')
+		if end < 0 {
+			return nil, errors.New("missing closing quote")
+		}
+		name := p.input[p.position : p.position+end]
+		p.position += end + 1
+		return &referenceNode{name: name}, nil
+
+	case isDigit(ch) || ch == '.':
+		start := p.position
+		for p.position < len(p.input) && (isDigit(p.input[p.position]) || p.input[p.position] == '.') {
+			p.position++
+		}
+		number := p.input[start:p.position]
+		// duration, example: 1h, 15m
+		if p.position < len(p.input) && unicode.IsLetter(rune(p.input[p.position])) {
+			for p.position < len(p.input) && (unicode.IsLetter(rune(p.input[p.position])) || isDigit(p.input[p.position])) {
+				p.position++
+			}
+			duration, err := metricTY.ParseDuration(p.input[start:p.position])
+			if err != nil {
+				return nil, fmt.Errorf("invalid duration:%s", p.input[start:p.position])
+			}
+			return &durationNode{duration: duration}, nil
+		}
+		parsed, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number:%s", number)
+		}
+		return &numberNode{value: parsed}, nil
+
+	case isIdentifier(ch):
+		start := p.position
+		for p.position < len(p.input) && (isIdentifier(p.input[p.position]) || isDigit(p.input[p.position])) {
+			p.position++
+		}
+		name := p.input[start:p.position]
+		if p.peek() != '(' {
+			return &referenceNode{name: name}, nil
+		}
+		p.position++
+		fn := &functionNode{name: strings.ToLower(name), arguments: []node{}}
+		if p.peek() == ')' {
+			p.position++
+			return fn, nil
+		}
+		for {
+			arg, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			fn.arguments = append(fn.arguments, arg)
+			switch p.peek() {
+			case ',':
+				p.position++
+			case ')':
+				p.position++
+				return fn, nil
+			default:
+				return nil, fmt.Errorf("missing ')' on function '%s'", name)
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("unexpected character '%c' at position %d", ch, p.position)
+	}
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func isIdentifier(ch byte) bool {
+	return ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
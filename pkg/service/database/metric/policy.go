@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/mycontroller-org/server/v2/pkg/store"
+	"github.com/mycontroller-org/server/v2/pkg/types/cmap"
+	metricTY "github.com/mycontroller-org/server/v2/plugin/database/metric/types"
+	"go.uber.org/zap"
+)
+
+var (
+	policies      = make([]metricTY.Policy, 0)
+	policiesMutex = &sync.RWMutex{}
+)
+
+// UpdatePolicies validates and applies the retention policies on the metric database
+func UpdatePolicies(newPolicies []metricTY.Policy) error {
+	names := make(map[string]bool)
+	for index := range newPolicies {
+		policy := &newPolicies[index]
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+		if names[policy.Name] {
+			return fmt.Errorf("duplicate metric policy name: %s", policy.Name)
+		}
+		names[policy.Name] = true
+	}
+
+	if store.METRIC != nil {
+		err := store.METRIC.UpdatePolicies(newPolicies)
+		if err != nil {
+			return err
+		}
+	}
+
+	policiesMutex.Lock()
+	defer policiesMutex.Unlock()
+	policies = newPolicies
+	zap.L().Info("metric retention policies updated", zap.Int("numberOfPolicies", len(policies)))
+	return nil
+}
+
+// GetPolicyName returns the first matching policy name for the metric type and labels
+// returns empty string, if none matches, data will be kept on the default policy of the metric database
+func GetPolicyName(metricType string, labels cmap.CustomStringMap) string {
+	policiesMutex.RLock()
+	defer policiesMutex.RUnlock()
+
+	for index := range policies {
+		if policies[index].Matches(metricType, labels) {
+			return policies[index].Name
+		}
+	}
+	return ""
+}
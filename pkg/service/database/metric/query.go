@@ -0,0 +1,200 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	fieldAPI "github.com/mycontroller-org/server/v2/pkg/api/field"
+	"github.com/mycontroller-org/server/v2/pkg/store"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	"github.com/mycontroller-org/server/v2/pkg/types/cmap"
+	fieldTY "github.com/mycontroller-org/server/v2/pkg/types/field"
+	converterUtils "github.com/mycontroller-org/server/v2/pkg/utils/convertor"
+	metricTY "github.com/mycontroller-org/server/v2/plugin/database/metric/types"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+)
+
+const (
+	selectorLimit = 500 // maximum number of fields selected by labels
+)
+
+// Query executes the query on the metric database,
+// aggregates the label selected fields and evaluates the expressions on the results.
+// label selection and expressions are independent of the metric database
+func Query(queryConfig *metricTY.QueryConfig) (map[string][]metricTY.ResponseData, error) {
+	if store.METRIC == nil {
+		return nil, errors.New("metric database not initialized")
+	}
+
+	expanded, groups, err := expandQueries(queryConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string][]metricTY.ResponseData)
+	if len(expanded.Individual) > 0 {
+		results, err = store.METRIC.Query(expanded)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// aggregate the label selected fields
+	for name, group := range groups {
+		seriesList := make([][]metricTY.ResponseData, 0, len(group.members))
+		for _, member := range group.members {
+			seriesList = append(seriesList, results[member])
+			delete(results, member)
+		}
+		results[name] = aggregateResults(group.aggregate, group.metricType, seriesList)
+	}
+
+	// evaluate the expressions, an expression can refer the previous expressions
+	for index := range queryConfig.Expressions {
+		expression := &queryConfig.Expressions[index]
+		if expression.Name == "" {
+			return nil, fmt.Errorf("expression name can not be empty, expression:%s", expression.Expression)
+		}
+		data, err := evaluateExpression(expression, results)
+		if err != nil {
+			return nil, fmt.Errorf("error on evaluating the expression:%s, error:%s", expression.Name, err.Error())
+		}
+		results[expression.Name] = data
+	}
+
+	return results, nil
+}
+
+// group of the label selected fields
+type group struct {
+	aggregate  string
+	metricType string
+	members    []string
+}
+
+// expandQueries replaces the label selectors with individual field queries
+// and updates the retention policy of the individual queries
+func expandQueries(queryConfig *metricTY.QueryConfig) (*metricTY.QueryConfig, map[string]*group, error) {
+	expanded := &metricTY.QueryConfig{Global: queryConfig.Global, Individual: make([]metricTY.Query, 0)}
+	groups := make(map[string]*group)
+
+	for index := range queryConfig.Individual {
+		query := queryConfig.Individual[index].Clone()
+		metricType := query.MetricType
+		if metricType == "" {
+			metricType = queryConfig.Global.MetricType
+		}
+
+		if len(query.Labels) == 0 {
+			// update retention policy, to select the raw or downsampled data
+			if query.Policy == "" && query.Tags[types.KeyID] != "" {
+				if field, err := fieldAPI.GetByID(query.Tags[types.KeyID]); err == nil {
+					query.Policy = GetPolicyName(metricType, field.Labels)
+				}
+			}
+			expanded.Individual = append(expanded.Individual, query)
+			continue
+		}
+
+		if query.Name == "" {
+			return nil, nil, errors.New("name required on the label selected query")
+		}
+		aggregate := query.Aggregate
+		if aggregate == "" {
+			aggregate = FunctionSum
+		}
+		switch aggregate {
+		case FunctionSum, FunctionMean, FunctionMin, FunctionMax:
+		default:
+			return nil, nil, fmt.Errorf("unsupported aggregate:%s, query:%s", aggregate, query.Name)
+		}
+
+		fields, err := getFieldsByLabels(query.Labels)
+		if err != nil {
+			return nil, nil, err
+		}
+		_group := &group{aggregate: aggregate, metricType: metricType, members: make([]string, 0)}
+		for _, field := range fields {
+			switch field.MetricType {
+			case metricTY.MetricTypeGauge, metricTY.MetricTypeGaugeFloat, metricTY.MetricTypeCounter:
+			default:
+				continue
+			}
+			if metricType != "" && field.MetricType != metricType {
+				continue
+			}
+			member := query.Clone()
+			member.Name = fmt.Sprintf("%s/%s", query.Name, field.ID)
+			member.MetricType = field.MetricType
+			member.Tags = map[string]string{types.KeyID: field.ID}
+			member.Labels = nil
+			member.Policy = GetPolicyName(field.MetricType, field.Labels)
+			expanded.Individual = append(expanded.Individual, member)
+			_group.members = append(_group.members, member.Name)
+			if _group.metricType == "" {
+				_group.metricType = field.MetricType
+			}
+		}
+		groups[query.Name] = _group
+	}
+	return expanded, groups, nil
+}
+
+// getFieldsByLabels returns the fields matching all the labels
+func getFieldsByLabels(labels cmap.CustomStringMap) ([]fieldTY.Field, error) {
+	filters := make([]storageTY.Filter, 0)
+	for key, value := range labels {
+		filters = append(filters, storageTY.Filter{Key: fmt.Sprintf("labels.%s", key), Operator: storageTY.OperatorEqual, Value: value})
+	}
+	result, err := fieldAPI.List(filters, &storageTY.Pagination{Limit: selectorLimit})
+	if err != nil {
+		return nil, err
+	}
+	if result.Count == 0 {
+		return []fieldTY.Field{}, nil
+	}
+	items, ok := result.Data.(*[]fieldTY.Field)
+	if !ok {
+		return nil, fmt.Errorf("invalid field list type:%T", result.Data)
+	}
+	return *items, nil
+}
+
+// aggregateResults merges the results of multiple fields, each metric key aggregated independently
+func aggregateResults(aggregate, metricType string, seriesList [][]metricTY.ResponseData) []metricTY.ResponseData {
+	keyPoints := make(map[string][][]point)
+	for _, series := range seriesList {
+		pointsByKey := make(map[string][]point)
+		for _, item := range series {
+			for key, raw := range item.Metric {
+				if raw == nil {
+					continue
+				}
+				pointsByKey[key] = append(pointsByKey[key], point{Time: item.Time, Value: converterUtils.ToFloat(raw)})
+			}
+		}
+		for key, points := range pointsByKey {
+			keyPoints[key] = append(keyPoints[key], points)
+		}
+	}
+
+	merged := make(map[int64]*metricTY.ResponseData)
+	for key, list := range keyPoints {
+		for _, p := range aggregateSeries(aggregate, list) {
+			data, found := merged[p.Time.UnixNano()]
+			if !found {
+				data = &metricTY.ResponseData{Time: p.Time, MetricType: metricType, Metric: make(map[string]interface{})}
+				merged[p.Time.UnixNano()] = data
+			}
+			data.Metric[key] = p.Value
+		}
+	}
+
+	data := make([]metricTY.ResponseData, 0, len(merged))
+	for _, item := range merged {
+		data = append(data, *item)
+	}
+	sort.SliceStable(data, func(i, j int) bool { return data[i].Time.Before(data[j].Time) })
+	return data
+}
@@ -0,0 +1,439 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mycontroller-org/server/v2/pkg/json"
+	sch "github.com/mycontroller-org/server/v2/pkg/service/core_scheduler"
+	"github.com/mycontroller-org/server/v2/pkg/store"
+	"github.com/mycontroller-org/server/v2/pkg/types"
+	"github.com/mycontroller-org/server/v2/pkg/types/cmap"
+	"github.com/mycontroller-org/server/v2/pkg/utils"
+	converterUtils "github.com/mycontroller-org/server/v2/pkg/utils/convertor"
+	metricTY "github.com/mycontroller-org/server/v2/plugin/database/metric/types"
+	"go.uber.org/zap"
+)
+
+const (
+	spoolDir               = "spool"
+	spoolFileExtension     = ".jsonl"
+	spoolJobName           = "metric-db-spool-check"
+	defaultSpoolMaxSize    = int64(100 * 1000 * 1000)
+	defaultSpoolSegment    = int64(4 * 1000 * 1000)
+	defaultSpoolCheckEvery = 15 * time.Second
+)
+
+// SpoolConfig of the metric write spool
+// data written to the disk, when the metric database is not reachable and replayed in order, once it is back
+//
+//	metric:
+//	  spool:
+//	    disabled: false
+//	    max_size: 100MB
+//	    segment_size: 4MB
+//	    check_interval: 15s
+type SpoolConfig struct {
+	Disabled      bool   `yaml:"disabled"`
+	MaxSize       string `yaml:"max_size"`
+	SegmentSize   string `yaml:"segment_size"`
+	CheckInterval string `yaml:"check_interval"`
+}
+
+// SpoolStatus reports the backlog of the metric write spool
+type SpoolStatus struct {
+	Enabled       bool      `json:"enabled"`
+	Online        bool      `json:"online"`
+	BacklogCount  int64     `json:"backlogCount"`
+	BacklogSize   int64     `json:"backlogSize"`
+	ReplayedCount int64     `json:"replayedCount"`
+	DroppedCount  int64     `json:"droppedCount"`
+	LastError     string    `json:"lastError"`
+	Since         time.Time `json:"since"` // online or offline since
+}
+
+// spool segment, a file on the disk with json line records
+type spoolSegment struct {
+	name   string
+	size   int64
+	count  int64
+	sealed bool
+}
+
+type spool struct {
+	mutex       sync.Mutex
+	replayMutex sync.Mutex
+	dir         string
+	maxSize     int64
+	segmentSize int64
+	segments    []*spoolSegment
+	status      SpoolStatus
+}
+
+var (
+	spoolSVC *spool
+)
+
+// StartSpool loads the existing backlog from the disk and starts the health check job
+func StartSpool(metricCfg cmap.CustomMap) error {
+	if metricCfg.GetBool(types.KeyDisabled) {
+		return nil
+	}
+
+	metricConfig := struct {
+		Spool SpoolConfig `yaml:"spool"`
+	}{}
+	err := utils.MapToStruct(utils.TagNameYaml, metricCfg, &metricConfig)
+	if err != nil {
+		return err
+	}
+	cfg := metricConfig.Spool
+	if cfg.Disabled {
+		return nil
+	}
+
+	checkInterval := defaultSpoolCheckEvery
+	if cfg.CheckInterval != "" {
+		parsed, err := time.ParseDuration(cfg.CheckInterval)
+		if err != nil {
+			return fmt.Errorf("invalid spool check_interval:%s, error:%s", cfg.CheckInterval, err.Error())
+		}
+		checkInterval = parsed
+	}
+
+	s := &spool{
+		dir:         path.Join(types.GetDataDirectoryMetric(), spoolDir),
+		maxSize:     utils.ParseSizeWithDefault(cfg.MaxSize, defaultSpoolMaxSize),
+		segmentSize: utils.ParseSizeWithDefault(cfg.SegmentSize, defaultSpoolSegment),
+		segments:    make([]*spoolSegment, 0),
+		status:      SpoolStatus{Enabled: true, Online: true, Since: time.Now()},
+	}
+	err = s.load()
+	if err != nil {
+		return err
+	}
+	// replay the backlog of the previous run
+	if len(s.segments) > 0 {
+		s.status.Online = false
+		zap.L().Info("metric spool has backlog from previous run", zap.Int64("backlogCount", s.status.BacklogCount), zap.Int64("backlogSize", s.status.BacklogSize))
+	}
+
+	spoolSVC = s
+	return sch.SVC.AddFunc(spoolJobName, fmt.Sprintf("@every %s", checkInterval.String()), s.check)
+}
+
+// CloseSpool stops the health check job, backlog remains on the disk
+func CloseSpool() {
+	if spoolSVC == nil {
+		return
+	}
+	sch.SVC.RemoveFunc(spoolJobName)
+	spoolSVC.replayMutex.Lock()
+	defer spoolSVC.replayMutex.Unlock()
+	spoolSVC = nil
+}
+
+// GetSpoolStatus returns the backlog details
+func GetSpoolStatus() SpoolStatus {
+	s := spoolSVC
+	if s == nil {
+		return SpoolStatus{}
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.status
+}
+
+// Write sends the data to the metric database
+// data will be kept on the spool, while the metric database is not reachable
+// online writes are asynchronous, an outage detected by the health check, the data written
+// between the outage and the detection depends on the retries of the metric database client
+func Write(data *metricTY.InputData) error {
+	s := spoolSVC
+	if s == nil || s.isOnline() {
+		return store.METRIC.Write(data)
+	}
+	return s.append(data)
+}
+
+func (s *spool) isOnline() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.status.Online
+}
+
+func (s *spool) setOffline(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.status.LastError = err.Error()
+	if s.status.Online {
+		s.status.Online = false
+		s.status.Since = time.Now()
+		zap.L().Warn("metric database not reachable, writes will be kept on the spool", zap.Error(err))
+	}
+}
+
+// append writes the data to the active segment, old segments removed when the size limit reached
+func (s *spool) append(data *metricTY.InputData) error {
+	record, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	record = append(record, '\n')
+	recordSize := int64(len(record))
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if recordSize > s.maxSize {
+		s.status.DroppedCount++
+		return errors.New("metric data larger than the spool size")
+	}
+
+	// remove the oldest segments, to keep the size limit
+	for s.status.BacklogSize+recordSize > s.maxSize && len(s.segments) > 0 {
+		oldest := s.segments[0]
+		if !oldest.sealed && len(s.segments) == 1 {
+			break
+		}
+		err = os.Remove(path.Join(s.dir, oldest.name))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		s.segments = s.segments[1:]
+		s.status.BacklogSize -= oldest.size
+		s.status.BacklogCount -= oldest.count
+		s.status.DroppedCount += oldest.count
+		zap.L().Warn("metric spool size limit reached, removed the oldest segment", zap.String("segment", oldest.name), zap.Int64("droppedCount", oldest.count))
+	}
+	if s.status.BacklogSize+recordSize > s.maxSize {
+		s.status.DroppedCount++
+		return errors.New("metric spool size limit reached")
+	}
+
+	var active *spoolSegment
+	if len(s.segments) > 0 {
+		active = s.segments[len(s.segments)-1]
+	}
+	if active == nil || active.sealed || active.size+recordSize > s.segmentSize {
+		if active != nil {
+			active.sealed = true
+		}
+		active = &spoolSegment{name: fmt.Sprintf("%d%s", time.Now().UnixNano(), spoolFileExtension)}
+		s.segments = append(s.segments, active)
+	}
+
+	err = utils.AppendFile(s.dir, active.name, record)
+	if err != nil {
+		return err
+	}
+	active.size += recordSize
+	active.count++
+	s.status.BacklogSize += recordSize
+	s.status.BacklogCount++
+	return nil
+}
+
+// load reads the segments available on the disk
+func (s *spool) load() error {
+	files, err := utils.ListFiles(s.dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name, spoolFileExtension) {
+			continue
+		}
+		data, err := os.ReadFile(file.FullPath)
+		if err != nil {
+			return err
+		}
+		count := int64(bytes.Count(data, []byte("\n")))
+		s.segments = append(s.segments, &spoolSegment{name: file.Name, size: file.Size, count: count, sealed: true})
+		s.status.BacklogSize += file.Size
+		s.status.BacklogCount += count
+	}
+	// segment names are the creation time
+	sort.SliceStable(s.segments, func(i, j int) bool {
+		if len(s.segments[i].name) != len(s.segments[j].name) {
+			return len(s.segments[i].name) < len(s.segments[j].name)
+		}
+		return s.segments[i].name < s.segments[j].name
+	})
+	return nil
+}
+
+// check verifies the metric database health and replays the backlog
+func (s *spool) check() {
+	if !s.replayMutex.TryLock() {
+		return // replay in progress
+	}
+	defer s.replayMutex.Unlock()
+
+	err := store.METRIC.Ping()
+	if err != nil {
+		s.setOffline(err)
+		return
+	}
+	if s.isOnline() {
+		return
+	}
+
+	startTime := time.Now()
+	replayed, err := s.replay()
+	if err != nil {
+		zap.L().Error("error on replaying the metric spool", zap.Int64("replayed", replayed), zap.Error(err))
+		s.setOffline(err)
+		return
+	}
+	zap.L().Info("metric spool replayed, metric database is online", zap.Int64("replayed", replayed), zap.String("timeTaken", time.Since(startTime).String()))
+}
+
+// replay writes the segments to the metric database in order and goes online, when there is no backlog
+func (s *spool) replay() (int64, error) {
+	replayed := int64(0)
+	for {
+		s.mutex.Lock()
+		if len(s.segments) == 0 {
+			s.status.Online = true
+			s.status.Since = time.Now()
+			s.status.LastError = ""
+			s.mutex.Unlock()
+			return replayed, nil
+		}
+		segment := s.segments[0]
+		// new data goes to a new segment
+		segment.sealed = true
+		s.mutex.Unlock()
+
+		count, err := s.replaySegment(segment)
+		replayed += count
+		if err != nil {
+			return replayed, err
+		}
+	}
+}
+
+// replaySegment writes the records of a sealed segment, remaining records kept on the disk on failure
+func (s *spool) replaySegment(segment *spoolSegment) (int64, error) {
+	filename := path.Join(s.dir, segment.name)
+	data, err := os.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+
+	replayed := int64(0)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), int(s.segmentSize)+1024)
+	offset := 0
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		lineSize := len(line) + 1
+		if len(bytes.TrimSpace(line)) > 0 {
+			inputData := &metricTY.InputData{}
+			if err := json.Unmarshal(line, inputData); err != nil {
+				zap.L().Error("error on decoding a metric spool record, dropped", zap.String("segment", segment.name), zap.Error(err))
+			} else {
+				normalizeFields(inputData)
+				if err := store.METRIC.WriteBlocking(inputData); err != nil {
+					// metric database not reachable, keep the remaining records
+					if pingErr := store.METRIC.Ping(); pingErr != nil {
+						s.removeRecords(segment, data[offset:], replayed)
+						return replayed, err
+					}
+					// rejected by the metric database, replaying it again will fail
+					zap.L().Error("metric spool record rejected by the metric database, dropped", zap.String("segment", segment.name), zap.Error(err))
+					s.addDropped(1)
+				}
+			}
+		}
+		offset += lineSize
+		replayed++
+	}
+	if err := scanner.Err(); err != nil {
+		zap.L().Error("error on reading the metric spool segment, dropped", zap.String("segment", segment.name), zap.Error(err))
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.status.ReplayedCount += replayed
+	index := s.segmentIndex(segment)
+	if index == -1 {
+		return replayed, nil // removed on size limit
+	}
+	err = os.Remove(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return replayed, err
+	}
+	s.segments = append(s.segments[:index], s.segments[index+1:]...)
+	s.status.BacklogSize -= segment.size
+	s.status.BacklogCount -= segment.count
+	return replayed, nil
+}
+
+func (s *spool) addDropped(count int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.status.DroppedCount += count
+}
+
+// segmentIndex returns the position of the segment, -1 if not found
+func (s *spool) segmentIndex(segment *spoolSegment) int {
+	for index := range s.segments {
+		if s.segments[index] == segment {
+			return index
+		}
+	}
+	return -1
+}
+
+// removeRecords rewrites the segment with the remaining records
+func (s *spool) removeRecords(segment *spoolSegment, remaining []byte, replayed int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.status.ReplayedCount += replayed
+	if s.segmentIndex(segment) == -1 {
+		return // removed on size limit
+	}
+
+	tmpName := fmt.Sprintf("%s.tmp", segment.name)
+	err := utils.WriteFile(s.dir, tmpName, remaining)
+	if err == nil {
+		err = os.Rename(path.Join(s.dir, tmpName), path.Join(s.dir, segment.name))
+	}
+	if err != nil {
+		// records will be replayed again
+		zap.L().Error("error on updating the metric spool segment", zap.String("segment", segment.name), zap.Error(err))
+		return
+	}
+	remainingSize := int64(len(remaining))
+	s.status.BacklogSize -= segment.size - remainingSize
+	s.status.BacklogCount -= replayed
+	segment.size = remainingSize
+	segment.count -= replayed
+}
+
+// normalizeFields restores the field types changed on the json encoding
+func normalizeFields(data *metricTY.InputData) {
+	for name, value := range data.Fields {
+		switch data.MetricType {
+		case metricTY.MetricTypeGauge, metricTY.MetricTypeCounter:
+			data.Fields[name] = converterUtils.ToInteger(value)
+		case metricTY.MetricTypeGaugeFloat, metricTY.MetricTypeGEO:
+			data.Fields[name] = converterUtils.ToFloat(value)
+		case metricTY.MetricTypeBinary:
+			data.Fields[name] = converterUtils.ToBool(value)
+		case metricTY.MetricTypeString:
+			data.Fields[name] = converterUtils.ToString(value)
+		}
+	}
+}
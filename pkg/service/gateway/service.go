@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	secretAPI "github.com/mycontroller-org/server/v2/pkg/api/secret"
 	commonStore "github.com/mycontroller-org/server/v2/pkg/store"
 	types "github.com/mycontroller-org/server/v2/pkg/types"
 	msgTY "github.com/mycontroller-org/server/v2/pkg/types/message"
@@ -25,6 +26,12 @@ func StartGW(gatewayCfg *gwTY.Config) error {
 		return err
 	}
 
+	// resolve the secret references
+	err = secretAPI.ResolveReferences(gatewayCfg)
+	if err != nil {
+		return err
+	}
+
 	if gwService.Get(gatewayCfg.ID) != nil {
 		zap.L().Info("no action needed. gateway service is in running state.", zap.String("gatewayId", gatewayCfg.ID))
 		return nil
@@ -6,7 +6,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/mycontroller-org/server/v2/pkg/store"
+	metricSVC "github.com/mycontroller-org/server/v2/pkg/service/database/metric"
 	types "github.com/mycontroller-org/server/v2/pkg/types"
 	fieldTY "github.com/mycontroller-org/server/v2/pkg/types/field"
 	nodeTY "github.com/mycontroller-org/server/v2/pkg/types/node"
@@ -41,6 +41,7 @@ func writeFieldMetric(field *fieldTY.Field) error {
 		Time:       field.Current.Timestamp,
 		Tags:       tags,
 		Fields:     fields,
+		Policy:     metricSVC.GetPolicyName(field.MetricType, field.Labels),
 	}
 
 	return writeMetric(metricData)
@@ -96,6 +97,7 @@ func writeNodeMetric(node *nodeTY.Node, suppliedMetricType, fieldName string, va
 		Time:       node.LastSeen,
 		Tags:       tags,
 		Fields:     fields,
+		Policy:     metricSVC.GetPolicyName(suppliedMetricType, node.Labels),
 	}
 
 	return writeMetric(metricData)
@@ -103,7 +105,7 @@ func writeNodeMetric(node *nodeTY.Node, suppliedMetricType, fieldName string, va
 
 func writeMetric(metricData *metricPluginTY.InputData) error {
 	startTime := time.Now()
-	err := store.METRIC.Write(metricData)
+	err := metricSVC.Write(metricData)
 	if err != nil {
 		zap.L().Error("failed to write into metrics database", zap.Error(err), zap.Any("metricData", metricData))
 		return err
@@ -0,0 +1,72 @@
+package mcgraphql
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	handlerUtils "github.com/mycontroller-org/server/v2/cmd/server/app/handler/utils"
+	"github.com/mycontroller-org/server/v2/pkg/graphql"
+	json "github.com/mycontroller-org/server/v2/pkg/json"
+	"go.uber.org/zap"
+)
+
+// graphql api paths
+const (
+	API_PATH           = "/api/graphql"
+	API_PATH_WEBSOCKET = "/api/graphql/ws"
+)
+
+// query parameters of the get request
+const (
+	queryKeyQuery         = "query"
+	queryKeyOperationName = "operationName"
+	queryKeyVariables     = "variables"
+)
+
+var schema = getSchema()
+
+// RegisterGraphQLRoutes registers graphql api
+func RegisterGraphQLRoutes(router *mux.Router) {
+	router.HandleFunc(API_PATH, executeQuery).Methods(http.MethodGet, http.MethodPost)
+	router.HandleFunc(API_PATH_WEBSOCKET, handleWebsocket)
+}
+
+// executeQuery executes a query, received as json body or as query parameters
+func executeQuery(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	request := &graphql.Request{}
+	if r.Method == http.MethodPost {
+		d, err := io.ReadAll(r.Body)
+		defer r.Body.Close()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		err = json.Unmarshal(d, request)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else {
+		request.Query = r.URL.Query().Get(queryKeyQuery)
+		request.OperationName = r.URL.Query().Get(queryKeyOperationName)
+		if variables := r.URL.Query().Get(queryKeyVariables); variables != "" {
+			err := json.Unmarshal([]byte(variables), &request.Variables)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	response := schema.Execute(r.Context(), request)
+	od, err := json.Marshal(response)
+	if err != nil {
+		zap.L().Error("error on converting the graphql response", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	handlerUtils.WriteResponse(w, od)
+}
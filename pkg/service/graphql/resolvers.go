@@ -0,0 +1,149 @@
+package mcgraphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mycontroller-org/server/v2/pkg/graphql"
+	json "github.com/mycontroller-org/server/v2/pkg/json"
+	"github.com/mycontroller-org/server/v2/pkg/store"
+	"github.com/mycontroller-org/server/v2/pkg/types"
+	"github.com/mycontroller-org/server/v2/pkg/types/cmap"
+	roleTY "github.com/mycontroller-org/server/v2/pkg/types/role"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+)
+
+const (
+	defaultLimit = 50 // default limit of the root lists, nested lists are not limited
+)
+
+// arguments of the list fields
+const (
+	argID     = "id"
+	argFilter = "filter"
+	argLabels = "labels"
+	argSortBy = "sortBy"
+	argLimit  = "limit"
+	argOffset = "offset"
+)
+
+// getAccess returns the resolved permissions of the request
+func getAccess(ctx context.Context) (*roleTY.Access, error) {
+	mcApiContext, ok := ctx.Value(types.MC_API_CONTEXT).(*types.McApiContext)
+	if !ok || mcApiContext == nil || mcApiContext.Access == nil {
+		return nil, errors.New("403 Forbidden, permission denied")
+	}
+	return mcApiContext.Access, nil
+}
+
+// getLabelSelectors verifies the read permission on the resource and returns the label selectors
+func getLabelSelectors(ctx context.Context, resource string) ([]cmap.CustomStringMap, error) {
+	access, err := getAccess(ctx)
+	if err != nil {
+		return nil, err
+	}
+	allowed, selectors := access.IsAllowed(resource, roleTY.VerbRead)
+	if !allowed {
+		return nil, fmt.Errorf("403 Forbidden, permission denied on '%s'", resource)
+	}
+	return selectors, nil
+}
+
+// getFiltersAndPagination converts the list arguments
+// filter and sortBy arguments follows the rest api format, example: filter: [{k: "name", o: "eq", v: "kitchen"}]
+func getFiltersAndPagination(params graphql.ResolveParams, limit int64) ([]storageTY.Filter, *storageTY.Pagination, error) {
+	filters := make([]storageTY.Filter, 0)
+	if value, found := params.Args[argFilter]; found && value != nil {
+		if err := convert(value, &filters); err != nil {
+			return nil, nil, fmt.Errorf("invalid filter: %w", err)
+		}
+	}
+	if value, found := params.Args[argLabels]; found && value != nil {
+		labels := cmap.CustomStringMap{}
+		if err := convert(value, &labels); err != nil {
+			return nil, nil, fmt.Errorf("invalid labels: %w", err)
+		}
+		for key, labelValue := range labels {
+			filters = append(filters, storageTY.Filter{Key: fmt.Sprintf("labels.%s", key), Operator: storageTY.OperatorEqual, Value: labelValue})
+		}
+	}
+
+	pagination := &storageTY.Pagination{
+		Limit:  params.GetInt(argLimit, limit),
+		Offset: params.GetInt(argOffset, 0),
+		SortBy: make([]storageTY.Sort, 0),
+	}
+	if value, found := params.Args[argSortBy]; found && value != nil {
+		if err := convert(value, &pagination.SortBy); err != nil {
+			return nil, nil, fmt.Errorf("invalid sortBy: %w", err)
+		}
+	}
+	return filters, pagination, nil
+}
+
+// list returns the entities, those are allowed to the user
+// label selectors applied on the storage filters, to keep the pagination on the allowed entities
+func list[T any](params graphql.ResolveParams, resource string, limit int64, parentFilters ...storageTY.Filter) ([]T, error) {
+	selectors, err := getLabelSelectors(params.Context, resource)
+	if err != nil {
+		return nil, err
+	}
+	labelFilters, err := roleTY.LabelSelectorFilters(selectors)
+	if err != nil {
+		return nil, err
+	}
+	filters, pagination, err := getFiltersAndPagination(params, limit)
+	if err != nil {
+		return nil, err
+	}
+	filters = append(filters, parentFilters...)
+	filters = append(filters, labelFilters...)
+
+	items := make([]T, 0)
+	_, err = store.STORAGE.Find(resource, &items, filters, pagination)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// get returns an entity, nil if not available or not allowed to the user
+func get[T any](params graphql.ResolveParams, resource string, labelsFn func(*T) cmap.CustomStringMap, filters ...storageTY.Filter) (*T, error) {
+	selectors, err := getLabelSelectors(params.Context, resource)
+	if err != nil {
+		return nil, err
+	}
+	item := new(T)
+	err = store.STORAGE.FindOne(resource, item, filters)
+	if err != nil {
+		if errors.Is(err, storageTY.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !roleTY.MatchesLabels(selectors, labelsFn(item)) {
+		return nil, nil
+	}
+	return item, nil
+}
+
+// sourceAs returns the parent value as the given type
+func sourceAs[T any](source interface{}) (*T, error) {
+	switch value := source.(type) {
+	case *T:
+		return value, nil
+	case T:
+		return &value, nil
+	}
+	return nil, fmt.Errorf("invalid source type: %T", source)
+}
+
+// convert the argument value to the target type
+func convert(value, out interface{}) error {
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(valueBytes, out)
+}
@@ -0,0 +1,220 @@
+package mcgraphql
+
+import (
+	"github.com/mycontroller-org/server/v2/pkg/graphql"
+	metricSVC "github.com/mycontroller-org/server/v2/pkg/service/database/metric"
+	"github.com/mycontroller-org/server/v2/pkg/types"
+	"github.com/mycontroller-org/server/v2/pkg/types/cmap"
+	fieldTY "github.com/mycontroller-org/server/v2/pkg/types/field"
+	nodeTY "github.com/mycontroller-org/server/v2/pkg/types/node"
+	roleTY "github.com/mycontroller-org/server/v2/pkg/types/role"
+	sourceTY "github.com/mycontroller-org/server/v2/pkg/types/source"
+	mtsTY "github.com/mycontroller-org/server/v2/plugin/database/metric/types"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+	gwTY "github.com/mycontroller-org/server/v2/plugin/gateway/types"
+)
+
+// object type names
+const (
+	typeQuery        = "Query"
+	typeSubscription = "Subscription"
+	typeGateway      = "Gateway"
+	typeNode         = "Node"
+	typeSource       = "Source"
+	typeField        = "Field"
+)
+
+// arguments of the metric field
+const (
+	argStart     = "start"
+	argStop      = "stop"
+	argWindow    = "window"
+	argFunctions = "functions"
+
+	metricQueryName = "metric"
+
+	// each metrics field executes a query on the metric database
+	maxMetricQueries = 20
+)
+
+// labels of the entities, used to verify the label restricted permissions
+var (
+	gatewayLabels = func(gw *gwTY.Config) cmap.CustomStringMap { return gw.Labels }
+	nodeLabels    = func(node *nodeTY.Node) cmap.CustomStringMap { return node.Labels }
+	sourceLabels  = func(source *sourceTY.Source) cmap.CustomStringMap { return source.Labels }
+	fieldLabels   = func(field *fieldTY.Field) cmap.CustomStringMap { return field.Labels }
+)
+
+// getSchema returns the graphql schema
+// fields of the entities are resolved from the json representation,
+// relations between the entities and the metrics are resolved here
+func getSchema() *graphql.Schema {
+	query := &graphql.Object{
+		Name: typeQuery,
+		Fields: map[string]*graphql.Field{
+			"gateways": {Type: typeGateway, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return list[gwTY.Config](p, types.EntityGateway, defaultLimit)
+			}},
+			"gateway": {Type: typeGateway, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return get(p, types.EntityGateway, gatewayLabels, idFilter(p.GetString(argID)))
+			}},
+			"nodes": {Type: typeNode, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return list[nodeTY.Node](p, types.EntityNode, defaultLimit)
+			}},
+			"node": {Type: typeNode, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return get(p, types.EntityNode, nodeLabels, idFilter(p.GetString(argID)))
+			}},
+			"sources": {Type: typeSource, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return list[sourceTY.Source](p, types.EntitySource, defaultLimit)
+			}},
+			"source": {Type: typeSource, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return get(p, types.EntitySource, sourceLabels, idFilter(p.GetString(argID)))
+			}},
+			"fields": {Type: typeField, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return list[fieldTY.Field](p, types.EntityField, defaultLimit)
+			}},
+			"field": {Type: typeField, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return get(p, types.EntityField, fieldLabels, idFilter(p.GetString(argID)))
+			}},
+		},
+	}
+
+	gateway := &graphql.Object{
+		Name: typeGateway,
+		Fields: map[string]*graphql.Field{
+			"nodes": {Type: typeNode, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				gw, err := sourceAs[gwTY.Config](p.Source)
+				if err != nil {
+					return nil, err
+				}
+				return list[nodeTY.Node](p, types.EntityNode, 0, keyFilter(types.KeyGatewayID, gw.ID))
+			}},
+		},
+	}
+
+	node := &graphql.Object{
+		Name: typeNode,
+		Fields: map[string]*graphql.Field{
+			"gateway": {Type: typeGateway, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				node, err := sourceAs[nodeTY.Node](p.Source)
+				if err != nil {
+					return nil, err
+				}
+				return get(p, types.EntityGateway, gatewayLabels, idFilter(node.GatewayID))
+			}},
+			"sources": {Type: typeSource, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				node, err := sourceAs[nodeTY.Node](p.Source)
+				if err != nil {
+					return nil, err
+				}
+				return list[sourceTY.Source](p, types.EntitySource, 0, nodeFilters(node.GatewayID, node.NodeID)...)
+			}},
+			"fields": {Type: typeField, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				node, err := sourceAs[nodeTY.Node](p.Source)
+				if err != nil {
+					return nil, err
+				}
+				return list[fieldTY.Field](p, types.EntityField, 0, nodeFilters(node.GatewayID, node.NodeID)...)
+			}},
+		},
+	}
+
+	source := &graphql.Object{
+		Name: typeSource,
+		Fields: map[string]*graphql.Field{
+			"node": {Type: typeNode, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				source, err := sourceAs[sourceTY.Source](p.Source)
+				if err != nil {
+					return nil, err
+				}
+				return get(p, types.EntityNode, nodeLabels, nodeFilters(source.GatewayID, source.NodeID)...)
+			}},
+			"fields": {Type: typeField, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				source, err := sourceAs[sourceTY.Source](p.Source)
+				if err != nil {
+					return nil, err
+				}
+				filters := append(nodeFilters(source.GatewayID, source.NodeID), keyFilter(types.KeySourceID, source.SourceID))
+				return list[fieldTY.Field](p, types.EntityField, 0, filters...)
+			}},
+		},
+	}
+
+	field := &graphql.Object{
+		Name: typeField,
+		Fields: map[string]*graphql.Field{
+			"node": {Type: typeNode, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				field, err := sourceAs[fieldTY.Field](p.Source)
+				if err != nil {
+					return nil, err
+				}
+				return get(p, types.EntityNode, nodeLabels, nodeFilters(field.GatewayID, field.NodeID)...)
+			}},
+			"source": {Type: typeSource, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				field, err := sourceAs[fieldTY.Field](p.Source)
+				if err != nil {
+					return nil, err
+				}
+				filters := append(nodeFilters(field.GatewayID, field.NodeID), keyFilter(types.KeySourceID, field.SourceID))
+				return get(p, types.EntitySource, sourceLabels, filters...)
+			}},
+			"metrics": {Resolve: resolveMetrics, MaxResolve: maxMetricQueries},
+		},
+	}
+
+	subscription := &graphql.Object{
+		Name: typeSubscription,
+		Fields: map[string]*graphql.Field{
+			"events": {Subscribe: subscribeEvents},
+			"fields": {Type: typeField, Subscribe: subscribeFields},
+		},
+	}
+
+	return graphql.NewSchema(query, subscription, gateway, node, source, field)
+}
+
+// resolveMetrics returns the metric history of a field
+func resolveMetrics(p graphql.ResolveParams) (interface{}, error) {
+	if _, err := getLabelSelectors(p.Context, roleTY.ResourceMetric); err != nil {
+		return nil, err
+	}
+	field, err := sourceAs[fieldTY.Field](p.Source)
+	if err != nil {
+		return nil, err
+	}
+	if field.MetricType == "" || field.MetricType == mtsTY.MetricTypeNone {
+		return []mtsTY.ResponseData{}, nil
+	}
+
+	queryConfig := &mtsTY.QueryConfig{
+		Global: mtsTY.Query{
+			Start:     p.GetString(argStart),
+			Stop:      p.GetString(argStop),
+			Window:    p.GetString(argWindow),
+			Functions: p.GetStrings(argFunctions),
+		},
+		Individual: []mtsTY.Query{{
+			Name:       metricQueryName,
+			MetricType: field.MetricType,
+			Tags:       map[string]string{types.KeyID: field.ID},
+			Policy:     metricSVC.GetPolicyName(field.MetricType, field.Labels),
+		}},
+	}
+	result, err := metricSVC.Query(queryConfig)
+	if err != nil {
+		return nil, err
+	}
+	return result[metricQueryName], nil
+}
+
+func idFilter(id string) storageTY.Filter {
+	return keyFilter(types.KeyID, id)
+}
+
+func keyFilter(key, value string) storageTY.Filter {
+	return storageTY.Filter{Key: key, Operator: storageTY.OperatorEqual, Value: value}
+}
+
+func nodeFilters(gatewayID, nodeID string) []storageTY.Filter {
+	return []storageTY.Filter{keyFilter(types.KeyGatewayID, gatewayID), keyFilter(types.KeyNodeID, nodeID)}
+}
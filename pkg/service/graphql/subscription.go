@@ -0,0 +1,137 @@
+package mcgraphql
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mycontroller-org/server/v2/pkg/graphql"
+	"github.com/mycontroller-org/server/v2/pkg/service/mcbus"
+	"github.com/mycontroller-org/server/v2/pkg/types"
+	busTY "github.com/mycontroller-org/server/v2/pkg/types/bus"
+	eventTY "github.com/mycontroller-org/server/v2/pkg/types/bus/event"
+	"github.com/mycontroller-org/server/v2/pkg/types/cmap"
+	fieldTY "github.com/mycontroller-org/server/v2/pkg/types/field"
+	roleTY "github.com/mycontroller-org/server/v2/pkg/types/role"
+	"github.com/mycontroller-org/server/v2/pkg/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	subscriptionQueueLimit = 100 // events dropped, if the client is slow
+)
+
+// arguments of the subscriptions
+const (
+	argIDs         = "ids"
+	argEntityTypes = "entityTypes"
+	argEventTypes  = "eventTypes"
+)
+
+// subscribeEvents streams the events of the resources
+// optionally filtered by the entity types, event types and entity ids
+func subscribeEvents(p graphql.ResolveParams) (<-chan interface{}, error) {
+	access, err := getAccess(p.Context)
+	if err != nil {
+		return nil, err
+	}
+	entityTypes := p.GetStrings(argEntityTypes)
+	eventTypes := p.GetStrings(argEventTypes)
+	ids := p.GetStrings(argIDs)
+
+	return subscribe(p.Context, func(event *eventTY.Event) (interface{}, bool) {
+		if !matches(entityTypes, event.EntityType) || !matches(eventTypes, event.Type) || !matches(ids, event.EntityID) {
+			return nil, false
+		}
+		allowed, selectors := access.IsAllowed(event.EntityType, roleTY.VerbRead)
+		if !allowed {
+			return nil, false
+		}
+		if len(selectors) > 0 {
+			holder := struct {
+				Labels cmap.CustomStringMap `json:"labels"`
+			}{}
+			if err := convert(event.Entity, &holder); err != nil || !roleTY.MatchesLabels(selectors, holder.Labels) {
+				return nil, false
+			}
+		}
+		return event, true
+	}), nil
+}
+
+// subscribeFields streams the created and updated fields, optionally filtered by the ids
+func subscribeFields(p graphql.ResolveParams) (<-chan interface{}, error) {
+	selectors, err := getLabelSelectors(p.Context, types.EntityField)
+	if err != nil {
+		return nil, err
+	}
+	ids := p.GetStrings(argIDs)
+
+	return subscribe(p.Context, func(event *eventTY.Event) (interface{}, bool) {
+		if event.EntityType != types.EntityField || event.Type == eventTY.TypeDeleted || !matches(ids, event.EntityID) {
+			return nil, false
+		}
+		field := &fieldTY.Field{}
+		if err := event.LoadEntity(field); err != nil {
+			zap.L().Debug("error on loading the field", zap.String("id", event.EntityID), zap.Error(err))
+			return nil, false
+		}
+		if !roleTY.MatchesLabels(selectors, field.Labels) {
+			return nil, false
+		}
+		return field, true
+	}), nil
+}
+
+// subscribe listens the events on the bus, till the context is done
+// filterFn returns the value to the subscriber and the status to include the event
+func subscribe(ctx context.Context, filterFn func(event *eventTY.Event) (interface{}, bool)) <-chan interface{} {
+	channel := make(chan interface{}, subscriptionQueueLimit)
+	closed := false
+	mutex := sync.Mutex{}
+
+	topic := mcbus.FormatTopic(mcbus.TopicEventsAll)
+	subscriptionID, err := mcbus.Subscribe(topic, func(data *busTY.BusData) {
+		event := &eventTY.Event{}
+		if err := data.LoadData(event); err != nil {
+			zap.L().Warn("failed to convert to target type", zap.Any("topic", data.Topic), zap.Error(err))
+			return
+		}
+		value, ok := filterFn(event)
+		if !ok {
+			return
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		if closed {
+			return
+		}
+		select {
+		case channel <- value:
+		default:
+			zap.L().Warn("graphql subscription queue is full, event dropped", zap.String("entityType", event.EntityType), zap.String("entityId", event.EntityID))
+		}
+	})
+	if err != nil {
+		zap.L().Error("error on subscribing the events", zap.String("topic", topic), zap.Error(err))
+		close(channel)
+		return channel
+	}
+
+	go func() {
+		<-ctx.Done()
+		if err := mcbus.Unsubscribe(topic, subscriptionID); err != nil {
+			zap.L().Error("error on unsubscribing the events", zap.String("topic", topic), zap.Error(err))
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		closed = true
+		close(channel)
+	}()
+	return channel
+}
+
+// matches returns true, if the values are empty or contains the value
+func matches(values []string, value string) bool {
+	return len(values) == 0 || utils.ContainsString(values, value)
+}
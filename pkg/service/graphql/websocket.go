@@ -0,0 +1,223 @@
+package mcgraphql
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	ws "github.com/gorilla/websocket"
+	"github.com/mycontroller-org/server/v2/pkg/graphql"
+	json "github.com/mycontroller-org/server/v2/pkg/json"
+	"go.uber.org/zap"
+)
+
+// websocket sub protocol, https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md
+const (
+	subProtocol = "graphql-transport-ws"
+
+	defaultWriteTimeout = time.Second * 3
+)
+
+// message types of the sub protocol
+const (
+	messageConnectionInit = "connection_init"
+	messageConnectionAck  = "connection_ack"
+	messagePing           = "ping"
+	messagePong           = "pong"
+	messageSubscribe      = "subscribe"
+	messageNext           = "next"
+	messageError          = "error"
+	messageComplete       = "complete"
+)
+
+// close codes of the sub protocol
+const (
+	closeInvalidMessage   = 4400
+	closeUnauthorized     = 4401
+	closeDuplicateInit    = 4429
+	closeSubscriberExists = 4409
+)
+
+var (
+	upgrader = ws.Upgrader{
+		CheckOrigin:  func(r *http.Request) bool { return true },
+		Subprotocols: []string{subProtocol},
+	}
+)
+
+type wsRequest struct {
+	ID      string          `json:"id"`
+	Type    string          `json:"type"`
+	Payload graphql.Request `json:"payload"`
+}
+
+type wsResponse struct {
+	ID      string      `json:"id,omitempty"`
+	Type    string      `json:"type"`
+	Payload interface{} `json:"payload,omitempty"`
+}
+
+// wsSession of a websocket connection
+type wsSession struct {
+	conn          *ws.Conn
+	ctx           context.Context
+	acknowledged  bool
+	subscriptions map[string]context.CancelFunc
+	mutex         sync.Mutex // guards subscriptions
+	writeMutex    sync.Mutex
+}
+
+// handleWebsocket serves the subscriptions and queries over websocket
+func handleWebsocket(w http.ResponseWriter, r *http.Request) {
+	wsCon, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		zap.L().Info("websocket upgrade error", zap.Error(err))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	session := &wsSession{conn: wsCon, ctx: ctx, subscriptions: make(map[string]context.CancelFunc)}
+	defer func() {
+		cancel()
+		err := wsCon.Close()
+		if err != nil {
+			zap.L().Debug("error on closing the connection", zap.String("remoteAddress", wsCon.RemoteAddr().String()), zap.Error(err))
+		}
+	}()
+
+	for {
+		_, data, err := wsCon.ReadMessage()
+		if err != nil {
+			zap.L().Debug("websocket read error", zap.Any("remoteAddress", wsCon.RemoteAddr()), zap.Error(err))
+			return
+		}
+		request := &wsRequest{}
+		if err = json.Unmarshal(data, request); err != nil {
+			session.close(closeInvalidMessage, "invalid message received")
+			return
+		}
+		if !session.handleMessage(request) {
+			return
+		}
+	}
+}
+
+// handleMessage processes a message, returns false if the connection closed
+func (s *wsSession) handleMessage(request *wsRequest) bool {
+	switch request.Type {
+	case messageConnectionInit:
+		// authentication verified on the http upgrade request
+		if s.acknowledged {
+			s.close(closeDuplicateInit, "too many initialisation requests")
+			return false
+		}
+		s.acknowledged = true
+		s.write(&wsResponse{Type: messageConnectionAck})
+
+	case messagePing:
+		s.write(&wsResponse{Type: messagePong})
+
+	case messagePong:
+		// nothing to do
+
+	case messageSubscribe:
+		if !s.acknowledged {
+			s.close(closeUnauthorized, "unauthorized")
+			return false
+		}
+		if request.ID == "" {
+			s.close(closeInvalidMessage, "subscription id required")
+			return false
+		}
+		if !s.startSubscription(request.ID, &request.Payload) {
+			s.close(closeSubscriberExists, fmt.Sprintf("subscriber for %s already exists", request.ID))
+			return false
+		}
+
+	case messageComplete:
+		// stopped by the client
+		s.removeSubscription(request.ID)
+
+	default:
+		s.close(closeInvalidMessage, fmt.Sprintf("invalid message type '%s'", request.Type))
+		return false
+	}
+	return true
+}
+
+// startSubscription executes the request, returns false if the id already in use
+func (s *wsSession) startSubscription(id string, request *graphql.Request) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, found := s.subscriptions[id]; found {
+		return false
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	responses, err := schema.Subscribe(ctx, request)
+	if err != nil {
+		cancel()
+		s.write(&wsResponse{ID: id, Type: messageError, Payload: []*graphql.Error{{Message: err.Error()}}})
+		return true
+	}
+	s.subscriptions[id] = cancel
+
+	go func() {
+		for response := range responses {
+			s.write(&wsResponse{ID: id, Type: messageNext, Payload: response})
+		}
+		// completed by the server, notify the client
+		if s.removeSubscription(id) {
+			s.write(&wsResponse{ID: id, Type: messageComplete})
+		}
+	}()
+	return true
+}
+
+// removeSubscription cancels the subscription, returns false if not available
+func (s *wsSession) removeSubscription(id string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	cancel, found := s.subscriptions[id]
+	if !found {
+		return false
+	}
+	cancel()
+	delete(s.subscriptions, id)
+	return true
+}
+
+func (s *wsSession) write(response *wsResponse) {
+	dataBytes, err := json.Marshal(response)
+	if err != nil {
+		zap.L().Error("error on converting to json", zap.Error(err))
+		return
+	}
+
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	err = s.conn.SetWriteDeadline(time.Now().Add(defaultWriteTimeout))
+	if err != nil {
+		zap.L().Debug("error on setting write deadline", zap.Any("remoteAddress", s.conn.RemoteAddr().String()), zap.Error(err))
+		return
+	}
+	err = s.conn.WriteMessage(ws.TextMessage, dataBytes)
+	if err != nil {
+		zap.L().Debug("error on write data to a client", zap.Any("remoteAddress", s.conn.RemoteAddr().String()), zap.Error(err))
+	}
+}
+
+func (s *wsSession) close(code int, reason string) {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	err := s.conn.WriteControl(ws.CloseMessage, ws.FormatCloseMessage(code, reason), time.Now().Add(defaultWriteTimeout))
+	if err != nil {
+		zap.L().Debug("error on closing the connection", zap.Any("remoteAddress", s.conn.RemoteAddr().String()), zap.Error(err))
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/mycontroller-org/server/v2/pkg/service/mcbus"
 	types "github.com/mycontroller-org/server/v2/pkg/types"
 	busTY "github.com/mycontroller-org/server/v2/pkg/types/bus"
+	taskHistoryTY "github.com/mycontroller-org/server/v2/pkg/types/task_history"
 	busUtils "github.com/mycontroller-org/server/v2/pkg/utils/bus_utils"
 	queueUtils "github.com/mycontroller-org/server/v2/pkg/utils/queue"
 	handlerTY "github.com/mycontroller-org/server/v2/plugin/handler/types"
@@ -82,4 +83,32 @@ func processHandlerMessage(item interface{}) {
 
 	state.Since = time.Now()
 	busUtils.SetHandlerState(msg.ID, *state)
+
+	if msg.ExecutionID == "" && msg.ReplyTopic == "" {
+		return
+	}
+
+	result := taskHistoryTY.HandlerResult{
+		HandlerID: msg.ID,
+		Status:    taskHistoryTY.HandlerStatusSuccess,
+		Duration:  time.Since(start).String(),
+		Timestamp: state.Since,
+	}
+	if err != nil {
+		result.Status = taskHistoryTY.HandlerStatusError
+		result.Error = err.Error()
+	}
+
+	// report the result to the task history
+	if msg.ExecutionID != "" {
+		busUtils.SetTaskHistoryHandlerResult(msg.ExecutionID, result)
+	}
+
+	// report the result to the requester
+	if msg.ReplyTopic != "" {
+		err = mcbus.Publish(msg.ReplyTopic, result)
+		if err != nil {
+			zap.L().Error("error on posting handler result", zap.String("handlerID", msg.ID), zap.String("replyTopic", msg.ReplyTopic), zap.Error(err))
+		}
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	secretAPI "github.com/mycontroller-org/server/v2/pkg/api/secret"
 	commonStore "github.com/mycontroller-org/server/v2/pkg/store"
 	types "github.com/mycontroller-org/server/v2/pkg/types"
 	"github.com/mycontroller-org/server/v2/pkg/utils"
@@ -93,6 +94,12 @@ func loadHandler(cfg *handlerTY.Config) (handlerTY.Plugin, error) {
 		return nil, err
 	}
 
+	// resolve the secret references
+	err = secretAPI.ResolveReferences(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	handler, err := handlerPlugin.Create(cfg.Type, cfg)
 	if err != nil {
 		return nil, err
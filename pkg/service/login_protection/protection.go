@@ -0,0 +1,116 @@
+package loginprotection
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mycontroller-org/server/v2/pkg/store"
+	"github.com/mycontroller-org/server/v2/pkg/utils"
+)
+
+const (
+	defaultMaxFailures      = 5
+	defaultMaxFailuresPerIP = 20
+	defaultFailureWindow    = time.Minute * 15
+	defaultLockoutDuration  = time.Minute * 15
+	cleanupThreshold        = 10000 // cleanup the stale entries, if the entries reach this count
+)
+
+// counter keeps the failures of a username or a client ip
+type counter struct {
+	failures    int
+	windowStart time.Time
+	lockedUntil time.Time
+}
+
+var (
+	usernames = make(map[string]*counter)
+	clientIPs = make(map[string]*counter)
+	mutex     sync.Mutex
+)
+
+// IsLocked returns true and the remaining lockout duration, if the username or the client ip is locked
+func IsLocked(username, clientIP string) (bool, time.Duration) {
+	if store.CFG == nil || store.CFG.Web.LoginProtection.Disabled {
+		return false, 0
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	now := time.Now()
+	remaining := time.Duration(0)
+	for _, _counter := range []*counter{usernames[username], clientIPs[clientIP]} {
+		if _counter != nil && _counter.lockedUntil.After(now) && _counter.lockedUntil.Sub(now) > remaining {
+			remaining = _counter.lockedUntil.Sub(now)
+		}
+	}
+	return remaining > 0, remaining
+}
+
+// Failed records a failed login
+func Failed(username, clientIP string) {
+	if store.CFG == nil || store.CFG.Web.LoginProtection.Disabled {
+		return
+	}
+	cfg := store.CFG.Web.LoginProtection
+	maxFailures := cfg.MaxFailures
+	if maxFailures <= 0 {
+		maxFailures = defaultMaxFailures
+	}
+	maxFailuresPerIP := cfg.MaxFailuresPerIP
+	if maxFailuresPerIP <= 0 {
+		maxFailuresPerIP = defaultMaxFailuresPerIP
+	}
+	window := utils.ToDuration(cfg.FailureWindow, defaultFailureWindow)
+	lockout := utils.ToDuration(cfg.LockoutDuration, defaultLockoutDuration)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if username != "" {
+		update(usernames, username, maxFailures, window, lockout)
+	}
+	if clientIP != "" {
+		update(clientIPs, clientIP, maxFailuresPerIP, window, lockout)
+	}
+}
+
+// Succeeded clears the failures of the username
+// failures of the client ip are retained, a valid account should not reset the guessing of other accounts
+func Succeeded(username string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	delete(usernames, username)
+}
+
+func update(counters map[string]*counter, key string, maxFailures int, window, lockout time.Duration) {
+	now := time.Now()
+	if len(counters) >= cleanupThreshold {
+		cleanup(counters, window)
+	}
+
+	_counter, found := counters[key]
+	if !found {
+		_counter = &counter{windowStart: now}
+		counters[key] = _counter
+	} else if now.Sub(_counter.windowStart) > window {
+		_counter.failures = 0
+		_counter.windowStart = now
+	}
+	_counter.failures++
+	if _counter.failures >= maxFailures {
+		_counter.lockedUntil = now.Add(lockout)
+		_counter.failures = 0
+		_counter.windowStart = now
+	}
+}
+
+// cleanup removes the entries those are not locked and the window expired
+func cleanup(counters map[string]*counter, window time.Duration) {
+	now := time.Now()
+	for key, _counter := range counters {
+		if _counter.lockedUntil.Before(now) && now.Sub(_counter.windowStart) > window {
+			delete(counters, key)
+		}
+	}
+}
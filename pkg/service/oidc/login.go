@@ -0,0 +1,213 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	json "github.com/mycontroller-org/server/v2/pkg/json"
+)
+
+const (
+	stateValidity = time.Minute * 10
+)
+
+var (
+	defaultScopes = []string{"openid", "profile", "email"}
+
+	// pending logins, key is the state
+	pendingLogins = make(map[string]*pendingLogin)
+	pendingMutex  sync.Mutex
+)
+
+// pendingLogin keeps the details of a login, until the callback received
+type pendingLogin struct {
+	providerID   string
+	nonce        string
+	codeVerifier string
+	expiresAt    time.Time
+}
+
+// GetAuthorizationURL returns the authorization url of the provider
+// uses authorization code flow with PKCE
+func GetAuthorizationURL(providerID string) (string, error) {
+	_provider, err := getProvider(providerID)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := randomString()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomString()
+	if err != nil {
+		return "", err
+	}
+	codeVerifier, err := randomString()
+	if err != nil {
+		return "", err
+	}
+
+	pendingMutex.Lock()
+	// remove expired logins
+	for key, login := range pendingLogins {
+		if time.Now().After(login.expiresAt) {
+			delete(pendingLogins, key)
+		}
+	}
+	pendingLogins[state] = &pendingLogin{
+		providerID:   providerID,
+		nonce:        nonce,
+		codeVerifier: codeVerifier,
+		expiresAt:    time.Now().Add(stateValidity),
+	}
+	pendingMutex.Unlock()
+
+	scopes := _provider.config.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultScopes
+	}
+
+	challenge := sha256.Sum256([]byte(codeVerifier))
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", _provider.config.ClientID)
+	query.Set("redirect_uri", _provider.config.RedirectURL)
+	query.Set("scope", strings.Join(scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(_provider.discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return _provider.discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// authenticate exchanges the authorization code and returns the verified claims
+func authenticate(providerID, state, code string) (*provider, jwt.MapClaims, error) {
+	pendingMutex.Lock()
+	login, found := pendingLogins[state]
+	delete(pendingLogins, state)
+	pendingMutex.Unlock()
+
+	if !found || time.Now().After(login.expiresAt) || login.providerID != providerID {
+		return nil, nil, errors.New("invalid or expired login state")
+	}
+	if code == "" {
+		return nil, nil, errors.New("authorization code not received")
+	}
+
+	_provider, err := getProvider(providerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// exchange the code
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", _provider.config.RedirectURL)
+	form.Set("code_verifier", login.codeVerifier)
+	headers := map[string]string{
+		"Content-Type":  "application/x-www-form-urlencoded",
+		"Accept":        "application/json",
+		"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(url.QueryEscape(_provider.config.ClientID)+":"+url.QueryEscape(_provider.config.ClientSecret))),
+	}
+	res, err := _provider.client.Execute(_provider.discovery.TokenEndpoint, http.MethodPost, headers, nil, form.Encode(), http.StatusOK)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error on exchanging the code, provider:%s, error:%s", providerID, err.Error())
+	}
+	tokenResponse := struct {
+		IDToken     string `json:"id_token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	err = json.Unmarshal(res.Body, &tokenResponse)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tokenResponse.IDToken == "" {
+		return nil, nil, errors.New("id_token not received")
+	}
+
+	claims, err := _provider.verifyIDToken(tokenResponse.IDToken, login.nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// groups are not included in the id token on some providers, get it from userinfo
+	if _provider.discovery.UserinfoEndpoint != "" && tokenResponse.AccessToken != "" {
+		if _, found := claims[_provider.groupsClaim()]; !found {
+			_provider.updateUserInfo(tokenResponse.AccessToken, claims)
+		}
+	}
+	return _provider, claims, nil
+}
+
+// verifyIDToken verifies the signature, issuer, audience, expiry and nonce of the id token
+func (p *provider) verifyIDToken(idToken, nonce string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}))
+	_, err := parser.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.getKey(kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token, error:%s", err.Error())
+	}
+	if !claims.VerifyIssuer(p.discovery.Issuer, true) {
+		return nil, errors.New("invalid id_token issuer")
+	}
+	if !claims.VerifyAudience(p.config.ClientID, true) {
+		return nil, errors.New("invalid id_token audience")
+	}
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, errors.New("expired id_token")
+	}
+	if receivedNonce, _ := claims["nonce"].(string); receivedNonce != nonce {
+		return nil, errors.New("invalid id_token nonce")
+	}
+	return claims, nil
+}
+
+// updateUserInfo adds the missing claims from the userinfo endpoint
+func (p *provider) updateUserInfo(accessToken string, claims jwt.MapClaims) {
+	headers := map[string]string{"Authorization": "Bearer " + accessToken}
+	res, err := p.client.ExecuteJson(p.discovery.UserinfoEndpoint, http.MethodGet, headers, nil, nil, http.StatusOK)
+	if err != nil {
+		return
+	}
+	userInfo := make(map[string]interface{})
+	if err = json.Unmarshal(res.Body, &userInfo); err != nil {
+		return
+	}
+	// userinfo should belong to the same subject
+	if userInfo["sub"] != claims["sub"] {
+		return
+	}
+	for key, value := range userInfo {
+		if _, found := claims[key]; !found {
+			claims[key] = value
+		}
+	}
+}
+
+func randomString() (string, error) {
+	data := make([]byte, 32)
+	_, err := rand.Read(data)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
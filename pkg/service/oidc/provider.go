@@ -0,0 +1,236 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	json "github.com/mycontroller-org/server/v2/pkg/json"
+	"github.com/mycontroller-org/server/v2/pkg/store"
+	configTY "github.com/mycontroller-org/server/v2/pkg/types/config"
+	httpClient "github.com/mycontroller-org/server/v2/pkg/utils/http_client_json"
+	"go.uber.org/zap"
+)
+
+const (
+	discoveryPath      = "/.well-known/openid-configuration"
+	keysRefreshMinimum = time.Minute * 5 // minimum interval to refresh the keys on unknown key id
+	requestTimeout     = time.Second * 15
+)
+
+var (
+	providers     = make(map[string]*provider)
+	providerMutex sync.Mutex
+)
+
+// ProviderInfo displayed on the login page
+type ProviderInfo struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// discovery document of the provider
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+// json web key
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type provider struct {
+	config        configTY.OIDCConfig
+	discovery     *discovery
+	keys          map[string]interface{}
+	keysUpdatedOn time.Time
+	client        *httpClient.Client
+	mutex         sync.Mutex
+}
+
+// GetProviders returns the enabled providers
+func GetProviders() []ProviderInfo {
+	items := make([]ProviderInfo, 0)
+	for _, cfg := range store.CFG.Web.OIDC {
+		if cfg.Disabled {
+			continue
+		}
+		name := cfg.Name
+		if name == "" {
+			name = cfg.ID
+		}
+		items = append(items, ProviderInfo{ID: cfg.ID, Name: name})
+	}
+	return items
+}
+
+// getProvider returns the provider, discovery document loaded on the first use
+func getProvider(providerID string) (*provider, error) {
+	providerMutex.Lock()
+	defer providerMutex.Unlock()
+
+	if _provider, found := providers[providerID]; found {
+		return _provider, nil
+	}
+
+	for _, cfg := range store.CFG.Web.OIDC {
+		if cfg.ID != providerID || cfg.Disabled {
+			continue
+		}
+		if cfg.IssuerURL == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+			return nil, fmt.Errorf("issuer_url, client_id and redirect_url are required, provider:%s", providerID)
+		}
+		_provider := &provider{
+			config: cfg,
+			keys:   make(map[string]interface{}),
+			client: httpClient.GetClient(cfg.Insecure, requestTimeout),
+		}
+		err := _provider.loadDiscovery()
+		if err != nil {
+			return nil, err
+		}
+		providers[providerID] = _provider
+		return _provider, nil
+	}
+	return nil, fmt.Errorf("oidc provider not found, id:%s", providerID)
+}
+
+func (p *provider) loadDiscovery() error {
+	url := strings.TrimSuffix(p.config.IssuerURL, "/") + discoveryPath
+	res, err := p.client.ExecuteJson(url, http.MethodGet, nil, nil, nil, http.StatusOK)
+	if err != nil {
+		return fmt.Errorf("error on getting discovery document, provider:%s, error:%s", p.config.ID, err.Error())
+	}
+	doc := &discovery{}
+	err = json.Unmarshal(res.Body, doc)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSuffix(doc.Issuer, "/") != strings.TrimSuffix(p.config.IssuerURL, "/") {
+		return fmt.Errorf("issuer mismatch, provider:%s, expected:%s, received:%s", p.config.ID, p.config.IssuerURL, doc.Issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JwksURI == "" {
+		return fmt.Errorf("incomplete discovery document, provider:%s", p.config.ID)
+	}
+	p.discovery = doc
+	return nil
+}
+
+// getKey returns the signing key, keys reloaded on unknown key id
+func (p *provider) getKey(kid string) (interface{}, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if key, found := p.keys[kid]; found {
+		return key, nil
+	}
+	if time.Since(p.keysUpdatedOn) < keysRefreshMinimum && len(p.keys) > 0 {
+		return nil, fmt.Errorf("unknown key id:%s", kid)
+	}
+
+	res, err := p.client.ExecuteJson(p.discovery.JwksURI, http.MethodGet, nil, nil, nil, http.StatusOK)
+	if err != nil {
+		return nil, fmt.Errorf("error on getting keys, provider:%s, error:%s", p.config.ID, err.Error())
+	}
+	keySet := struct {
+		Keys []jwk `json:"keys"`
+	}{}
+	err = json.Unmarshal(res.Body, &keySet)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]interface{})
+	for _, key := range keySet.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		publicKey, err := key.publicKey()
+		if err != nil {
+			zap.L().Warn("error on parsing a key", zap.String("provider", p.config.ID), zap.String("kid", key.Kid), zap.Error(err))
+			continue
+		}
+		keys[key.Kid] = publicKey
+	}
+	p.keys = keys
+	p.keysUpdatedOn = time.Now()
+
+	if key, found := p.keys[kid]; found {
+		return key, nil
+	}
+	// a single key can be used without key id
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key id:%s", kid)
+}
+
+// publicKey returns the rsa or ecdsa public key
+func (k *jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve:%s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	default:
+		return nil, errors.New("unsupported key type")
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
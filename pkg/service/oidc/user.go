@@ -0,0 +1,144 @@
+package oidc
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	userAPI "github.com/mycontroller-org/server/v2/pkg/api/user"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	userTY "github.com/mycontroller-org/server/v2/pkg/types/user"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultUsernameClaim = "preferred_username"
+	defaultGroupsClaim   = "groups"
+)
+
+// Login completes the login from the provider callback,
+// returns the user and the token validity of the provider
+func Login(providerID, state, code string) (*userTY.User, string, error) {
+	_provider, claims, err := authenticate(providerID, state, code)
+	if err != nil {
+		return nil, "", err
+	}
+	user, err := _provider.getUser(claims)
+	if err != nil {
+		return nil, "", err
+	}
+	return user, _provider.config.ExpiresIn, nil
+}
+
+func (p *provider) usernameClaim() string {
+	if p.config.UsernameClaim != "" {
+		return p.config.UsernameClaim
+	}
+	return defaultUsernameClaim
+}
+
+func (p *provider) groupsClaim() string {
+	if p.config.GroupsClaim != "" {
+		return p.config.GroupsClaim
+	}
+	return defaultGroupsClaim
+}
+
+// getUser returns the user linked to the subject, creates a user if auto provision enabled
+// roles are updated from the group claim on every login, if role mapping configured
+func (p *provider) getUser(claims jwt.MapClaims) (*userTY.User, error) {
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, errors.New("subject not found in the id_token")
+	}
+
+	roles, rolesManaged := p.getRoles(claims)
+
+	filters := []storageTY.Filter{
+		{Key: types.KeyProvider, Value: p.config.ID},
+		{Key: types.KeyExternalID, Value: subject},
+	}
+	user, err := userAPI.Get(filters)
+	if err != nil {
+		if err != storageTY.ErrNoDocuments {
+			return nil, err
+		}
+		if !p.config.AutoProvision {
+			return nil, fmt.Errorf("user not provisioned, provider:%s, subject:%s", p.config.ID, subject)
+		}
+		username, _ := claims[p.usernameClaim()].(string)
+		if username == "" {
+			return nil, fmt.Errorf("username claim '%s' not found in the id_token", p.usernameClaim())
+		}
+		// a local user or a user of another provider should not be taken over
+		if _, err := userAPI.GetByUsername(username); err == nil {
+			return nil, fmt.Errorf("username '%s' is already in use", username)
+		}
+		user = userTY.User{
+			Username:   username,
+			Provider:   p.config.ID,
+			ExternalID: subject,
+		}
+		zap.L().Info("provisioning a user from oidc provider", zap.String("provider", p.config.ID), zap.String("username", username))
+	}
+
+	if rolesManaged {
+		user.Roles = roles
+	}
+	if len(user.Roles) == 0 {
+		return nil, fmt.Errorf("no roles mapped to the user, provider:%s, subject:%s", p.config.ID, subject)
+	}
+	if email, _ := claims["email"].(string); email != "" {
+		user.Email = email
+	}
+	if fullName, _ := claims["name"].(string); fullName != "" {
+		user.FullName = fullName
+	}
+
+	err = userAPI.Save(&user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// getRoles returns the roles mapped from the groups claim
+// default roles returned, if none of the groups mapped
+// roles are managed locally, if role mapping and default roles are not configured
+func (p *provider) getRoles(claims jwt.MapClaims) ([]string, bool) {
+	if len(p.config.RoleMapping) == 0 && len(p.config.DefaultRoles) == 0 {
+		return nil, false
+	}
+
+	groups := make([]string, 0)
+	switch value := claims[p.groupsClaim()].(type) {
+	case []interface{}:
+		for _, group := range value {
+			if groupString, ok := group.(string); ok {
+				groups = append(groups, groupString)
+			}
+		}
+	case string:
+		groups = append(groups, strings.Fields(value)...)
+	}
+
+	rolesMap := make(map[string]bool)
+	for _, group := range groups {
+		if role, found := p.config.RoleMapping[group]; found {
+			rolesMap[role] = true
+		}
+	}
+	if len(rolesMap) == 0 {
+		return p.config.DefaultRoles, true
+	}
+
+	roles := make([]string, 0, len(rolesMap))
+	for role := range rolesMap {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles, true
+}
@@ -0,0 +1,67 @@
+package ratelimiter
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	cleanupThreshold = 10000 // cleanup the idle buckets, if the buckets reach this count
+)
+
+// bucket keeps the available requests of a key, refilled over the time
+type bucket struct {
+	tokens    float64
+	updatedOn time.Time
+	requests  int
+	interval  time.Duration
+}
+
+var (
+	buckets = make(map[string]*bucket)
+	mutex   sync.Mutex
+)
+
+// Allow consumes a request from the bucket of the key
+// returns false and the duration to wait, if the limit reached
+func Allow(key string, requests int, interval time.Duration) (bool, time.Duration) {
+	if requests <= 0 || interval <= 0 {
+		return true, 0
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	now := time.Now()
+	if len(buckets) >= cleanupThreshold {
+		cleanup(now)
+	}
+
+	_bucket, found := buckets[key]
+	if !found || _bucket.requests != requests || _bucket.interval != interval {
+		_bucket = &bucket{tokens: float64(requests), updatedOn: now, requests: requests, interval: interval}
+		buckets[key] = _bucket
+	}
+
+	// refill the bucket
+	ratePerNano := float64(requests) / float64(interval)
+	_bucket.tokens += float64(now.Sub(_bucket.updatedOn)) * ratePerNano
+	if _bucket.tokens > float64(requests) {
+		_bucket.tokens = float64(requests)
+	}
+	_bucket.updatedOn = now
+
+	if _bucket.tokens >= 1 {
+		_bucket.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - _bucket.tokens) / ratePerNano)
+}
+
+// cleanup removes the buckets those are full, same as a new bucket
+func cleanup(now time.Time) {
+	for key, _bucket := range buckets {
+		if now.Sub(_bucket.updatedOn) >= _bucket.interval {
+			delete(buckets, key)
+		}
+	}
+}
@@ -115,6 +115,18 @@ func processEvent(item interface{}) {
 			zap.L().Error("error on serving virtual assistant service request", zap.Error(err))
 		}
 
+	case rsTY.TypeTaskHistory:
+		err := taskHistoryService(request)
+		if err != nil {
+			zap.L().Error("error on serving task history request", zap.Error(err))
+		}
+
+	case rsTY.TypeField:
+		err := fieldService(request)
+		if err != nil {
+			zap.L().Error("error on serving field request", zap.Error(err))
+		}
+
 	default:
 		zap.L().Warn("unknown event type", zap.Any("event", request))
 	}
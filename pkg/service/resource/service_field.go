@@ -0,0 +1,64 @@
+package resource
+
+import (
+	"errors"
+	"fmt"
+
+	fieldAPI "github.com/mycontroller-org/server/v2/pkg/api/field"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
+	fieldTY "github.com/mycontroller-org/server/v2/pkg/types/field"
+	rsTY "github.com/mycontroller-org/server/v2/pkg/types/resource_service"
+	"github.com/mycontroller-org/server/v2/pkg/utils"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+	"go.uber.org/zap"
+)
+
+func fieldService(reqEvent *rsTY.ServiceEvent) error {
+	resEvent := &rsTY.ServiceEvent{
+		Type:    reqEvent.Type,
+		Command: reqEvent.ReplyCommand,
+	}
+
+	switch reqEvent.Command {
+	case rsTY.CommandList:
+		data, err := getFields(reqEvent)
+		if err != nil {
+			resEvent.Error = err.Error()
+		}
+		resEvent.SetData(data)
+
+	default:
+		return errors.New("unknown command")
+	}
+	return postResponse(reqEvent.ReplyTopic, resEvent)
+}
+
+// getFields returns the fields of a node
+func getFields(request *rsTY.ServiceEvent) ([]fieldTY.Field, error) {
+	ids := make(map[string]interface{})
+	err := request.LoadData(&ids)
+	if err != nil {
+		zap.L().Error("error on data conversion", zap.Any("request", request), zap.Error(err))
+		return nil, err
+	}
+
+	gatewayId := utils.GetMapValueString(ids, types.KeyGatewayID, "")
+	nodeId := utils.GetMapValueString(ids, types.KeyNodeID, "")
+	if gatewayId == "" || nodeId == "" {
+		return nil, fmt.Errorf("%v and %v should be supplied", types.KeyGatewayID, types.KeyNodeID)
+	}
+	filters := []storageTY.Filter{
+		{Key: types.KeyGatewayID, Operator: storageTY.OperatorEqual, Value: gatewayId},
+		{Key: types.KeyNodeID, Operator: storageTY.OperatorEqual, Value: nodeId},
+	}
+	result, err := fieldAPI.List(filters, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]fieldTY.Field, 0)
+	if items, ok := result.Data.(*[]fieldTY.Field); ok {
+		fields = *items
+	}
+	return fields, nil
+}
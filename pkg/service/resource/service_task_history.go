@@ -0,0 +1,35 @@
+package resource
+
+import (
+	"errors"
+
+	taskHistoryAPI "github.com/mycontroller-org/server/v2/pkg/api/task_history"
+	rsTY "github.com/mycontroller-org/server/v2/pkg/types/resource_service"
+	taskHistoryTY "github.com/mycontroller-org/server/v2/pkg/types/task_history"
+	"go.uber.org/zap"
+)
+
+func taskHistoryService(reqEvent *rsTY.ServiceEvent) error {
+	switch reqEvent.Command {
+	case rsTY.CommandUpdateState:
+		return updateTaskHistoryHandlerResult(reqEvent)
+
+	default:
+		return errors.New("unknown command")
+	}
+}
+
+func updateTaskHistoryHandlerResult(reqEvent *rsTY.ServiceEvent) error {
+	if reqEvent.Data == "" {
+		zap.L().Error("handler result not supplied", zap.Any("event", reqEvent))
+		return errors.New("handler result not supplied")
+	}
+	result := &taskHistoryTY.HandlerResult{}
+	err := reqEvent.LoadData(result)
+	if err != nil {
+		zap.L().Error("error on data conversion", zap.Any("data", reqEvent.Data), zap.Error(err))
+		return err
+	}
+
+	return taskHistoryAPI.SetHandlerResult(reqEvent.ID, result)
+}
@@ -0,0 +1,26 @@
+package schedule
+
+import (
+	"time"
+
+	taskHistoryAPI "github.com/mycontroller-org/server/v2/pkg/api/task_history"
+	taskHistoryTY "github.com/mycontroller-org/server/v2/pkg/types/task_history"
+	"go.uber.org/zap"
+)
+
+// addHistoryEntry persists the execution entry with the duration
+func addHistoryEntry(entry *taskHistoryTY.Entry) {
+	entry.Duration = time.Since(entry.Timestamp).String()
+	err := taskHistoryAPI.Add(entry)
+	if err != nil {
+		zap.L().Error("error on adding task history entry", zap.String("scheduleId", entry.SourceID), zap.Error(err))
+	}
+}
+
+// saveHistoryEntry updates the persisted execution entry
+func saveHistoryEntry(entry *taskHistoryTY.Entry) {
+	err := taskHistoryAPI.Save(entry)
+	if err != nil {
+		zap.L().Error("error on updating task history entry", zap.String("scheduleId", entry.SourceID), zap.Error(err))
+	}
+}
@@ -11,6 +11,7 @@ import (
 	types "github.com/mycontroller-org/server/v2/pkg/types"
 	dateTimeTY "github.com/mycontroller-org/server/v2/pkg/types/cusom_datetime"
 	scheduleTY "github.com/mycontroller-org/server/v2/pkg/types/schedule"
+	taskHistoryTY "github.com/mycontroller-org/server/v2/pkg/types/task_history"
 	"github.com/mycontroller-org/server/v2/pkg/utils"
 	busUtils "github.com/mycontroller-org/server/v2/pkg/utils/bus_utils"
 	converterUtils "github.com/mycontroller-org/server/v2/pkg/utils/convertor"
@@ -40,6 +41,17 @@ func scheduleTriggerFunc(cfg *scheduleTY.Config, spec string) {
 
 	executionError := ""
 
+	// execution entry of the task history
+	history := &taskHistoryTY.Entry{
+		ID:          utils.RandUUID(),
+		Timestamp:   start,
+		SourceType:  taskHistoryTY.SourceTypeSchedule,
+		SourceID:    cfg.ID,
+		Description: cfg.Description,
+		Triggered:   true,
+		Handlers:    []taskHistoryTY.HandlerResult{},
+	}
+
 	// disable even there is a error on the schedule
 	// call it inside func to get updated "executionError" value
 	defer func() { verifyAndDisableSchedule(cfg, time.Since(start), executionError) }()
@@ -53,6 +65,8 @@ func scheduleTriggerFunc(cfg *scheduleTY.Config, spec string) {
 		cfg.State.Message = fmt.Sprintf("error: %s", err.Error())
 		busUtils.SetScheduleState(cfg.ID, *cfg.State)
 		executionError = err.Error()
+		history.Error = err.Error()
+		addHistoryEntry(history)
 		return
 	}
 
@@ -71,6 +85,8 @@ func scheduleTriggerFunc(cfg *scheduleTY.Config, spec string) {
 				cfg.State.Message = fmt.Sprintf("error: %s", err.Error())
 				busUtils.SetScheduleState(cfg.ID, *cfg.State)
 				executionError = err.Error()
+				history.Error = err.Error()
+				addHistoryEntry(history)
 				return
 			}
 
@@ -92,7 +108,16 @@ func scheduleTriggerFunc(cfg *scheduleTY.Config, spec string) {
 
 	// post to handlers
 	parameters := variablesUtils.UpdateParameters(variables, cfg.HandlerParameters)
-	busUtils.PostToHandler(cfg.Handlers, parameters)
+	// the entry should be available before the handlers report the results
+	history.NotifiedHandlers = true
+	history.Handlers = taskHistoryTY.NewHandlerResults(cfg.Handlers)
+	addHistoryEntry(history)
+	if !busUtils.PostToHandlerWithExecutionID(history.ID, cfg.Handlers, parameters) {
+		history.NotifiedHandlers = false
+		history.Handlers = []taskHistoryTY.HandlerResult{}
+		history.Error = "no enabled handler parameters to post"
+		saveHistoryEntry(history)
+	}
 
 	cfg.State.Message = fmt.Sprintf("time taken: %s", time.Since(start).String())
 	// update triggered count and update state
@@ -0,0 +1,41 @@
+package systemjobs
+
+import (
+	"time"
+
+	auditAPI "github.com/mycontroller-org/server/v2/pkg/api/audit"
+	settingsAPI "github.com/mycontroller-org/server/v2/pkg/api/settings"
+	helper "github.com/mycontroller-org/server/v2/pkg/service/system_jobs/helper_utils"
+	"github.com/mycontroller-org/server/v2/pkg/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	idAuditLogPurger  = "audit_log_purger"
+	executionInterval = "@every 1h"
+)
+
+// ReloadJob schedules the audit log purge job
+func ReloadJob() {
+	settings, err := settingsAPI.GetSystemSettings()
+	if err != nil {
+		zap.L().Error("error on getting system settings", zap.Error(err))
+		return
+	}
+
+	retentionString := settings.AuditLog.Retention
+	if retentionString == "" {
+		retentionString = auditAPI.DefaultRetention
+	}
+	retention := utils.ToDuration(retentionString, time.Hour*24*30)
+
+	purgeAuditLog := func() {
+		_, err := auditAPI.Purge(retention)
+		if err != nil {
+			zap.L().Error("error on purging audit log", zap.Error(err))
+		}
+	}
+
+	// schedule a job
+	helper.Schedule(idAuditLogPurger, executionInterval, purgeAuditLog)
+}
@@ -7,8 +7,10 @@ import (
 )
 
 const (
-	JobTypeNodeStateUpdater = "node_state_updater"
-	JobTypeSunriseUpdater   = "sunrise_updater"
+	JobTypeNodeStateUpdater  = "node_state_updater"
+	JobTypeSunriseUpdater    = "sunrise_updater"
+	JobTypeAuditLogPurger    = "audit_log_purger"
+	JobTypeTaskHistoryPurger = "task_history_purger"
 )
 
 // PostEvent sends job change notification.
@@ -2,8 +2,11 @@ package systemjobs
 
 import (
 	analyticsJob "github.com/mycontroller-org/server/v2/pkg/service/system_jobs/analytics_job"
+	auditJob "github.com/mycontroller-org/server/v2/pkg/service/system_jobs/audit_job"
 	nodeJob "github.com/mycontroller-org/server/v2/pkg/service/system_jobs/node_job"
+	sessionJob "github.com/mycontroller-org/server/v2/pkg/service/system_jobs/session_job"
 	sunriseJob "github.com/mycontroller-org/server/v2/pkg/service/system_jobs/sunrise_job"
+	taskHistoryJob "github.com/mycontroller-org/server/v2/pkg/service/system_jobs/task_history_job"
 )
 
 // ReloadSystemJobs func
@@ -11,4 +14,7 @@ func ReloadSystemJobs() {
 	sunriseJob.ReloadJob()
 	analyticsJob.ReloadJob()
 	nodeJob.ReloadNodeStateVerifyJob()
+	auditJob.ReloadJob()
+	sessionJob.ReloadJob()
+	taskHistoryJob.ReloadJob()
 }
@@ -2,9 +2,11 @@ package systemjobs
 
 import (
 	"github.com/mycontroller-org/server/v2/pkg/service/mcbus"
+	auditJob "github.com/mycontroller-org/server/v2/pkg/service/system_jobs/audit_job"
 	helper "github.com/mycontroller-org/server/v2/pkg/service/system_jobs/helper_utils"
 	nodeJob "github.com/mycontroller-org/server/v2/pkg/service/system_jobs/node_job"
 	sunriseJob "github.com/mycontroller-org/server/v2/pkg/service/system_jobs/sunrise_job"
+	taskHistoryJob "github.com/mycontroller-org/server/v2/pkg/service/system_jobs/task_history_job"
 	busTY "github.com/mycontroller-org/server/v2/pkg/types/bus"
 	rsTY "github.com/mycontroller-org/server/v2/pkg/types/resource_service"
 	queueUtils "github.com/mycontroller-org/server/v2/pkg/utils/queue"
@@ -82,6 +84,12 @@ func processEvent(event interface{}) {
 	case helper.JobTypeSunriseUpdater:
 		sunriseJob.ReloadJob()
 
+	case helper.JobTypeAuditLogPurger:
+		auditJob.ReloadJob()
+
+	case helper.JobTypeTaskHistoryPurger:
+		taskHistoryJob.ReloadJob()
+
 	default:
 		// NOOP
 	}
@@ -0,0 +1,25 @@
+package systemjobs
+
+import (
+	sessionAPI "github.com/mycontroller-org/server/v2/pkg/api/session"
+	helper "github.com/mycontroller-org/server/v2/pkg/service/system_jobs/helper_utils"
+	"go.uber.org/zap"
+)
+
+const (
+	idSessionPurger   = "session_purger"
+	executionInterval = "@every 1h"
+)
+
+// ReloadJob schedules the expired sessions purge job
+func ReloadJob() {
+	purgeSessions := func() {
+		_, err := sessionAPI.PurgeExpired()
+		if err != nil {
+			zap.L().Error("error on purging expired sessions", zap.Error(err))
+		}
+	}
+
+	// schedule a job
+	helper.Schedule(idSessionPurger, executionInterval, purgeSessions)
+}
@@ -0,0 +1,41 @@
+package systemjobs
+
+import (
+	"time"
+
+	settingsAPI "github.com/mycontroller-org/server/v2/pkg/api/settings"
+	taskHistoryAPI "github.com/mycontroller-org/server/v2/pkg/api/task_history"
+	helper "github.com/mycontroller-org/server/v2/pkg/service/system_jobs/helper_utils"
+	"github.com/mycontroller-org/server/v2/pkg/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	idTaskHistoryPurger = "task_history_purger"
+	executionInterval   = "@every 1h"
+)
+
+// ReloadJob schedules the task history purge job
+func ReloadJob() {
+	settings, err := settingsAPI.GetSystemSettings()
+	if err != nil {
+		zap.L().Error("error on getting system settings", zap.Error(err))
+		return
+	}
+
+	retentionString := settings.TaskHistory.Retention
+	if retentionString == "" {
+		retentionString = taskHistoryAPI.DefaultRetention
+	}
+	retention := utils.ToDuration(retentionString, time.Hour*24*30)
+
+	purgeTaskHistory := func() {
+		_, err := taskHistoryAPI.Purge(retention)
+		if err != nil {
+			zap.L().Error("error on purging task history", zap.Error(err))
+		}
+	}
+
+	// schedule a job
+	helper.Schedule(idTaskHistoryPurger, executionInterval, purgeTaskHistory)
+}
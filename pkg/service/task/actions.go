@@ -0,0 +1,319 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	taskHistoryAPI "github.com/mycontroller-org/server/v2/pkg/api/task_history"
+	"github.com/mycontroller-org/server/v2/pkg/service/mcbus"
+	commonStore "github.com/mycontroller-org/server/v2/pkg/store"
+	busTY "github.com/mycontroller-org/server/v2/pkg/types/bus"
+	taskTY "github.com/mycontroller-org/server/v2/pkg/types/task"
+	taskHistoryTY "github.com/mycontroller-org/server/v2/pkg/types/task_history"
+	"github.com/mycontroller-org/server/v2/pkg/utils"
+	busUtils "github.com/mycontroller-org/server/v2/pkg/utils/bus_utils"
+	variablesUtils "github.com/mycontroller-org/server/v2/pkg/utils/variables"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultHandlerResultTimeout = 30 * time.Second
+	waitActionPollInterval      = time.Second
+)
+
+// running action lists, key is the task id
+var (
+	runningActions      = make(map[string]*actionRunner)
+	runningActionsMutex sync.Mutex
+)
+
+// actionRunner executes the actions of a task in order
+type actionRunner struct {
+	task        *taskTY.Config
+	variables   map[string]interface{}
+	executionID string
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+// startActions executes the actions of the task in the background
+// the running actions of the task are cancelled, the latest execution wins
+func startActions(task *taskTY.Config, variables map[string]interface{}, executionID string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	runner := &actionRunner{
+		task:        task,
+		variables:   variables,
+		executionID: executionID,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+
+	runningActionsMutex.Lock()
+	if previous, found := runningActions[task.ID]; found {
+		zap.L().Debug("cancelling the running actions of a task", zap.String("taskId", task.ID), zap.String("executionId", previous.executionID))
+		previous.cancel()
+	}
+	runningActions[task.ID] = runner
+	runningActionsMutex.Unlock()
+
+	go func() {
+		defer func() {
+			runningActionsMutex.Lock()
+			if runningActions[task.ID] == runner {
+				delete(runningActions, task.ID)
+			}
+			runningActionsMutex.Unlock()
+			cancel()
+		}()
+		runner.runActions(task.Actions, "actions")
+	}()
+}
+
+// stopActions cancels the running actions of the task, all the tasks if the id is empty
+func stopActions(taskID string) {
+	runningActionsMutex.Lock()
+	defer runningActionsMutex.Unlock()
+
+	for id, runner := range runningActions {
+		if taskID == "" || taskID == id {
+			runner.cancel()
+			delete(runningActions, id)
+		}
+	}
+}
+
+// runActions executes the actions in order, returns false if the execution stopped
+func (r *actionRunner) runActions(actions []taskTY.Action, path string) bool {
+	for index, action := range actions {
+		actionPath := fmt.Sprintf("%s.%d", path, index)
+		if r.ctx.Err() != nil {
+			r.addResult(actionPath, action.Type, "cancelled", taskHistoryTY.HandlerStatusError)
+			return false
+		}
+
+		// executes the matching branch of the condition action
+		if action.Type == taskTY.ActionTypeCondition {
+			matched, err := r.evaluateCondition(action.Condition)
+			if err != nil {
+				if !r.onFailure(action, actionPath, err) {
+					return false
+				}
+				continue
+			}
+			branch, branchPath := action.Else, actionPath+".else"
+			if matched {
+				branch, branchPath = action.Then, actionPath+".then"
+			}
+			r.addResult(actionPath, action.Type, fmt.Sprintf("condition matched:%t", matched), taskHistoryTY.HandlerStatusSuccess)
+			if !r.runActions(branch, branchPath) {
+				return false
+			}
+			continue
+		}
+
+		message, err := r.runAction(action)
+		if err != nil {
+			if !r.onFailure(action, actionPath, err) {
+				return false
+			}
+			continue
+		}
+		r.addResult(actionPath, action.Type, message, taskHistoryTY.HandlerStatusSuccess)
+	}
+	return true
+}
+
+// onFailure notifies the fallback handlers and records the failure
+// returns true, if the next actions can be executed
+func (r *actionRunner) onFailure(action taskTY.Action, path string, err error) bool {
+	zap.L().Warn("error on executing a task action", zap.String("taskId", r.task.ID), zap.String("path", path), zap.Error(err))
+	message := err.Error()
+	if len(action.OnFailure) > 0 {
+		parameters := r.getParameters(action)
+		// fallback handlers are not tracked, the failure recorded on the action result
+		busUtils.PostToHandler(action.OnFailure, parameters)
+		message = fmt.Sprintf("%s, notified fallback handlers:%s", message, strings.Join(action.OnFailure, ","))
+	}
+	r.addResult(path, action.Type, message, taskHistoryTY.HandlerStatusError)
+	return action.ContinueOnFailure
+}
+
+// runAction executes an action, returns the result message
+func (r *actionRunner) runAction(action taskTY.Action) (string, error) {
+	switch action.Type {
+	case taskTY.ActionTypeHandler:
+		return r.runHandlerAction(action)
+
+	case taskTY.ActionTypeDelay:
+		delay, err := time.ParseDuration(action.Delay)
+		if err != nil {
+			return "", fmt.Errorf("invalid delay '%s'", action.Delay)
+		}
+		select {
+		case <-time.After(delay):
+			return fmt.Sprintf("waited %s", delay), nil
+		case <-r.ctx.Done():
+			return "", errors.New("cancelled")
+		}
+
+	case taskTY.ActionTypeWait:
+		return r.runWaitAction(action)
+
+	default:
+		return "", fmt.Errorf("unknown action type '%s'", action.Type)
+	}
+}
+
+// evaluateCondition evaluates the condition with the latest values of the variables
+func (r *actionRunner) evaluateCondition(condition taskTY.Rule) (bool, error) {
+	variables, err := r.loadVariables()
+	if err != nil {
+		return false, err
+	}
+	return isTriggered(condition, variables, newConditionContext(time.Now(), nil), nil), nil
+}
+
+// runHandlerAction posts the parameters to the handlers and waits for the results
+func (r *actionRunner) runHandlerAction(action taskTY.Action) (string, error) {
+	timeout := utils.ToDuration(action.Timeout, defaultHandlerResultTimeout)
+
+	handlers := make([]string, 0)
+	for _, handlerID := range action.Handlers {
+		if handlerID != "" {
+			handlers = append(handlers, handlerID)
+		}
+	}
+
+	resultChan := make(chan taskHistoryTY.HandlerResult, len(handlers))
+	replyTopic := mcbus.FormatTopic(fmt.Sprintf("internal_task_action_response_%s", utils.RandIDWithLength(8)))
+	sID, err := mcbus.Subscribe(replyTopic, func(data *busTY.BusData) {
+		result := taskHistoryTY.HandlerResult{}
+		err := data.LoadData(&result)
+		if err != nil {
+			zap.L().Error("error on converting to handler result", zap.Error(err))
+			return
+		}
+		select {
+		case resultChan <- result:
+		default:
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		err := mcbus.Unsubscribe(replyTopic, sID)
+		if err != nil {
+			zap.L().Error("error on unsubscribe", zap.Error(err), zap.String("topic", replyTopic))
+		}
+	}()
+
+	// posted without the execution id, the results of the task handlers are not overwritten
+	parameters := r.getParameters(action)
+	if !busUtils.PostToHandlerWithReply("", replyTopic, handlers, parameters) {
+		return "", errors.New("no enabled handler parameters to post")
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	pending := make(map[string]bool)
+	for _, handlerID := range handlers {
+		pending[handlerID] = true
+	}
+	failures := make([]string, 0)
+	for len(pending) > 0 {
+		select {
+		case result := <-resultChan:
+			if !pending[result.HandlerID] {
+				continue
+			}
+			delete(pending, result.HandlerID)
+			if result.Status == taskHistoryTY.HandlerStatusError {
+				failures = append(failures, fmt.Sprintf("%s:%s", result.HandlerID, result.Error))
+			}
+
+		case <-timer.C:
+			for handlerID := range pending {
+				failures = append(failures, fmt.Sprintf("%s:no result within %s", handlerID, timeout))
+			}
+			pending = nil
+
+		case <-r.ctx.Done():
+			return "", errors.New("cancelled")
+		}
+	}
+
+	if len(failures) > 0 {
+		return "", fmt.Errorf("handlers failed, %s", strings.Join(failures, ", "))
+	}
+	return fmt.Sprintf("notified handlers:%s", strings.Join(handlers, ",")), nil
+}
+
+// runWaitAction waits until the condition matched, fails on the timeout
+func (r *actionRunner) runWaitAction(action taskTY.Action) (string, error) {
+	timeout, err := time.ParseDuration(action.Timeout)
+	if err != nil {
+		return "", fmt.Errorf("invalid timeout '%s'", action.Timeout)
+	}
+
+	start := time.Now()
+	deadline := start.Add(timeout)
+	conditionCtx := newConditionContext(start, nil)
+	for {
+		variables, err := r.loadVariables()
+		if err != nil {
+			return "", err
+		}
+		conditionCtx = newConditionContext(time.Now(), conditionCtx.states)
+		if isTriggered(action.Condition, variables, conditionCtx, nil) {
+			return fmt.Sprintf("condition matched in %s", time.Since(start).Round(time.Millisecond)), nil
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("condition not matched within %s", timeout)
+		}
+
+		select {
+		case <-time.After(waitActionPollInterval):
+		case <-r.ctx.Done():
+			return "", errors.New("cancelled")
+		}
+	}
+}
+
+// loadVariables returns the variables of the task with the latest values
+func (r *actionRunner) loadVariables() (map[string]interface{}, error) {
+	latest, err := variablesUtils.LoadVariables(r.task.Variables, commonStore.CFG.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load a variables, error:%s", err.Error())
+	}
+	return variablesUtils.Merge(r.variables, latest), nil
+}
+
+// getParameters returns the handler parameters of the action, task handler parameters used if empty
+func (r *actionRunner) getParameters(action taskTY.Action) map[string]string {
+	parameters := action.Parameters
+	if len(parameters) == 0 {
+		parameters = r.task.HandlerParameters
+	}
+	return variablesUtils.UpdateParameters(r.variables, parameters)
+}
+
+// addResult records the action result on the task history
+func (r *actionRunner) addResult(path, actionType, message, status string) {
+	result := &taskHistoryTY.ActionResult{
+		Path:      path,
+		Type:      actionType,
+		Status:    status,
+		Message:   message,
+		Timestamp: time.Now(),
+	}
+	err := taskHistoryAPI.AddActionResult(r.executionID, result)
+	if err != nil {
+		zap.L().Error("error on adding action result to the task history", zap.String("taskId", r.task.ID), zap.String("path", path), zap.Error(err))
+	}
+}
@@ -0,0 +1,35 @@
+package task
+
+import (
+	taskTY "github.com/mycontroller-org/server/v2/pkg/types/task"
+	converterUtils "github.com/mycontroller-org/server/v2/pkg/utils/convertor"
+	"github.com/mycontroller-org/server/v2/pkg/utils/expression"
+	"go.uber.org/zap"
+)
+
+func isTriggeredExpression(task *taskTY.Config, variables map[string]interface{}, trace *taskTY.Trace) (map[string]interface{}, bool) {
+	variableNames := task.ExpressionVariableNames()
+	program, err := tasksStore.GetProgram(task.ID, task.EvaluationConfig.Expression, variableNames)
+	if err != nil {
+		zap.L().Error("error on compiling expression", zap.Error(err), zap.String("taskID", task.ID), zap.String("expression", task.EvaluationConfig.Expression))
+		if trace != nil {
+			trace.AddMessage("error on compiling expression, error:%s", err.Error())
+		}
+		return nil, false
+	}
+	result, err := expression.EvaluateProgram(program, variableNames, variables)
+	if err != nil {
+		zap.L().Error("error on evaluating expression", zap.Error(err), zap.String("taskID", task.ID), zap.String("expression", task.EvaluationConfig.Expression))
+		if trace != nil {
+			trace.AddMessage("error on evaluating expression, error:%s", err.Error())
+		}
+		return nil, false
+	}
+	if trace != nil {
+		trace.Output = result
+	}
+	if resultMap, ok := result.(map[string]interface{}); ok {
+		return resultMap, converterUtils.ToBool(resultMap[taskTY.KeyIsTriggered])
+	}
+	return nil, converterUtils.ToBool(result)
+}
@@ -7,12 +7,18 @@ import (
 	"go.uber.org/zap"
 )
 
-func isTriggeredJavascript(taskID string, config taskTY.EvaluationConfig, variables map[string]interface{}) (map[string]interface{}, bool) {
+func isTriggeredJavascript(taskID string, config taskTY.EvaluationConfig, variables map[string]interface{}, trace *taskTY.Trace) (map[string]interface{}, bool) {
 	result, err := javascript.Execute(config.Javascript, variables)
 	if err != nil {
 		zap.L().Error("error on executing script", zap.Error(err), zap.String("taskID", taskID), zap.String("script", config.Javascript))
+		if trace != nil {
+			trace.AddMessage("error on executing script, error:%s", err.Error())
+		}
 		return nil, false
 	}
+	if trace != nil {
+		trace.Output = result
+	}
 	// data, _ := json.Marshal(result)
 	// zap.L().Info("variables", zap.Any("vars", string(data)))
 	if resultMap, ok := result.(map[string]interface{}); ok {
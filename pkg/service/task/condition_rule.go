@@ -15,46 +15,156 @@ import (
 	"go.uber.org/zap"
 )
 
-func isTriggered(rule taskTY.Rule, variables map[string]interface{}) bool {
-	if len(rule.Conditions) == 0 {
+// isTriggered evaluates the rule conditions and the nested groups
+// temporal conditions keep the state on each evaluation, hence all the conditions are evaluated
+// on the dry run, the conditions are recorded on the trace
+func isTriggered(rule taskTY.Rule, variables map[string]interface{}, conditionCtx *conditionContext, trace *taskTY.Trace) bool {
+	if len(rule.Conditions) == 0 && len(rule.Groups) == 0 {
 		return true
 	}
 
-	zap.L().Debug("isTriggered", zap.Any("conditions", rule.Conditions), zap.Any("variables", variables))
+	zap.L().Debug("isTriggered", zap.Any("conditions", rule.Conditions), zap.Any("groups", rule.Groups), zap.Any("variables", variables))
 
-	for index := 0; index < len(rule.Conditions); index++ {
-		condition := rule.Conditions[index]
-		value, err := getValueByVariableName(variables, condition.Variable)
-		if err != nil {
-			zap.L().Warn("error on getting a variable", zap.Error(err))
-			return false
+	triggered, _ := evaluateGroup(rule.Group(), "", variables, conditionCtx, trace)
+	return triggered
+}
+
+// evaluateGroup evaluates the conditions and the nested groups of a group
+// returns the result and the failed status, failed if a condition could not be evaluated
+// the first decisive item decides the result, a failed item decides as not matched
+func evaluateGroup(group taskTY.ConditionGroup, path string, variables map[string]interface{}, conditionCtx *conditionContext, trace *taskTY.Trace) (bool, bool) {
+	matchAll := group.Operator != taskTY.GroupOperatorOr && group.Operator != taskTY.GroupOperatorNot
+	matched := matchAll
+	failed := false
+	decided := false
+
+	update := func(itemMatched, itemFailed bool) {
+		if decided {
+			return
 		}
+		switch {
+		case itemFailed:
+			matched, failed, decided = false, true, true
 
-		expectedValue := condition.Value
-		stringValue := converterUtils.ToString(expectedValue)
+		case matchAll && !itemMatched:
+			matched, decided = false, true
 
-		// process value as template
-		updatedValue, err := tplUtils.Execute(stringValue, variables)
-		if err != nil {
-			zap.L().Warn("error on parsing template", zap.Error(err), zap.String("template", stringValue), zap.Any("variables", variables))
+		case !matchAll && itemMatched:
+			matched, decided = true, true
+		}
+	}
+
+	for index := 0; index < len(group.Conditions); index++ {
+		condition := group.Conditions[index]
+		conditionTrace := evaluateCondition(joinPath(path, "conditions", index), condition, variables, conditionCtx)
+		if trace != nil {
+			trace.Conditions = append(trace.Conditions, *conditionTrace)
+		}
+		if conditionTrace.Matched {
+			zap.L().Debug("condition passed", zap.String("path", conditionTrace.Path), zap.Any("condition", condition), zap.Any("expectedValue", conditionTrace.ExpectedValue))
 		} else {
-			expectedValue = updatedValue
+			zap.L().Debug("condition failed", zap.String("path", conditionTrace.Path), zap.Any("condition", condition), zap.Any("expectedValue", conditionTrace.ExpectedValue))
 		}
+		update(conditionTrace.Matched, conditionTrace.Error != "")
+	}
 
-		valid := isMatching(value, condition.Operator, expectedValue)
+	for index := 0; index < len(group.Groups); index++ {
+		groupMatched, groupFailed := evaluateGroup(group.Groups[index], joinPath(path, "groups", index), variables, conditionCtx, trace)
+		update(groupMatched, groupFailed)
+	}
 
-		if rule.MatchAll && !valid {
-			zap.L().Debug("condition failed", zap.Any("condition", condition), zap.Any("variables", variables), zap.Any("expectedValue", expectedValue))
-			return false
+	switch group.Operator {
+	case taskTY.GroupOperatorAnd, taskTY.GroupOperatorOr, "":
+		// result as is
+
+	case taskTY.GroupOperatorNot:
+		if !failed {
+			matched = !matched
 		}
 
-		if !rule.MatchAll && valid {
-			zap.L().Debug("condition passed", zap.Any("condition", condition), zap.Any("variables", variables), zap.Any("expectedValue", expectedValue))
-			return true
+	default:
+		zap.L().Error("unknown group operator", zap.String("operator", group.Operator), zap.String("path", path))
+		if trace != nil {
+			trace.AddMessage("unknown group operator '%s' on '%s'", group.Operator, path)
 		}
+		return false, true
+	}
+	return matched, failed
+}
+
+// joinPath returns the path of an item on the rule
+func joinPath(path, itemType string, index int) string {
+	if path == "" {
+		return fmt.Sprintf("%s.%d", itemType, index)
+	}
+	return fmt.Sprintf("%s.%s.%d", path, itemType, index)
+}
+
+// evaluateCondition returns the values and the result of a condition
+func evaluateCondition(path string, condition taskTY.Conditions, variables map[string]interface{}, conditionCtx *conditionContext) *taskTY.ConditionTrace {
+	conditionTrace := &taskTY.ConditionTrace{
+		Path:          path,
+		Type:          condition.Type,
+		Variable:      condition.Variable,
+		Operator:      condition.Operator,
+		ExpectedValue: condition.Value,
+	}
+	if conditionTrace.Operator == "" {
+		conditionTrace.Operator = storageTY.OperatorEqual
+	}
+
+	switch condition.Type {
+	case taskTY.ConditionTypeValue, "":
+		// evaluated below
+
+	case taskTY.ConditionTypeForDuration:
+		evaluateValue(condition, variables, conditionTrace)
+		conditionCtx.evaluateForDuration(path, condition, conditionTrace)
+		return conditionTrace
+
+	case taskTY.ConditionTypeChangedCount:
+		conditionCtx.evaluateChangedCount(path, condition, variables, conditionTrace)
+		return conditionTrace
+
+	case taskTY.ConditionTypeNoUpdate:
+		conditionCtx.evaluateNoUpdate(condition, variables, conditionTrace)
+		return conditionTrace
+
+	case taskTY.ConditionTypeSunWindow:
+		conditionCtx.evaluateSunWindow(condition, conditionTrace)
+		return conditionTrace
+
+	default:
+		conditionTrace.Error = fmt.Sprintf("unknown condition type '%s'", condition.Type)
+		return conditionTrace
+	}
+
+	evaluateValue(condition, variables, conditionTrace)
+	return conditionTrace
+}
+
+// evaluateValue compares the value of the variable with the expected value
+func evaluateValue(condition taskTY.Conditions, variables map[string]interface{}, conditionTrace *taskTY.ConditionTrace) {
+	value, err := getValueByVariableName(variables, condition.Variable)
+	if err != nil {
+		zap.L().Warn("error on getting a variable", zap.Error(err))
+		conditionTrace.Error = err.Error()
+		return
 	}
+	conditionTrace.Value = value
+	conditionTrace.ExpectedValue = getExpectedValue(condition, variables)
+	conditionTrace.Matched = isMatching(value, conditionTrace.Operator, conditionTrace.ExpectedValue)
+}
 
-	return rule.MatchAll
+// getExpectedValue returns the expected value of the condition, processed as a template
+func getExpectedValue(condition taskTY.Conditions, variables map[string]interface{}) interface{} {
+	stringValue := converterUtils.ToString(condition.Value)
+	updatedValue, err := tplUtils.Execute(stringValue, variables)
+	if err != nil {
+		zap.L().Warn("error on parsing template", zap.Error(err), zap.String("template", stringValue), zap.Any("variables", variables))
+		return condition.Value
+	}
+	return updatedValue
 }
 
 func getValueByVariableName(variables map[string]interface{}, variableName string) (interface{}, error) {
@@ -88,6 +198,11 @@ func isMatching(value interface{}, operator string, expectedValue interface{}) b
 		operator = storageTY.OperatorEqual
 	}
 
+	if value == nil {
+		zap.L().Debug("ismatching, value is nil", zap.String("operator", operator), zap.Any("expectedValue", expectedValue))
+		return false
+	}
+
 	// format value to actual type
 	value = formatValue(value)
 
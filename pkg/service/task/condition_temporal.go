@@ -0,0 +1,211 @@
+package task
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mycontroller-org/server/v2/pkg/api/sunrise"
+	taskTY "github.com/mycontroller-org/server/v2/pkg/types/task"
+	converterUtils "github.com/mycontroller-org/server/v2/pkg/utils/convertor"
+	"go.uber.org/zap"
+)
+
+// conditionContext holds the state of the temporal conditions on an evaluation
+type conditionContext struct {
+	now       time.Time
+	previous  map[string]taskTY.ConditionState
+	states    map[string]taskTY.ConditionState // updated states, stored on the task state
+	recheckAt time.Time                        // earliest time to re-evaluate the temporal conditions, zero if not required
+}
+
+func newConditionContext(now time.Time, previous map[string]taskTY.ConditionState) *conditionContext {
+	if previous == nil {
+		previous = make(map[string]taskTY.ConditionState)
+	}
+	return &conditionContext{
+		now:      now,
+		previous: previous,
+		states:   make(map[string]taskTY.ConditionState),
+	}
+}
+
+// updates the recheck time, if the supplied time is earlier
+func (cc *conditionContext) setRecheck(recheckAt time.Time) {
+	if cc.recheckAt.IsZero() || recheckAt.Before(cc.recheckAt) {
+		cc.recheckAt = recheckAt
+	}
+}
+
+// evaluateForDuration verifies the value comparison matched continuously for the duration
+// value comparison result expected on the trace
+func (cc *conditionContext) evaluateForDuration(key string, condition taskTY.Conditions, conditionTrace *taskTY.ConditionTrace) {
+	if conditionTrace.Error != "" {
+		return
+	}
+	duration, err := getConditionDuration(condition)
+	if err != nil {
+		conditionTrace.Error = err.Error()
+		return
+	}
+
+	if !conditionTrace.Matched {
+		conditionTrace.Details = "value not matching"
+		return
+	}
+
+	matchedSince := cc.previous[key].MatchedSince
+	if matchedSince.IsZero() {
+		matchedSince = cc.now
+	}
+	cc.states[key] = taskTY.ConditionState{MatchedSince: matchedSince}
+
+	matchedDuration := cc.now.Sub(matchedSince)
+	conditionTrace.Matched = matchedDuration >= duration
+	conditionTrace.Details = fmt.Sprintf("matched for %s, required %s", matchedDuration.Round(time.Second), duration)
+	if !conditionTrace.Matched {
+		cc.setRecheck(matchedSince.Add(duration))
+	}
+}
+
+// evaluateChangedCount compares the number of value changes within the duration
+func (cc *conditionContext) evaluateChangedCount(key string, condition taskTY.Conditions, variables map[string]interface{}, conditionTrace *taskTY.ConditionTrace) {
+	duration, err := getConditionDuration(condition)
+	if err != nil {
+		conditionTrace.Error = err.Error()
+		return
+	}
+	value, err := getValueByVariableName(variables, condition.Variable)
+	if err != nil {
+		zap.L().Warn("error on getting a variable", zap.Error(err))
+		conditionTrace.Error = err.Error()
+		return
+	}
+
+	previous, found := cc.previous[key]
+	changes := make([]time.Time, 0)
+	for _, changedAt := range previous.Changes {
+		if cc.now.Sub(changedAt) < duration {
+			changes = append(changes, changedAt)
+		}
+	}
+	// the first evaluation keeps the value as the reference
+	if found && previous.LastValue != nil && converterUtils.ToString(previous.LastValue) != converterUtils.ToString(value) {
+		changes = append(changes, cc.now)
+	}
+	cc.states[key] = taskTY.ConditionState{LastValue: value, Changes: changes}
+
+	// count decreases, when the changes move out of the duration
+	if len(changes) > 0 {
+		cc.setRecheck(changes[0].Add(duration))
+	}
+
+	conditionTrace.Value = len(changes)
+	conditionTrace.ExpectedValue = getExpectedValue(condition, variables)
+	conditionTrace.Matched = isMatching(len(changes), conditionTrace.Operator, conditionTrace.ExpectedValue)
+	conditionTrace.Details = fmt.Sprintf("changed %d times within %s", len(changes), duration)
+}
+
+// evaluateNoUpdate verifies the timestamp on the variable is older than the duration
+func (cc *conditionContext) evaluateNoUpdate(condition taskTY.Conditions, variables map[string]interface{}, conditionTrace *taskTY.ConditionTrace) {
+	duration, err := getConditionDuration(condition)
+	if err != nil {
+		conditionTrace.Error = err.Error()
+		return
+	}
+	value, err := getValueByVariableName(variables, condition.Variable)
+	if err != nil {
+		zap.L().Warn("error on getting a variable", zap.Error(err))
+		conditionTrace.Error = err.Error()
+		return
+	}
+	conditionTrace.Value = value
+	conditionTrace.Operator = "" // operator not used
+	conditionTrace.ExpectedValue = condition.Duration
+
+	updatedAt, err := toTime(value)
+	if err != nil {
+		conditionTrace.Error = err.Error()
+		return
+	}
+
+	noUpdateDuration := cc.now.Sub(updatedAt)
+	conditionTrace.Matched = noUpdateDuration >= duration
+	conditionTrace.Details = fmt.Sprintf("no update for %s, required %s", noUpdateDuration.Round(time.Second), duration)
+	if !conditionTrace.Matched {
+		cc.setRecheck(updatedAt.Add(duration))
+	}
+}
+
+// evaluateSunWindow verifies the current time is on the day or on the night
+func (cc *conditionContext) evaluateSunWindow(condition taskTY.Conditions, conditionTrace *taskTY.ConditionTrace) {
+	expected := converterUtils.ToString(condition.Value)
+	if expected == "" {
+		expected = taskTY.SunWindowDay
+	}
+	conditionTrace.Operator = "" // operator not used
+	conditionTrace.ExpectedValue = expected
+	if expected != taskTY.SunWindowDay && expected != taskTY.SunWindowNight {
+		conditionTrace.Error = fmt.Sprintf("invalid sun window '%s', supported: %s, %s", expected, taskTY.SunWindowDay, taskTY.SunWindowNight)
+		return
+	}
+
+	sunriseTime, err := sunrise.GetSunriseTime()
+	if err != nil {
+		conditionTrace.Error = fmt.Sprintf("error on getting sunrise time, error:%s", err.Error())
+		return
+	}
+	sunsetTime, err := sunrise.GetSunsetTime()
+	if err != nil {
+		conditionTrace.Error = fmt.Sprintf("error on getting sunset time, error:%s", err.Error())
+		return
+	}
+
+	current := taskTY.SunWindowNight
+	if !cc.now.Before(*sunriseTime) && cc.now.Before(*sunsetTime) {
+		current = taskTY.SunWindowDay
+	}
+	conditionTrace.Value = current
+	conditionTrace.Matched = current == expected
+	conditionTrace.Details = fmt.Sprintf("sunrise %s, sunset %s", sunriseTime.Format(time.Kitchen), sunsetTime.Format(time.Kitchen))
+
+	// re-evaluate on the next sunrise or sunset
+	switch {
+	case cc.now.Before(*sunriseTime):
+		cc.setRecheck(*sunriseTime)
+	case cc.now.Before(*sunsetTime):
+		cc.setRecheck(*sunsetTime)
+	default:
+		cc.setRecheck(sunriseTime.Add(24 * time.Hour))
+	}
+}
+
+func getConditionDuration(condition taskTY.Conditions) (time.Duration, error) {
+	duration, err := time.ParseDuration(condition.Duration)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration '%s' on the '%s' condition, error:%s", condition.Duration, condition.Type, err.Error())
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("duration should be greater than zero on the '%s' condition", condition.Type)
+	}
+	return duration, nil
+}
+
+// toTime converts the value to time
+func toTime(value interface{}) (time.Time, error) {
+	switch typedValue := value.(type) {
+	case time.Time:
+		return typedValue, nil
+
+	case *time.Time:
+		if typedValue != nil {
+			return *typedValue, nil
+		}
+
+	case string:
+		parsedTime, err := time.Parse(time.RFC3339Nano, typedValue)
+		if err == nil {
+			return parsedTime, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("value is not a timestamp, value:%v", value)
+}
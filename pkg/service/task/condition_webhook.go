@@ -14,7 +14,7 @@ import (
 
 const timeout = time.Second * 10
 
-func isTriggeredWebhook(taskID string, config taskTY.EvaluationConfig, variables map[string]interface{}) (map[string]interface{}, bool) {
+func isTriggeredWebhook(taskID string, config taskTY.EvaluationConfig, variables map[string]interface{}, trace *taskTY.Trace) (map[string]interface{}, bool) {
 	whCfg := config.Webhook
 	client := httpclient.GetClient(whCfg.Insecure, timeout)
 	if !whCfg.IncludeConfig {
@@ -32,8 +32,14 @@ func isTriggeredWebhook(taskID string, config taskTY.EvaluationConfig, variables
 	}
 	if err != nil {
 		zap.L().Error("error on executing webhook", zap.Error(err), zap.String("taskID", taskID), zap.String("url", whCfg.URL), zap.Int("responseStatusCode", responseStatusCode))
+		if trace != nil {
+			trace.AddMessage("error on executing webhook, responseStatusCode:%d, error:%s", responseStatusCode, err.Error())
+		}
 		return nil, false
 	}
+	if trace != nil {
+		trace.Output = res.StringBody()
+	}
 
 	resultMap := make(map[string]interface{})
 
@@ -43,6 +49,10 @@ func isTriggeredWebhook(taskID string, config taskTY.EvaluationConfig, variables
 		return nil, converterUtils.ToBool(res.StringBody())
 	}
 
+	if trace != nil {
+		trace.Output = resultMap
+	}
+
 	zap.L().Debug("webhook response", zap.String("taskID", taskID), zap.Any("response", resultMap))
 	if len(resultMap) > 0 {
 		isTriggered, isTriggeredFound := resultMap[taskTY.KeyIsTriggered]
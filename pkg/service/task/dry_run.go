@@ -0,0 +1,85 @@
+package task
+
+import (
+	"errors"
+
+	taskAPI "github.com/mycontroller-org/server/v2/pkg/api/task"
+	eventTY "github.com/mycontroller-org/server/v2/pkg/types/bus/event"
+	taskTY "github.com/mycontroller-org/server/v2/pkg/types/task"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+)
+
+// DryRunRequest evaluates a task without notifying the handlers
+// saved task loaded by the id, if the task config not supplied
+type DryRunRequest struct {
+	ID    string         `json:"id"`
+	Task  *taskTY.Config `json:"task"`
+	Event *eventTY.Event `json:"event"` // synthetic event, optional
+}
+
+// DryRun evaluates a task against the current data or the supplied event and returns the trace
+// handlers are not notified and the state of the task is not updated
+func DryRun(request *DryRunRequest) (*taskTY.Trace, error) {
+	id := request.ID
+	if request.Task != nil && request.Task.ID != "" {
+		id = request.Task.ID
+	}
+
+	// state of the saved task used on the dampening
+	var savedTask *taskTY.Config
+	if id != "" {
+		task, err := taskAPI.GetByID(id)
+		if err != nil && !errors.Is(err, storageTY.ErrNoDocuments) {
+			return nil, err
+		}
+		if err == nil {
+			savedTask = task
+		}
+	}
+
+	var task *taskTY.Config
+	switch {
+	case request.Task != nil:
+		task = request.Task
+		task.State = nil
+		if savedTask != nil {
+			task.State = savedTask.State
+		}
+
+	case savedTask != nil:
+		task = savedTask
+
+	case id != "":
+		return nil, storageTY.ErrNoDocuments
+
+	default:
+		return nil, errors.New("task id or task config required")
+	}
+	if task.State == nil {
+		task.State = &taskTY.State{}
+	}
+
+	trace := &taskTY.Trace{
+		Conditions: make([]taskTY.ConditionTrace, 0),
+		Messages:   make([]string, 0),
+	}
+
+	var evntWrapper *eventWrapper
+	if request.Event != nil {
+		event := *request.Event
+		if isSupportedEntity(event.EntityType) {
+			if err := loadEventEntity(&event); err != nil {
+				return nil, err
+			}
+		}
+		evntWrapper = &eventWrapper{Event: &event}
+		matched := tasksStore.isEventMatching(task, evntWrapper)
+		trace.EventMatched = &matched
+		if !matched {
+			trace.AddMessage("event not matching with the trigger and the event filter of the task")
+		}
+	}
+
+	executeTask(task, evntWrapper, trace)
+	return trace, nil
+}
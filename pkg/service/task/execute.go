@@ -1,11 +1,13 @@
 package task
 
 import (
+	"fmt"
 	"time"
 
 	commonStore "github.com/mycontroller-org/server/v2/pkg/store"
 	types "github.com/mycontroller-org/server/v2/pkg/types"
 	taskTY "github.com/mycontroller-org/server/v2/pkg/types/task"
+	taskHistoryTY "github.com/mycontroller-org/server/v2/pkg/types/task_history"
 	"github.com/mycontroller-org/server/v2/pkg/utils"
 	busUtils "github.com/mycontroller-org/server/v2/pkg/utils/bus_utils"
 	scheduleUtils "github.com/mycontroller-org/server/v2/pkg/utils/schedule"
@@ -17,22 +19,36 @@ const (
 	defaultExecutionsSliceLimit = 10
 )
 
-func executeTask(task *taskTY.Config, evntWrapper *eventWrapper) {
+// executeTask evaluates the task and notifies the handlers
+// on the dry run (trace supplied), the evaluation details are recorded on the trace,
+// handlers are not notified and the state is not updated
+func executeTask(task *taskTY.Config, evntWrapper *eventWrapper, trace *taskTY.Trace) {
 	start := time.Now()
+	isDryRun := trace != nil
 
 	state := task.State
 	state.ExecutedCount++
 	state.LastEvaluation = start
 
+	// execution entry of the task history, not recorded on the dry run
+	// records the failures and the triggered executions, evaluations without a trigger are not recorded
+	history := newHistoryEntry(task, evntWrapper, start)
+
 	zap.L().Debug("executing a task", zap.String("id", task.ID), zap.String("description", task.Description))
 	// load variables
-	variables, err := variablesUtils.LoadVariables(task.Variables, commonStore.CFG.Secret)
+	variables, redactedVariables, err := variablesUtils.LoadVariablesWithRedacted(task.Variables, commonStore.CFG.Secret)
 	if err != nil {
 		zap.L().Warn("failed to load variables", zap.Error(err), zap.String("taskID", task.ID), zap.String("taskDescription", task.Description))
+		if isDryRun {
+			trace.AddMessage("failed to load a variables, error:%s", err.Error())
+			return
+		}
 		// update failure message for state and send it
 		state.LastStatus = false
 		state.Message = "failed to load a variables"
 		tasksStore.UpdateState(task.ID, state)
+		history.Error = fmt.Sprintf("failed to load a variables, error:%s", err.Error())
+		addHistoryEntry(history)
 		return
 	}
 
@@ -46,20 +62,36 @@ func executeTask(task *taskTY.Config, evntWrapper *eventWrapper) {
 	// execute conditions
 	switch task.EvaluationType {
 	case taskTY.EvaluationTypeRule:
-		triggered = isTriggered(task.EvaluationConfig.Rule, variables)
+		conditionCtx := newConditionContext(start, state.Conditions)
+		triggered = isTriggered(task.EvaluationConfig.Rule, variables, conditionCtx, trace)
+		if !isDryRun {
+			state.Conditions = conditionCtx.states
+			scheduleConditionRecheck(task, conditionCtx.recheckAt)
+		}
 
 	case taskTY.EvaluationTypeJavascript:
-		responseMap, triggeredStatus := isTriggeredJavascript(task.ID, task.EvaluationConfig, variables)
+		responseMap, triggeredStatus := isTriggeredJavascript(task.ID, task.EvaluationConfig, variables, trace)
+		triggered = triggeredStatus
+		variables = variablesUtils.Merge(variables, responseMap)
+
+	case taskTY.EvaluationTypeExpression:
+		responseMap, triggeredStatus := isTriggeredExpression(task, variables, trace)
 		triggered = triggeredStatus
 		variables = variablesUtils.Merge(variables, responseMap)
 
 	case taskTY.EvaluationTypeWebhook:
-		responseMap, triggeredStatus := isTriggeredWebhook(task.ID, task.EvaluationConfig, variables)
+		responseMap, triggeredStatus := isTriggeredWebhook(task.ID, task.EvaluationConfig, variables, trace)
 		triggered = triggeredStatus
 		variables = variablesUtils.Merge(variables, responseMap)
 
 	default:
 		zap.L().Error("unknown evaluation type", zap.String("type", task.EvaluationType), zap.String("taskId", task.ID))
+		if isDryRun {
+			trace.AddMessage("unknown evaluation type '%s'", task.EvaluationType)
+			return
+		}
+		history.Error = fmt.Sprintf("unknown evaluation type '%s'", task.EvaluationType)
+		addHistoryEntry(history)
 		return
 	}
 
@@ -87,10 +119,17 @@ func executeTask(task *taskTY.Config, evntWrapper *eventWrapper) {
 		dampeningTriggered, executionsSliceLimit = executeDampeningEvaluations(task, triggered)
 
 	case taskTY.DampeningTypeActiveDuration:
-		dampeningTriggered = executeDampeningActiveDuration(task, triggered)
+		dampeningTriggered = executeDampeningActiveDuration(task, triggered, isDryRun)
 
 	default:
 		zap.L().Error("unknown dampening type", zap.String("type", task.Dampening.Type), zap.String("taskId", task.ID))
+		if isDryRun {
+			trace.AddMessage("unknown dampening type '%s'", task.Dampening.Type)
+			return
+		}
+		history.Triggered = triggered
+		history.Error = fmt.Sprintf("unknown dampening type '%s'", task.Dampening.Type)
+		addHistoryEntry(history)
 		return
 	}
 
@@ -101,14 +140,54 @@ func executeTask(task *taskTY.Config, evntWrapper *eventWrapper) {
 			notifyHandlers = true
 		} else if !state.LastStatus { // if ignoreDuplicate and last status false
 			notifyHandlers = true
+		} else if isDryRun {
+			trace.AddMessage("duplicate ignored, triggered on the last evaluation")
 		}
+	} else if triggered && isDryRun {
+		trace.AddMessage("triggered, but the dampening '%s' not satisfied", task.Dampening.Type)
 	}
 
+	if isDryRun {
+		// decrypted secrets are not exposed on the trace
+		traceVariables := variablesUtils.Merge(variables, redactedVariables)
+		trace.Variables = traceVariables
+		trace.Triggered = triggered
+		trace.DampeningTriggered = dampeningTriggered
+		trace.NotifyHandlers = notifyHandlers
+		if notifyHandlers {
+			trace.Handlers = task.Handlers
+			trace.HandlerParameters = variablesUtils.UpdateParameters(traceVariables, task.HandlerParameters)
+			trace.Actions = task.Actions
+		}
+		redactTrace(trace, variablesUtils.GetSecretValues(variables, redactedVariables))
+		return
+	}
+
+	history.Triggered = triggered
+	history.DampeningTriggered = dampeningTriggered
+
 	if notifyHandlers {
 		state.LastSuccess = start // update last success time
 		parameters := variablesUtils.UpdateParameters(variables, task.HandlerParameters)
 		variablesUtils.UpdateParameters(variables, parameters)
-		busUtils.PostToHandler(task.Handlers, parameters)
+
+		// the entry should be available before the handlers and actions report the results
+		if len(task.Handlers) > 0 {
+			history.NotifiedHandlers = true
+			history.Handlers = taskHistoryTY.NewHandlerResults(task.Handlers)
+		}
+		addHistoryEntry(history)
+		if len(task.Handlers) > 0 && !busUtils.PostToHandlerWithExecutionID(history.ID, task.Handlers, parameters) {
+			history.NotifiedHandlers = false
+			history.Handlers = []taskHistoryTY.HandlerResult{}
+			history.Error = "no enabled handler parameters to post"
+			saveHistoryEntry(history)
+		}
+		if len(task.Actions) > 0 {
+			startActions(task, variables, history.ID)
+		}
+	} else if triggered {
+		addHistoryEntry(history)
 	}
 
 	// limit executions status slice
@@ -164,10 +243,16 @@ func executeDampeningEvaluations(task *taskTY.Config, triggered bool) (bool, int
 }
 
 // verifies the active duration dampening
-func executeDampeningActiveDuration(task *taskTY.Config, triggered bool) bool {
+// schedules are not updated on the dry run
+func executeDampeningActiveDuration(task *taskTY.Config, triggered, isDryRun bool) bool {
 	scheduleID := scheduleUtils.GetScheduleID(schedulePrefix, task.ID, scheduleTypeActiveDuration)
+	unscheduleFn := func() {
+		if !isDryRun {
+			unschedule(scheduleID)
+		}
+	}
 	if !triggered {
-		unschedule(scheduleID)
+		unscheduleFn()
 		task.State.ActiveSince = time.Time{}
 		return false
 	}
@@ -175,7 +260,7 @@ func executeDampeningActiveDuration(task *taskTY.Config, triggered bool) bool {
 	now := time.Now()
 	activeDuration := utils.ToDuration(task.Dampening.ActiveDuration, 0)
 	if activeDuration == 0 {
-		unschedule(scheduleID)
+		unscheduleFn()
 		zap.L().Debug("active duration can not be zero in a task", zap.String("id", task.ID), zap.String("activeDuration", task.Dampening.ActiveDuration))
 		return false
 	}
@@ -185,12 +270,37 @@ func executeDampeningActiveDuration(task *taskTY.Config, triggered bool) bool {
 	// Note: in case, if active duration doesn't work properly, revisit activeSince and activeDuration
 	activeSince += time.Millisecond * 500
 	if activeSince >= activeDuration {
-		unschedule(scheduleID)
+		unscheduleFn()
 		return true
 	} else {
-		if !scheduleUtils.IsScheduleAvailable(scheduleID) {
+		if !isDryRun && !scheduleUtils.IsScheduleAvailable(scheduleID) {
 			schedule(scheduleTypeActiveDuration, task.Dampening.ActiveDuration, task)
 		}
 		return false
 	}
 }
+
+// redactTrace masks the secret values on the evaluated values and on the outputs
+// the values of the conditions, scripts and webhooks can be derived from the secrets
+func redactTrace(trace *taskTY.Trace, secretValues []string) {
+	if len(secretValues) == 0 {
+		return
+	}
+	for index := range trace.Conditions {
+		condition := &trace.Conditions[index]
+		condition.Value = variablesUtils.RedactValues(condition.Value, secretValues)
+		condition.ExpectedValue = variablesUtils.RedactValues(condition.ExpectedValue, secretValues)
+		condition.Details = variablesUtils.RedactString(condition.Details, secretValues, false)
+		condition.Error = variablesUtils.RedactString(condition.Error, secretValues, false)
+	}
+	trace.Output = variablesUtils.RedactValues(trace.Output, secretValues)
+	if variables, ok := variablesUtils.RedactValues(trace.Variables, secretValues).(map[string]interface{}); ok {
+		trace.Variables = variables
+	}
+	for name, value := range trace.HandlerParameters {
+		trace.HandlerParameters[name] = variablesUtils.RedactString(value, secretValues, false)
+	}
+	for index, message := range trace.Messages {
+		trace.Messages[index] = variablesUtils.RedactString(message, secretValues, false)
+	}
+}
@@ -0,0 +1,48 @@
+package task
+
+import (
+	"time"
+
+	taskHistoryAPI "github.com/mycontroller-org/server/v2/pkg/api/task_history"
+	taskTY "github.com/mycontroller-org/server/v2/pkg/types/task"
+	taskHistoryTY "github.com/mycontroller-org/server/v2/pkg/types/task_history"
+	"github.com/mycontroller-org/server/v2/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// newHistoryEntry returns the execution entry of the task
+func newHistoryEntry(task *taskTY.Config, evntWrapper *eventWrapper, start time.Time) *taskHistoryTY.Entry {
+	entry := &taskHistoryTY.Entry{
+		ID:          utils.RandUUID(),
+		Timestamp:   start,
+		SourceType:  taskHistoryTY.SourceTypeTask,
+		SourceID:    task.ID,
+		Description: task.Description,
+		Handlers:    []taskHistoryTY.HandlerResult{},
+	}
+	if evntWrapper != nil && evntWrapper.Event != nil {
+		entry.Event = &taskHistoryTY.TriggerEvent{
+			Type:       evntWrapper.Event.Type,
+			EntityType: evntWrapper.Event.EntityType,
+			EntityID:   evntWrapper.Event.EntityID,
+		}
+	}
+	return entry
+}
+
+// addHistoryEntry persists the execution entry with the duration
+func addHistoryEntry(entry *taskHistoryTY.Entry) {
+	entry.Duration = time.Since(entry.Timestamp).String()
+	err := taskHistoryAPI.Add(entry)
+	if err != nil {
+		zap.L().Error("error on adding task history entry", zap.String("taskId", entry.SourceID), zap.Error(err))
+	}
+}
+
+// saveHistoryEntry updates the persisted execution entry
+func saveHistoryEntry(entry *taskHistoryTY.Entry) {
+	err := taskHistoryAPI.Save(entry)
+	if err != nil {
+		zap.L().Error("error on updating task history entry", zap.String("taskId", entry.SourceID), zap.Error(err))
+	}
+}
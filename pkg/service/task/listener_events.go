@@ -1,6 +1,8 @@
 package task
 
 import (
+	"fmt"
+
 	"github.com/mycontroller-org/server/v2/pkg/service/mcbus"
 	types "github.com/mycontroller-org/server/v2/pkg/types"
 	busTY "github.com/mycontroller-org/server/v2/pkg/types/bus"
@@ -78,36 +80,17 @@ func processPreEvent(item interface{}) {
 		return
 	}
 
-	var out interface{}
-
 	// supported entity events
-	switch event.EntityType {
-	case types.EntityGateway:
-		out = &gatewayTY.Config{}
-
-	case types.EntityNode:
-		out = &nodeTY.Node{}
-
-	case types.EntitySource:
-		out = &source.Source{}
-
-	case types.EntityField:
-		out = &fieldTY.Field{}
-
-	case types.EntityDataRepository:
-		out = &dataRepositoryTY.Config{}
-
-	default:
+	if !isSupportedEntity(event.EntityType) {
 		// return do not proceed further
 		return
 	}
 
-	err = event.LoadEntity(out)
+	err = loadEventEntity(event)
 	if err != nil {
 		zap.L().Warn("error on loading entity", zap.Any("event", event), zap.Error(err))
 		return
 	}
-	event.Entity = out
 
 	resourceWrapper := &eventWrapper{Event: event}
 	err = resourcePreProcessor(resourceWrapper)
@@ -124,6 +107,46 @@ func processPreEvent(item interface{}) {
 	}
 }
 
+// isSupportedEntity returns true, if the tasks can be triggered on the entity events
+func isSupportedEntity(entityType string) bool {
+	switch entityType {
+	case types.EntityGateway, types.EntityNode, types.EntitySource, types.EntityField, types.EntityDataRepository:
+		return true
+	}
+	return false
+}
+
+// loadEventEntity converts the entity of the event to the actual type
+func loadEventEntity(event *eventTY.Event) error {
+	var out interface{}
+	switch event.EntityType {
+	case types.EntityGateway:
+		out = &gatewayTY.Config{}
+
+	case types.EntityNode:
+		out = &nodeTY.Node{}
+
+	case types.EntitySource:
+		out = &source.Source{}
+
+	case types.EntityField:
+		out = &fieldTY.Field{}
+
+	case types.EntityDataRepository:
+		out = &dataRepositoryTY.Config{}
+
+	default:
+		return fmt.Errorf("unsupported entity type: %s", event.EntityType)
+	}
+
+	err := event.LoadEntity(out)
+	if err != nil {
+		return err
+	}
+	event.Entity = out
+	return nil
+}
+
 func resourcePreProcessor(evntWrapper *eventWrapper) error {
 	zap.L().Debug("eventWrapper received", zap.Any("eventWrapper", evntWrapper))
 
@@ -151,6 +174,6 @@ func resourcePostProcessor(item interface{}) {
 
 	for index := 0; index < len(evntWrapper.Tasks); index++ {
 		task := evntWrapper.Tasks[index]
-		executeTask(&task, evntWrapper)
+		executeTask(&task, evntWrapper, nil)
 	}
 }
@@ -2,6 +2,8 @@ package task
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	taskTY "github.com/mycontroller-org/server/v2/pkg/types/task"
 	busUtils "github.com/mycontroller-org/server/v2/pkg/utils/bus_utils"
@@ -14,6 +16,13 @@ const (
 	scheduleTypePolling        = "polling"
 	scheduleTypeActiveDuration = "active_duration"
 	scheduleTypeReEnable       = "re_enable"
+	scheduleTypeRecheck        = "condition_recheck"
+)
+
+var (
+	// scheduled re-evaluation time of the tasks, to avoid rescheduling on each evaluation
+	recheckTimes = make(map[string]time.Time)
+	recheckMutex sync.Mutex
 )
 
 func schedule(scheduleType, interval string, task *taskTY.Config) string {
@@ -39,6 +48,14 @@ func unschedule(scheduleID string) {
 }
 
 func unscheduleAll(taskID string) {
+	recheckMutex.Lock()
+	if taskID == "" {
+		recheckTimes = make(map[string]time.Time)
+	} else {
+		delete(recheckTimes, taskID)
+	}
+	recheckMutex.Unlock()
+
 	if taskID == "" {
 		scheduleUtils.UnscheduleAll(schedulePrefix)
 	} else {
@@ -76,14 +93,63 @@ func taskActiveDurationFunc(task *taskTY.Config) func() {
 		unschedule(scheduleID)
 		// execute active task
 		zap.L().Debug("verifying a active duration dampening task", zap.String("id", taskID))
-		executeTask(task, nil)
+		executeTask(task, nil, nil)
+	}
+}
+
+// re-evaluates the temporal conditions of a task
+func taskRecheckFunc(task *taskTY.Config) func() {
+	scheduleID := scheduleUtils.GetScheduleID(schedulePrefix, task.ID, scheduleTypeRecheck)
+	taskID := task.ID
+	return func() {
+		// remove the schedule
+		unschedule(scheduleID)
+		recheckMutex.Lock()
+		delete(recheckTimes, taskID)
+		recheckMutex.Unlock()
+		zap.L().Debug("re-evaluating temporal conditions of a task", zap.String("id", taskID))
+		executeTask(task, nil, nil)
+	}
+}
+
+// scheduleConditionRecheck schedules a re-evaluation of the temporal conditions
+// rescheduled only when the re-evaluation time changed, removes the schedule, if not required
+// the task state is not updated, evaluated on each event
+func scheduleConditionRecheck(task *taskTY.Config, recheckAt time.Time) {
+	recheckMutex.Lock()
+	defer recheckMutex.Unlock()
+
+	scheduled, found := recheckTimes[task.ID]
+	if found && scheduled.Equal(recheckAt) {
+		return
+	}
+
+	scheduleID := scheduleUtils.GetScheduleID(schedulePrefix, task.ID, scheduleTypeRecheck)
+	if found {
+		unschedule(scheduleID)
+		delete(recheckTimes, task.ID)
+	}
+	if recheckAt.IsZero() {
+		return
+	}
+
+	interval := time.Until(recheckAt).Round(time.Second) + time.Second // avoids false on the edge
+	if interval < time.Second {
+		interval = time.Second
+	}
+	cronSpec := fmt.Sprintf("@every %s", interval)
+	err := scheduleUtils.Schedule(scheduleID, cronSpec, taskRecheckFunc(task))
+	if err != nil {
+		zap.L().Error("error on scheduling the re-evaluation of a task", zap.String("id", task.ID), zap.String("cronSpec", cronSpec), zap.Error(err))
+		return
 	}
+	recheckTimes[task.ID] = recheckAt
 }
 
 func pollingTaskTriggerFunc(task *taskTY.Config) func() {
 	return func() {
 		zap.L().Debug("executing a task by polling", zap.String("id", task.ID))
-		executeTask(task, nil)
+		executeTask(task, nil, nil)
 	}
 }
 
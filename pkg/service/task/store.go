@@ -1,11 +1,16 @@
 package task
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
+	"github.com/google/cel-go/cel"
 	taskTY "github.com/mycontroller-org/server/v2/pkg/types/task"
 	"github.com/mycontroller-org/server/v2/pkg/utils"
 	busUtils "github.com/mycontroller-org/server/v2/pkg/utils/bus_utils"
+	"github.com/mycontroller-org/server/v2/pkg/utils/expression"
 	filterUtils "github.com/mycontroller-org/server/v2/pkg/utils/filter_sort"
 	scheduleUtils "github.com/mycontroller-org/server/v2/pkg/utils/schedule"
 	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
@@ -14,13 +19,21 @@ import (
 
 type store struct {
 	tasks        map[string]taskTY.Config
-	pollingTasks []string // tasks which is in polling mode (will not trigger on events)
+	pollingTasks []string                   // tasks which is in polling mode (will not trigger on events)
+	programs     map[string]compiledProgram // compiled expressions, key: task id
 	mutex        sync.Mutex
 }
 
+// compiledProgram of an expression with the declared variables
+type compiledProgram struct {
+	key     string
+	program cel.Program
+}
+
 var tasksStore = store{
 	tasks:        make(map[string]taskTY.Config),
 	pollingTasks: make([]string, 0),
+	programs:     make(map[string]compiledProgram),
 }
 
 // Add a task
@@ -62,12 +75,14 @@ func (s *store) Remove(taskID string) {
 	defer s.mutex.Unlock()
 	scheduleID := scheduleUtils.GetScheduleID(schedulePrefix, taskID, scheduleTypePolling)
 	unscheduleAll(taskID)
+	stopActions(taskID)
 	if utils.ContainsString(s.pollingTasks, scheduleID) {
 		updatedSlice := make([]string, 0)
 		updatedSlice = append(updatedSlice, s.pollingTasks...)
 		s.pollingTasks = updatedSlice
 	}
 	delete(s.tasks, taskID)
+	delete(s.programs, taskID)
 }
 
 // GetByID returns handler by id
@@ -82,7 +97,34 @@ func (s *store) RemoveAll() {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
+	stopActions("")
 	tasksStore.tasks = make(map[string]taskTY.Config)
+	tasksStore.programs = make(map[string]compiledProgram)
+}
+
+// GetProgram returns the compiled expression of a task
+// compiles again, if the expression or the variables changed
+func (s *store) GetProgram(taskID, expressionString string, variableNames []string) (cel.Program, error) {
+	names := append([]string{}, variableNames...)
+	sort.Strings(names)
+	key := fmt.Sprintf("%s|%s", expressionString, strings.Join(names, ","))
+
+	s.mutex.Lock()
+	compiled, found := s.programs[taskID]
+	s.mutex.Unlock()
+	if found && compiled.key == key {
+		return compiled.program, nil
+	}
+
+	program, err := expression.Compile(expressionString, variableNames)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.programs[taskID] = compiledProgram{key: key, program: program}
+	return program, nil
 }
 
 func (s *store) ListIDs() []string {
@@ -104,36 +146,37 @@ func (s *store) filterTasks(evnWrapper *eventWrapper) []taskTY.Config {
 	for id := range s.tasks {
 		task := s.tasks[id]
 
-		// if the task is not event based, do not include
-		if !task.TriggerOnEvent {
-			continue
+		if s.isEventMatching(&task, evnWrapper) {
+			filteredTasks = append(filteredTasks, task)
 		}
+	}
+	return filteredTasks
+}
 
-		// if event filter added and matching do not include
-		eventTypes := task.EventFilter.EventTypes
-		if len(eventTypes) > 0 && !utils.ContainsString(eventTypes, evnWrapper.Event.Type) {
-			continue
-		}
-		entityTypes := task.EventFilter.EntityTypes
-		if len(entityTypes) > 0 && !utils.ContainsString(entityTypes, evnWrapper.Event.EntityType) {
-			continue
-		}
+// isEventMatching returns true, if the task should be triggered on the event
+func (s *store) isEventMatching(task *taskTY.Config, evnWrapper *eventWrapper) bool {
+	// if the task is not event based, do not include
+	if !task.TriggerOnEvent {
+		return false
+	}
 
-		filters := s.getFilters(task.EventFilter.Filters)
-		matching := false
-		zap.L().Debug("filterTasks", zap.Any("filters", filters), zap.Any("event", evnWrapper.Event))
+	// if event filter added and matching do not include
+	eventTypes := task.EventFilter.EventTypes
+	if len(eventTypes) > 0 && !utils.ContainsString(eventTypes, evnWrapper.Event.Type) {
+		return false
+	}
+	entityTypes := task.EventFilter.EntityTypes
+	if len(entityTypes) > 0 && !utils.ContainsString(entityTypes, evnWrapper.Event.EntityType) {
+		return false
+	}
 
-		if len(filters) == 0 {
-			matching = true
-		} else {
-			zap.L().Debug("filterTasks", zap.Any("filters", filters), zap.Any("event", evnWrapper.Event))
-			matching = filterUtils.IsMatching(evnWrapper.Event.Entity, filters)
-		}
-		if matching {
-			filteredTasks = append(filteredTasks, task)
-		}
+	filters := s.getFilters(task.EventFilter.Filters)
+	zap.L().Debug("filterTasks", zap.Any("filters", filters), zap.Any("event", evnWrapper.Event))
+
+	if len(filters) == 0 {
+		return true
 	}
-	return filteredTasks
+	return filterUtils.IsMatching(evnWrapper.Event.Entity, filters)
 }
 
 func (s *store) getFilters(filtersMap map[string]string) []storageTY.Filter {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	secretAPI "github.com/mycontroller-org/server/v2/pkg/api/secret"
 	commonStore "github.com/mycontroller-org/server/v2/pkg/store"
 	types "github.com/mycontroller-org/server/v2/pkg/types"
 	vaTY "github.com/mycontroller-org/server/v2/pkg/types/virtual_assistant"
@@ -24,6 +25,12 @@ func StartAssistant(cfg *vaTY.Config) error {
 		return err
 	}
 
+	// resolve the secret references
+	err = secretAPI.ResolveReferences(cfg)
+	if err != nil {
+		return err
+	}
+
 	if vaService.Get(cfg.ID) != nil {
 		zap.L().Info("no action needed. virtual assistant service is in running state.", zap.String("id", cfg.ID))
 		return nil
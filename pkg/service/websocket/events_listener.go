@@ -8,6 +8,8 @@ import (
 	"github.com/mycontroller-org/server/v2/pkg/service/mcbus"
 	busTY "github.com/mycontroller-org/server/v2/pkg/types/bus"
 	eventTY "github.com/mycontroller-org/server/v2/pkg/types/bus/event"
+	"github.com/mycontroller-org/server/v2/pkg/types/cmap"
+	roleTY "github.com/mycontroller-org/server/v2/pkg/types/role"
 	wsTY "github.com/mycontroller-org/server/v2/pkg/types/websocket"
 	queueUtils "github.com/mycontroller-org/server/v2/pkg/utils/queue"
 	"go.uber.org/zap"
@@ -88,7 +90,10 @@ func processEvent(item interface{}) {
 
 	wsClients := clientStore.getClients()
 	for index := range wsClients {
-		client := wsClients[index]
+		if !isVisible(wsClients[index].access, event) {
+			continue
+		}
+		client := wsClients[index].conn
 
 		// write with write timeout
 		err := client.SetWriteDeadline(time.Now().Add(defaultWriteTimeout))
@@ -104,3 +109,22 @@ func processEvent(item interface{}) {
 		}
 	}
 }
+
+// isVisible verifies the read permission of the client on the event entity
+// label restricted clients receives only the entities with the allowed labels
+func isVisible(access *roleTY.Access, event *eventTY.Event) bool {
+	allowed, selectors := access.IsAllowed(event.EntityType, roleTY.VerbRead)
+	if !allowed {
+		return false
+	}
+	if len(selectors) == 0 {
+		return true
+	}
+	entity := struct {
+		Labels cmap.CustomStringMap `json:"labels"`
+	}{}
+	if err := event.LoadEntity(&entity); err != nil {
+		return false
+	}
+	return roleTY.MatchesLabels(selectors, entity.Labels)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gorilla/mux"
 	ws "github.com/gorilla/websocket"
+	types "github.com/mycontroller-org/server/v2/pkg/types"
 	"go.uber.org/zap"
 )
 
@@ -26,6 +27,12 @@ func RegisterWebsocketRoutes(router *mux.Router) {
 // this is simple example websocket
 // yet to implement actual version
 func wsFunc(w http.ResponseWriter, r *http.Request) {
+	mcApiContext, ok := r.Context().Value(types.MC_API_CONTEXT).(*types.McApiContext)
+	if !ok || mcApiContext == nil || mcApiContext.Access == nil {
+		http.Error(w, "permission denied", http.StatusForbidden)
+		return
+	}
+
 	wsCon, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		zap.L().Info("websocket upgrade error", zap.Error(err))
@@ -33,7 +40,7 @@ func wsFunc(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// register the new client
-	clientStore.register(wsCon)
+	clientStore.register(wsCon, mcApiContext.Access)
 
 	// NOTE: for now not serving any request, only sending the events to the listeners(ex: remote browsers)
 	// this loop is used to close the connection immediately on remote side close
@@ -4,25 +4,32 @@ import (
 	"sync"
 
 	ws "github.com/gorilla/websocket"
+	roleTY "github.com/mycontroller-org/server/v2/pkg/types/role"
 	"go.uber.org/zap"
 )
 
+// client of the websocket, events are filtered with the access of the session
+type client struct {
+	conn   *ws.Conn
+	access *roleTY.Access
+}
+
 type store struct {
-	clients map[*ws.Conn]bool
+	clients map[*ws.Conn]*roleTY.Access
 	mutex   sync.RWMutex
 }
 
 var clientStore = store{
-	clients: make(map[*ws.Conn]bool),
+	clients: make(map[*ws.Conn]*roleTY.Access),
 	mutex:   sync.RWMutex{},
 }
 
 // register a websocket client connection
-func (s *store) register(conn *ws.Conn) {
+func (s *store) register(conn *ws.Conn, access *roleTY.Access) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	s.clients[conn] = true
+	s.clients[conn] = access
 	zap.L().Debug("new websocket connection added", zap.String("remoteAddress", conn.RemoteAddr().String()))
 }
 
@@ -41,13 +48,13 @@ func (s *store) unregister(conn *ws.Conn) {
 }
 
 // returns available websocket client connection
-func (s *store) getClients() []*ws.Conn {
+func (s *store) getClients() []client {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
-	wsClients := make([]*ws.Conn, 0)
-	for client := range s.clients {
-		wsClients = append(wsClients, client)
+	wsClients := make([]client, 0)
+	for conn, access := range s.clients {
+		wsClients = append(wsClients, client{conn: conn, access: access})
 	}
 	return wsClients
 }
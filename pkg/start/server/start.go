@@ -41,6 +41,10 @@ func startServices() {
 		zap.L().Fatal("error on init metric database", zap.Error(err))
 	}
 	store.InitMetric(mtgSVC) // load storage database client
+	err = metricSVC.StartSpool(store.CFG.Database.Metric)
+	if err != nil {
+		zap.L().Fatal("error on init metric spool", zap.Error(err))
+	}
 
 	StartupJobs()
 	StartupJobsExtra()
@@ -158,6 +162,7 @@ func closeServices() {
 			zap.L().Error("failed to close storage database")
 		}
 	}
+	metricSVC.CloseSpool()
 	if store.METRIC != nil {
 		err := store.METRIC.Close()
 		if err != nil {
@@ -1,14 +1,19 @@
 package server
 
 import (
+	auditAPI "github.com/mycontroller-org/server/v2/pkg/api/audit"
+	scriptModuleAPI "github.com/mycontroller-org/server/v2/pkg/api/script_module"
 	settingsAPI "github.com/mycontroller-org/server/v2/pkg/api/settings"
+	taskHistoryAPI "github.com/mycontroller-org/server/v2/pkg/api/task_history"
 	userAPI "github.com/mycontroller-org/server/v2/pkg/api/user"
 	systemJobs "github.com/mycontroller-org/server/v2/pkg/service/system_jobs"
 	nodeJobs "github.com/mycontroller-org/server/v2/pkg/service/system_jobs/node_job"
+	roleTY "github.com/mycontroller-org/server/v2/pkg/types/role"
 	settingsTY "github.com/mycontroller-org/server/v2/pkg/types/settings"
 	userTY "github.com/mycontroller-org/server/v2/pkg/types/user"
 	"github.com/mycontroller-org/server/v2/pkg/utils"
 	"github.com/mycontroller-org/server/v2/pkg/utils/hashed"
+	"github.com/mycontroller-org/server/v2/pkg/utils/javascript"
 	"github.com/mycontroller-org/server/v2/pkg/version"
 	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
 	"go.uber.org/zap"
@@ -23,6 +28,8 @@ func StartupJobsExtra() {
 	CreateSettingsData()
 	UpdateInitialUser()
 	UpdateGeoLocation()
+	LoadMetricPolicies()
+	LoadScriptRuntime()
 	systemJobs.ReloadSystemJobs()
 }
 
@@ -47,11 +54,19 @@ func UpdateInitialUser() {
 			Password: hashedPassword,
 			FullName: "Admin User",
 			Email:    "admin@example.com",
+			Roles:    []string{roleTY.RoleAdmin},
 		}
 		err = userAPI.Save(adminUser)
 		if err != nil {
 			zap.L().Error("failed to create default admin user", zap.Error(err))
 		}
+		return
+	}
+
+	// update roles to the existing users
+	err = userAPI.UpdateMissingRoles()
+	if err != nil {
+		zap.L().Error("failed to update roles to the existing users", zap.Error(err))
 	}
 }
 
@@ -72,6 +87,8 @@ func CreateSettingsData() {
 			ExecutionInterval: nodeJobs.DefaultExecutionInterval,
 			InactiveDuration:  nodeJobs.DefaultInactiveDuration,
 		},
+		AuditLog:    settingsTY.AuditLog{Retention: auditAPI.DefaultRetention},
+		TaskHistory: settingsTY.TaskHistory{Retention: taskHistoryAPI.DefaultRetention},
 	}
 	settings := &settingsTY.Settings{ID: settingsTY.KeySystemSettings}
 	settings.Spec = utils.StructToMap(systemSettings)
@@ -125,3 +142,20 @@ func UpdateGeoLocation() {
 		zap.L().Error("error on updating geo location", zap.Error(err))
 	}
 }
+
+// LoadMetricPolicies applies the metric retention policies on the metric database
+func LoadMetricPolicies() {
+	err := settingsAPI.LoadMetricPolicies()
+	if err != nil {
+		zap.L().Error("error on loading metric retention policies", zap.Error(err))
+	}
+}
+
+// LoadScriptRuntime applies the javascript runtime limits and enables the script modules
+func LoadScriptRuntime() {
+	javascript.SetModuleLoader(scriptModuleAPI.GetSource)
+	err := settingsAPI.LoadScriptRuntime()
+	if err != nil {
+		zap.L().Error("error on loading javascript runtime settings", zap.Error(err))
+	}
+}
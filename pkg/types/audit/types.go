@@ -0,0 +1,40 @@
+package audit
+
+import (
+	"time"
+)
+
+// operations recorded on the audit log
+const (
+	OperationCreate  = "create"
+	OperationUpdate  = "update"
+	OperationDelete  = "delete"
+	OperationExecute = "execute"
+)
+
+// AuditLog holds a change or an action triggered by an user or a service token
+type AuditLog struct {
+	ID             string      `json:"id" yaml:"id"`
+	Timestamp      time.Time   `json:"timestamp" yaml:"timestamp"`
+	UserID         string      `json:"userId" yaml:"userId"`
+	Username       string      `json:"username" yaml:"username"`
+	ServiceTokenID string      `json:"serviceTokenId" yaml:"serviceTokenId"`
+	ClientIP       string      `json:"clientIp" yaml:"clientIp"`
+	Method         string      `json:"method" yaml:"method"`
+	Path           string      `json:"path" yaml:"path"`
+	Resource       string      `json:"resource" yaml:"resource"`
+	Operation      string      `json:"operation" yaml:"operation"`
+	EntityIDs      []string    `json:"entityIds" yaml:"entityIds"`
+	StatusCode     int         `json:"statusCode" yaml:"statusCode"`
+	Error          string      `json:"error" yaml:"error"`
+	Changes        []Change    `json:"changes" yaml:"changes"`
+	Payload        interface{} `json:"payload" yaml:"payload"` // request payload of the actions
+}
+
+// Change of an entity field
+type Change struct {
+	EntityID string      `json:"entityId" yaml:"entityId"`
+	Path     string      `json:"path" yaml:"path"`
+	Old      interface{} `json:"old" yaml:"old"`
+	New      interface{} `json:"new" yaml:"new"`
+}
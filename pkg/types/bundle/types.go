@@ -0,0 +1,50 @@
+package bundle
+
+import (
+	dataRepositoryTY "github.com/mycontroller-org/server/v2/pkg/types/data_repository"
+	fwdPayloadTY "github.com/mycontroller-org/server/v2/pkg/types/forward_payload"
+	scheduleTY "github.com/mycontroller-org/server/v2/pkg/types/schedule"
+	taskTY "github.com/mycontroller-org/server/v2/pkg/types/task"
+	handlerTY "github.com/mycontroller-org/server/v2/plugin/handler/types"
+)
+
+// supported bundle versions
+const (
+	APIVersionV1 = "v1"
+)
+
+// change actions
+const (
+	ActionCreate    = "create"
+	ActionUpdate    = "update"
+	ActionUnchanged = "unchanged"
+)
+
+// Bundle of automation entities, applied in a single request
+type Bundle struct {
+	APIVersion       string                    `json:"apiVersion" yaml:"apiVersion"`
+	Handlers         []handlerTY.Config        `json:"handlers" yaml:"handlers"`
+	DataRepositories []dataRepositoryTY.Config `json:"dataRepositories" yaml:"dataRepositories"`
+	ForwardPayloads  []fwdPayloadTY.Config     `json:"forwardPayloads" yaml:"forwardPayloads"`
+	Schedules        []scheduleTY.Config       `json:"schedules" yaml:"schedules"`
+	Tasks            []taskTY.Config           `json:"tasks" yaml:"tasks"`
+}
+
+// ApplyResult of a bundle, changes are not applied on dry run or on validation errors
+// on a save error, the changes saved before the error are marked as applied
+type ApplyResult struct {
+	DryRun   bool     `json:"dryRun" yaml:"dryRun"`
+	Applied  bool     `json:"applied" yaml:"applied"`
+	Changes  []Change `json:"changes" yaml:"changes"`
+	Errors   []string `json:"errors" yaml:"errors"`
+	Warnings []string `json:"warnings" yaml:"warnings"`
+}
+
+// Change of an entity
+type Change struct {
+	EntityType string   `json:"entityType" yaml:"entityType"`
+	ID         string   `json:"id" yaml:"id"`
+	Action     string   `json:"action" yaml:"action"`
+	Applied    bool     `json:"applied" yaml:"applied"`
+	Diff       []string `json:"diff" yaml:"diff"` // changed key paths, ex: "enabled: false => true"
+}
@@ -35,6 +35,10 @@ type WebConfig struct {
 	Http             HttpConfig      `yaml:"http"`
 	HttpsSSL         HttpsSSLConfig  `yaml:"https_ssl"`
 	HttpsACME        HttpsACMEConfig `yaml:"https_acme"`
+	OIDC             []OIDCConfig    `yaml:"oidc"`
+	TrustedProxies   []string        `yaml:"trusted_proxies"` // ip or cidr of the reverse proxies
+	LoginProtection  LoginProtection `yaml:"login_protection"`
+	RateLimit        RateLimitConfig `yaml:"rate_limit"`
 }
 
 // AnalyticsConfig input
@@ -51,10 +55,34 @@ type HttpConfig struct {
 
 // HttpsSSLConfig struct
 type HttpsSSLConfig struct {
-	Enabled     bool   `yaml:"enabled"`
-	BindAddress string `yaml:"bind_address"`
-	Port        uint   `yaml:"port"`
-	CertDir     string `yaml:"cert_dir"`
+	Enabled     bool             `yaml:"enabled"`
+	BindAddress string           `yaml:"bind_address"`
+	Port        uint             `yaml:"port"`
+	CertDir     string           `yaml:"cert_dir"`
+	ClientAuth  ClientAuthConfig `yaml:"client_auth"`
+}
+
+// ClientAuth modes
+const (
+	ClientAuthNone     = "none"
+	ClientAuthOptional = "optional" // verified, if a client certificate supplied
+	ClientAuthRequired = "required"
+)
+
+// ClientAuthConfig client certificate authentication on the https listener
+type ClientAuthConfig struct {
+	Mode         string              `yaml:"mode"`    // none, optional, required
+	CAFile       string              `yaml:"ca_file"` // certificate authorities to verify the client certificates
+	Certificates []ClientCertificate `yaml:"certificates"`
+}
+
+// ClientCertificate maps a client certificate to a user or a service token
+// certificate matched with the sha256 fingerprint or with the common name
+type ClientCertificate struct {
+	Fingerprint    string `yaml:"fingerprint"`
+	CommonName     string `yaml:"common_name"`
+	Username       string `yaml:"username"`
+	ServiceTokenID string `yaml:"service_token_id"` // id part of the service token
 }
 
 // HttpsACMEConfig struct
@@ -68,6 +96,49 @@ type HttpsACMEConfig struct {
 	Domains       []string `yaml:"domains"`
 }
 
+// OIDCConfig external OpenID Connect identity provider
+type OIDCConfig struct {
+	ID            string            `yaml:"id"`
+	Name          string            `yaml:"name"` // display name on the login page
+	Disabled      bool              `yaml:"disabled"`
+	IssuerURL     string            `yaml:"issuer_url"`
+	ClientID      string            `yaml:"client_id"`
+	ClientSecret  string            `yaml:"client_secret"`
+	RedirectURL   string            `yaml:"redirect_url"` // https://<server>/api/oidc/callback/<id>
+	Scopes        []string          `yaml:"scopes"`
+	UsernameClaim string            `yaml:"username_claim"`
+	GroupsClaim   string            `yaml:"groups_claim"`
+	RoleMapping   map[string]string `yaml:"role_mapping"`  // group to role
+	DefaultRoles  []string          `yaml:"default_roles"` // assigned, if none of the groups mapped
+	AutoProvision bool              `yaml:"auto_provision"`
+	ExpiresIn     string            `yaml:"expires_in"`
+	Insecure      bool              `yaml:"insecure"`
+}
+
+// LoginProtection locks the login temporarily on the repeated failures
+type LoginProtection struct {
+	Disabled         bool   `yaml:"disabled"`
+	MaxFailures      int    `yaml:"max_failures"`        // per username
+	MaxFailuresPerIP int    `yaml:"max_failures_per_ip"` // per client ip
+	FailureWindow    string `yaml:"failure_window"`
+	LockoutDuration  string `yaml:"lockout_duration"`
+}
+
+// RateLimitConfig limits the api requests per service token, user or client ip
+type RateLimitConfig struct {
+	Enabled       bool      `yaml:"enabled"`
+	ClientIP      RateLimit `yaml:"client_ip"`      // all the api per client ip, applied before the authentication
+	Default       RateLimit `yaml:"default"`        // all the api, except the following
+	Action        RateLimit `yaml:"action"`         // /api/action
+	GatewayPlugin RateLimit `yaml:"gateway_plugin"` // /api/plugin/gateway
+}
+
+// RateLimit allowed requests on the interval, requests 0 means unlimited
+type RateLimit struct {
+	Requests int    `yaml:"requests"`
+	Interval string `yaml:"interval"`
+}
+
 // Directories for data and logs
 type Directories struct {
 	Data          string `yaml:"data"`
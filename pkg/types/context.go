@@ -1,5 +1,10 @@
 package types
 
+import (
+	"github.com/mycontroller-org/server/v2/pkg/types/cmap"
+	roleTY "github.com/mycontroller-org/server/v2/pkg/types/role"
+)
+
 const (
 	MC_API_CONTEXT ContextKey = "MC_API_CONTEXT"
 )
@@ -8,6 +13,12 @@ type ContextKey string
 
 // struct used in api request
 type McApiContext struct {
-	Tenant string `json:"tenant" yaml:"tenant"`
-	UserID string `json:"userId" yaml:"userId"`
+	Tenant         string                 `json:"tenant" yaml:"tenant"`
+	UserID         string                 `json:"userId" yaml:"userId"`
+	ServiceTokenID string                 `json:"serviceTokenId" yaml:"serviceTokenId"`
+	Access         *roleTY.Access         `json:"access" yaml:"access"`
+	Resource       string                 `json:"resource" yaml:"resource"`             // resource of the request, verified against the access
+	LabelSelectors []cmap.CustomStringMap `json:"labelSelectors" yaml:"labelSelectors"` // restricts the resources of the request
+	TwoFactorEnrol bool                   `json:"twoFactorEnrol" yaml:"twoFactorEnrol"` // allowed only to enroll the two factor authentication
+	SessionID      string                 `json:"sessionId" yaml:"sessionId"`
 }
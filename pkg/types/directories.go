@@ -14,6 +14,7 @@ const (
 
 	DirectoryDataFirmware       = "/firmware"         // location to keep firmware files
 	DirectoryDataStorage        = "/storage"          // location to keep storage database exported files
+	DirectoryDataMetric         = "/metric"           // location to keep embedded metric database files
 	DirectoryDataInternal       = "/internal"         // location to keep system internal files
 	DirectoryLogsGateway        = "/gateway_logs"     // location to keep gateway message logs
 	DirectoryTmpGatewayFirmware = "/gateway/firmware" // location to keep gateway related tmp items
@@ -75,6 +76,11 @@ func GetDataDirectoryStorage() string {
 	return getDirectoryFullPath(dir.Data, DirectoryDataStorage)
 }
 
+// GetDataDirectoryMetric location
+func GetDataDirectoryMetric() string {
+	return getDirectoryFullPath(dir.Data, DirectoryDataMetric)
+}
+
 // GetDirectoryStorage location
 func GetDataDirectoryInternal() string {
 	return getDirectoryFullPath(dir.Data, DirectoryDataInternal)
@@ -18,29 +18,40 @@ const (
 	EntityVirtualDevice    = "virtual_device"    // holds virtual devices
 	EntityVirtualAssistant = "virtual_assistant" // holds virtual assistants
 	EntityServiceToken     = "service_token"     // holds service token
+	EntityRole             = "role"              // holds user roles and permissions
+	EntityAuditLog         = "audit_log"         // holds configuration changes and actions
+	EntityTaskHistory      = "task_history"      // holds executions of tasks and schedules
+	EntitySession          = "session"           // holds issued login sessions
+	EntitySecret           = "secret"            // holds encrypted sensitive values
+	EntityScriptModule     = "script_module"     // holds reusable javascript modules
+	EntityScene            = "scene"             // holds captured field values, restored together
 )
 
 // Entity field keys
 const (
-	KeyID           = "ID"
-	KeyGatewayID    = "GatewayID"
-	KeyNodeID       = "NodeID"
-	KeySourceID     = "SourceID"
-	KeyFieldID      = "FieldID"
-	KeyFieldName    = "FieldName"
-	KeyUsername     = "Username"
-	KeyUserID       = "UserID"
-	KeyTokenID      = "Token.ID"
-	KeyEmail        = "Email"
-	KeyHandlerType  = "Type"
-	KeyHandlerName  = "Name"
-	KeyEnabled      = "Enabled"
-	KeyDisabled     = "Disabled"
-	KeyScheduleType = "Type"
-	KeyType         = "Type"
-	KeySrcFieldID   = "SrcFieldID"
-	KeyName         = "Name"
-	KeyLocation     = "Location"
+	KeyID             = "ID"
+	KeyGatewayID      = "GatewayID"
+	KeyNodeID         = "NodeID"
+	KeySourceID       = "SourceID"
+	KeyFieldID        = "FieldID"
+	KeyFieldName      = "FieldName"
+	KeyUsername       = "Username"
+	KeyUserID         = "UserID"
+	KeyTokenID        = "Token.ID"
+	KeyEmail          = "Email"
+	KeyProvider       = "Provider"
+	KeyExternalID     = "ExternalID"
+	KeyServiceTokenID = "ServiceTokenID"
+	KeyHandlerType    = "Type"
+	KeyHandlerName    = "Name"
+	KeyEnabled        = "Enabled"
+	KeyDisabled       = "Disabled"
+	KeyScheduleType   = "Type"
+	KeyType           = "Type"
+	KeySrcFieldID     = "SrcFieldID"
+	KeyName           = "Name"
+	KeyLocation       = "Location"
+	KeyTimestamp      = "Timestamp"
 )
 
 // Field names used in entities
@@ -16,6 +16,8 @@ const (
 	TypeResourceAction   = "resource_action"
 	TypeSystemJobs       = "system_jobs"
 	TypeVirtualAssistant = "virtual_assistant"
+	TypeTaskHistory      = "task_history"
+	TypeField            = "field"
 )
 
 // Command details
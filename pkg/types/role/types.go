@@ -0,0 +1,230 @@
+package role
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mycontroller-org/server/v2/pkg/types/cmap"
+	storageTY "github.com/mycontroller-org/server/v2/plugin/database/storage/types"
+)
+
+// verbs
+const (
+	VerbAll     = "*"
+	VerbRead    = "read"    // get and list
+	VerbUpdate  = "update"  // create and update
+	VerbDelete  = "delete"  // delete
+	VerbExecute = "execute" // actions, enable, disable, reload, backup, etc.,
+)
+
+// resources, other than the entities
+const (
+	ResourceAll         = "*"
+	ResourceMetric      = "metric"
+	ResourceAction      = "action"
+	ResourceQuickID     = "quick_id"
+	ResourceBackup      = "backup"
+	ResourceBundle      = "bundle"
+	ResourceWebsocket   = "websocket"
+	ResourceSecureShare = "secure_share"
+)
+
+// built-in roles
+const (
+	RoleAdmin    = "admin"
+	RoleOperator = "operator"
+	RoleViewer   = "viewer"
+)
+
+// resources allowed for the viewer and operator roles
+var operationalResources = []string{
+	"gateway", "node", "source", "field", "firmware", "dashboard", "forward_payload",
+	"handler", "task", "schedule", "data_repository", "virtual_device", "virtual_assistant",
+	"script_module", "scene",
+	ResourceMetric, ResourceQuickID, ResourceWebsocket, ResourceSecureShare,
+}
+
+// BuiltInRoles available by default, can not be modified
+var BuiltInRoles = map[string]Role{
+	RoleAdmin: {
+		ID:          RoleAdmin,
+		Description: "full access",
+		Permissions: []Permission{{Resources: []string{ResourceAll}, Verbs: []string{VerbAll}}},
+	},
+	RoleOperator: {
+		ID:          RoleOperator,
+		Description: "view resources and execute actions",
+		Permissions: []Permission{
+			{Resources: operationalResources, Verbs: []string{VerbRead, VerbExecute}},
+			{Resources: []string{ResourceAction}, Verbs: []string{VerbExecute}},
+		},
+	},
+	RoleViewer: {
+		ID:          RoleViewer,
+		Description: "view resources",
+		Permissions: []Permission{{Resources: operationalResources, Verbs: []string{VerbRead}}},
+	},
+}
+
+// Role holds the permissions
+type Role struct {
+	ID          string               `json:"id" yaml:"id"`
+	Description string               `json:"description" yaml:"description"`
+	Permissions []Permission         `json:"permissions" yaml:"permissions"`
+	Labels      cmap.CustomStringMap `json:"labels" yaml:"labels"`
+	ModifiedOn  time.Time            `json:"modifiedOn" yaml:"modifiedOn"`
+}
+
+// Permission allows the verbs on the resources
+// labels restricts read and update to the resources with all these labels
+type Permission struct {
+	Resources []string             `json:"resources" yaml:"resources"`
+	Verbs     []string             `json:"verbs" yaml:"verbs"`
+	Labels    cmap.CustomStringMap `json:"labels" yaml:"labels"`
+}
+
+// Validate verifies the role
+func (r *Role) Validate() error {
+	if r.ID == "" {
+		return errors.New("role id can not be empty")
+	}
+	if _, found := BuiltInRoles[r.ID]; found {
+		return fmt.Errorf("built-in role '%s' can not be modified", r.ID)
+	}
+	for _, permission := range r.Permissions {
+		if len(permission.Resources) == 0 || len(permission.Verbs) == 0 {
+			return fmt.Errorf("resources and verbs required on the permissions, role:%s", r.ID)
+		}
+		if len(permission.Labels) > 0 {
+			for _, verb := range permission.Verbs {
+				if verb != VerbRead && verb != VerbUpdate {
+					return fmt.Errorf("labels supported only with '%s' and '%s' verbs, role:%s", VerbRead, VerbUpdate, r.ID)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Allows returns true, if the permission allows the verb on the resource
+func (p *Permission) Allows(resource, verb string) bool {
+	return contains(p.Resources, ResourceAll, resource) && contains(p.Verbs, VerbAll, verb)
+}
+
+func contains(items []string, wildcard, value string) bool {
+	for _, item := range items {
+		if item == wildcard || item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Access holds the resolved permissions of a request
+type Access struct {
+	Permissions []Permission `json:"permissions"`
+	Scope       []Permission `json:"scope"`    // service token scope
+	HasScope    bool         `json:"hasScope"` // true, if the request made with a scoped service token
+}
+
+// IsAllowed returns the allowed status and the label selectors
+// empty label selectors, if there is no label restriction
+func (a *Access) IsAllowed(resource, verb string) (bool, []cmap.CustomStringMap) {
+	allowed, labels := isAllowed(a.Permissions, resource, verb)
+	if !allowed || !a.HasScope {
+		return allowed, labels
+	}
+	scopeAllowed, scopeLabels := isAllowed(a.Scope, resource, verb)
+	if !scopeAllowed {
+		return false, nil
+	}
+	switch {
+	case len(labels) == 0:
+		return true, scopeLabels
+	case len(scopeLabels) == 0:
+		return true, labels
+	default:
+		// both restricted, a resource should satisfy both, merge the selectors
+		merged := make([]cmap.CustomStringMap, 0)
+		for _, userLabels := range labels {
+			for _, tokenLabels := range scopeLabels {
+				item := userLabels.Clone()
+				item.CopyFrom(tokenLabels)
+				merged = append(merged, item)
+			}
+		}
+		return true, merged
+	}
+}
+
+func isAllowed(permissions []Permission, resource, verb string) (bool, []cmap.CustomStringMap) {
+	allowed := false
+	labels := make([]cmap.CustomStringMap, 0)
+	for index := range permissions {
+		permission := &permissions[index]
+		if !permission.Allows(resource, verb) {
+			continue
+		}
+		// a permission without labels, gives full access
+		if len(permission.Labels) == 0 {
+			return true, nil
+		}
+		allowed = true
+		labels = append(labels, permission.Labels)
+	}
+	return allowed, labels
+}
+
+// MatchesLabels returns true, if any of the selectors matches the labels
+func MatchesLabels(selectors []cmap.CustomStringMap, labels cmap.CustomStringMap) bool {
+	if len(selectors) == 0 {
+		return true
+	}
+	for _, selector := range selectors {
+		matched := true
+		for key, value := range selector {
+			if labels.Get(key) != value {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// LabelSelectorFilters returns the storage filters of the label selectors
+// multiple selectors supported only on a single label key
+func LabelSelectorFilters(selectors []cmap.CustomStringMap) ([]storageTY.Filter, error) {
+	filters := make([]storageTY.Filter, 0)
+	if len(selectors) == 0 {
+		return filters, nil
+	}
+
+	if len(selectors) == 1 {
+		for key, value := range selectors[0] {
+			filters = append(filters, storageTY.Filter{Key: fmt.Sprintf("labels.%s", key), Operator: storageTY.OperatorEqual, Value: value})
+		}
+		return filters, nil
+	}
+
+	labelKey := ""
+	values := make([]string, 0)
+	for _, selector := range selectors {
+		if len(selector) != 1 {
+			return nil, errors.New("multiple label selectors supported only with a single label key")
+		}
+		for key, value := range selector {
+			if labelKey != "" && labelKey != key {
+				return nil, errors.New("multiple label selectors supported only with a single label key")
+			}
+			labelKey = key
+			values = append(values, value)
+		}
+	}
+	filters = append(filters, storageTY.Filter{Key: fmt.Sprintf("labels.%s", labelKey), Operator: storageTY.OperatorIn, Value: values})
+	return filters, nil
+}
@@ -0,0 +1,32 @@
+package scene
+
+import (
+	"time"
+
+	"github.com/mycontroller-org/server/v2/pkg/types/cmap"
+)
+
+// scene actions, executed via action api, virtual assistants and resource handlers
+const (
+	ActionSnapshot = "snapshot"
+	ActionRestore  = "restore"
+)
+
+// Config of a scene, captures the current values of the fields and restores them on demand
+// fields selected by quick ids and by labels
+type Config struct {
+	ID          string               `json:"id" yaml:"id"`
+	Description string               `json:"description" yaml:"description"`
+	Labels      cmap.CustomStringMap `json:"labels" yaml:"labels"`
+	Fields      []string             `json:"fields" yaml:"fields"`           // field quick ids, ex: "field:gw1.node1.src1.V_STATUS"
+	FieldLabels cmap.CustomStringMap `json:"fieldLabels" yaml:"fieldLabels"` // fields matching all the labels
+	Snapshot    []FieldValue         `json:"snapshot" yaml:"snapshot"`       // captured values, restored on activation
+	SnapshotOn  time.Time            `json:"snapshotOn" yaml:"snapshotOn"`
+	ModifiedOn  time.Time            `json:"modifiedOn" yaml:"modifiedOn"`
+}
+
+// FieldValue captured on a snapshot
+type FieldValue struct {
+	QuickID string `json:"quickId" yaml:"quickId"`
+	Value   string `json:"value" yaml:"value"`
+}
@@ -1,10 +1,13 @@
 package schedule
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/mitchellh/mapstructure"
 	"github.com/mycontroller-org/server/v2/pkg/types/cmap"
 	dateTimeTY "github.com/mycontroller-org/server/v2/pkg/types/cusom_datetime"
+	"github.com/robfig/cron/v3"
 )
 
 // cron types
@@ -115,3 +118,57 @@ type SpecSimple struct {
 	Time        string
 	Offset      string
 }
+
+// cron parser, supports seconds as the scheduler
+var cronParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Validate verifies the spec of the schedule type
+func (c *Config) Validate() error {
+	switch c.Type {
+	case TypeRepeat:
+		spec := &SpecRepeat{}
+		if err := mapstructure.Decode(c.Spec, spec); err != nil {
+			return err
+		}
+		if _, err := time.ParseDuration(spec.Interval); err != nil {
+			return fmt.Errorf("invalid interval '%s'", spec.Interval)
+		}
+
+	case TypeCron:
+		spec := &SpecCron{}
+		if err := mapstructure.Decode(c.Spec, spec); err != nil {
+			return err
+		}
+		if _, err := cronParser.Parse(spec.CronExpression); err != nil {
+			return fmt.Errorf("invalid cron expression '%s', error:%s", spec.CronExpression, err.Error())
+		}
+
+	case TypeSimple, TypeSunrise, TypeSunset:
+		spec := &SpecSimple{}
+		if err := mapstructure.Decode(c.Spec, spec); err != nil {
+			return err
+		}
+		switch spec.Frequency {
+		case FrequencyDaily, FrequencyWeekly, FrequencyMonthly:
+		case FrequencyOnDate:
+			if _, err := time.Parse(dateTimeTY.CustomDateFormat, spec.Date); err != nil {
+				return fmt.Errorf("invalid date '%s'", spec.Date)
+			}
+		default:
+			return fmt.Errorf("invalid frequency '%s'", spec.Frequency)
+		}
+		if c.Type == TypeSimple {
+			if _, err := time.Parse("15:04:05", spec.Time); err != nil {
+				if _, err := time.Parse("15:04", spec.Time); err != nil {
+					return fmt.Errorf("invalid time '%s'", spec.Time)
+				}
+			}
+		} else if _, err := time.ParseDuration(spec.Offset); err != nil {
+			return fmt.Errorf("invalid offset '%s'", spec.Offset)
+		}
+
+	default:
+		return fmt.Errorf("invalid schedule type '%s'", c.Type)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package scriptmodule
+
+import (
+	"time"
+
+	"github.com/mycontroller-org/server/v2/pkg/types/cmap"
+)
+
+// Config of a reusable javascript module, loaded on the scripts via require("id")
+type Config struct {
+	ID          string               `json:"id" yaml:"id"`
+	Description string               `json:"description" yaml:"description"`
+	Labels      cmap.CustomStringMap `json:"labels" yaml:"labels"`
+	Script      string               `json:"script" yaml:"script"` // CommonJS module, exports on "module.exports"
+	ModifiedOn  time.Time            `json:"modifiedOn" yaml:"modifiedOn"`
+}
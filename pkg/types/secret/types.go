@@ -0,0 +1,35 @@
+package secret
+
+import (
+	"time"
+
+	json "github.com/mycontroller-org/server/v2/pkg/json"
+	"github.com/mycontroller-org/server/v2/pkg/types/cmap"
+)
+
+const (
+	RedactedValue = "********"
+)
+
+// Secret keeps a sensitive value encrypted with the server secret
+// referenced from the configurations as "${secret:<id>}"
+type Secret struct {
+	ID          string               `json:"id" yaml:"id"`
+	Description string               `json:"description" yaml:"description"`
+	Labels      cmap.CustomStringMap `json:"labels" yaml:"labels"`
+	Value       string               `json:"value" yaml:"value"`
+	ModifiedOn  time.Time            `json:"modifiedOn" yaml:"modifiedOn"`
+}
+
+// MarshalJSON implementation, value never leaves the server
+func (s *Secret) MarshalJSON() ([]byte, error) {
+	type secret Secret // prevent recursion
+	x := secret(*s)
+	if x.Value != "" {
+		x.Value = RedactedValue
+	}
+	return json.Marshal(x)
+}
+
+// SecretWithValue used to keep the encrypted value on json export
+type SecretWithValue Secret
@@ -19,9 +19,17 @@ type ServiceToken struct {
 	NeverExpire bool                  `json:"neverExpire" yaml:"neverExpire"`
 	ExpiresOn   dateTimeTY.CustomDate `json:"expiresOn" yaml:"expiresOn"`
 	Labels      cmap.CustomStringMap  `json:"labels" yaml:"labels"`
+	Roles       []string              `json:"roles" yaml:"roles"` // reduces the scope of the token, empty: permissions of the user
+	Quota       Quota                 `json:"quota" yaml:"quota"` // limits the api requests of the token, in addition to the server limits
 	CreatedOn   time.Time             `json:"createdOn" yaml:"createdOn"`
 }
 
+// Quota allowed api requests on the interval, requests 0 means unlimited
+type Quota struct {
+	Requests int    `json:"requests" yaml:"requests"`
+	Interval string `json:"interval" yaml:"interval"`
+}
+
 type CreateTokenResponse struct {
 	ID    string `json:"id" yaml:"id"`
 	Token string `json:"token" yaml:"token"`
@@ -0,0 +1,15 @@
+package session
+
+import "time"
+
+// Session of an issued JWT token
+// deleting a session revokes the token
+type Session struct {
+	ID             string    `json:"id" yaml:"id"`
+	UserID         string    `json:"userId" yaml:"userId"`
+	ServiceTokenID string    `json:"serviceTokenId" yaml:"serviceTokenId"`
+	ClientIP       string    `json:"clientIp" yaml:"clientIp"`
+	UserAgent      string    `json:"userAgent" yaml:"userAgent"`
+	CreatedOn      time.Time `json:"createdOn" yaml:"createdOn"`
+	ExpiresOn      time.Time `json:"expiresOn" yaml:"expiresOn"`
+}
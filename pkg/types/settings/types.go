@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/mycontroller-org/server/v2/pkg/types/cmap"
+	metricTY "github.com/mycontroller-org/server/v2/plugin/database/metric/types"
 )
 
 // key to get a specific settings
@@ -14,6 +15,8 @@ const (
 	KeyVersion               = "version"
 	KeyAnalytics             = "analytics"
 	KeySystemDynamicSecrets  = "system_dynamic_secrets"
+	KeyMetricPolicies        = "metric_policies"
+	KeyScriptRuntime         = "script_runtime"
 )
 
 // Settings struct
@@ -29,6 +32,8 @@ type SystemSettings struct {
 	Login        Login        `json:"login" yaml:"login"`
 	Language     string       `json:"language" yaml:"language"`
 	NodeStateJob NodeStateJob `json:"nodeStateJob" yaml:"nodeStateJob"`
+	AuditLog     AuditLog     `json:"auditLog" yaml:"auditLog"`
+	TaskHistory  TaskHistory  `json:"taskHistory" yaml:"taskHistory"`
 }
 
 // GeoLocation struct
@@ -51,6 +56,16 @@ type NodeStateJob struct {
 	InactiveDuration  string `json:"inactiveDuration" yaml:"inactiveDuration"`
 }
 
+// AuditLog retention of the audit log entries
+type AuditLog struct {
+	Retention string `json:"retention" yaml:"retention"`
+}
+
+// TaskHistory retention of the task and schedule executions
+type TaskHistory struct {
+	Retention string `json:"retention" yaml:"retention"`
+}
+
 // VersionSettings struct
 type VersionSettings struct {
 	Version string `json:"version" yaml:"version"`
@@ -82,3 +97,18 @@ type AnalyticsConfig struct {
 type SystemDynamicSecrets struct {
 	JwtAccessSecret string `json:"jwtAccessSecret" yaml:"jwtAccessSecret"`
 }
+
+// MetricPolicies retention and downsample policies of the metric data
+// policies evaluated in order, first matching policy applied to a field
+type MetricPolicies struct {
+	Policies []metricTY.Policy `json:"policies" yaml:"policies"`
+}
+
+// ScriptRuntime limits and helpers of the javascript runtime
+// memory limit is approximate, the process heap growth sampled while a script runs
+type ScriptRuntime struct {
+	Timeout          string   `json:"timeout" yaml:"timeout"`                   // maximum execution time of a script
+	MaxCallStackSize int      `json:"maxCallStackSize" yaml:"maxCallStackSize"` // maximum nested function calls
+	MaxMemory        string   `json:"maxMemory" yaml:"maxMemory"`               // maximum heap growth while a script runs, ex: 64MiB, default 256MiB
+	AllowedHelpers   []string `json:"allowedHelpers" yaml:"allowedHelpers"`     // mcUtils helpers allowed on scripts, all allowed if empty
+}
@@ -1,8 +1,10 @@
 package task
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/mycontroller-org/server/v2/pkg/types"
 	"github.com/mycontroller-org/server/v2/pkg/types/cmap"
 )
 
@@ -11,6 +13,7 @@ const (
 	EvaluationTypeRule       = "rule"
 	EvaluationTypeJavascript = "javascript"
 	EvaluationTypeWebhook    = "webhook"
+	EvaluationTypeExpression = "expression" // CEL expression, compiled on save
 )
 
 // dampening types
@@ -21,9 +24,39 @@ const (
 	DampeningTypeActiveDuration = "active_duration"
 )
 
+// condition types of the rule
+const (
+	ConditionTypeValue        = "value"         // compares the value of the variable, default type
+	ConditionTypeForDuration  = "for_duration"  // value comparison matched continuously for the duration
+	ConditionTypeChangedCount = "changed_count" // compares the number of value changes within the duration
+	ConditionTypeNoUpdate     = "no_update"     // timestamp on the variable is older than the duration
+	ConditionTypeSunWindow    = "sun_window"    // "day" between sunrise and sunset, "night" otherwise
+)
+
+// operators of the condition groups
+const (
+	GroupOperatorAnd = "and" // all the items matched, default operator
+	GroupOperatorOr  = "or"  // any of the items matched
+	GroupOperatorNot = "not" // none of the items matched
+)
+
+// action types
+const (
+	ActionTypeHandler   = "handler"   // posts the parameters to the handlers and waits for the results
+	ActionTypeDelay     = "delay"     // waits for the delay duration
+	ActionTypeWait      = "wait"      // waits until the condition matched, fails on the timeout
+	ActionTypeCondition = "condition" // executes "then" actions, if the condition matched, otherwise "else" actions
+)
+
+// sun window values
+const (
+	SunWindowDay   = "day"
+	SunWindowNight = "night"
+)
+
 // keys used in script engine
 const (
-	KeyIsTriggered = "isTriggered" // expected value from script or from webhook to trigger
+	KeyIsTriggered = "isTriggered" // expected value from script, expression or from webhook to trigger
 )
 
 // Config struct
@@ -45,6 +78,7 @@ type Config struct {
 	EvaluationConfig  EvaluationConfig     `json:"evaluationConfig" yaml:"evaluationConfig"`
 	HandlerParameters map[string]string    `json:"handlerParameters" yaml:"handlerParameters"`
 	Handlers          []string             `json:"handlers" yaml:"handlers"`
+	Actions           []Action             `json:"actions" yaml:"actions"` // executed in order after the handlers notified
 	ModifiedOn        time.Time            `json:"modifiedOn" yaml:"modifiedOn"`
 	State             *State               `json:"state" yaml:"state"`
 }
@@ -61,12 +95,144 @@ type EvaluationConfig struct {
 	Rule       Rule        `json:"rule" yaml:"rule"`
 	Javascript string      `json:"javascript" yaml:"javascript"`
 	Webhook    WebhookData `json:"webhook" yaml:"webhook"`
+	Expression string      `json:"expression" yaml:"expression"`
 }
 
 // Rule struct
+// conditions and groups are combined with "and", if matchAll enabled, otherwise with "or"
 type Rule struct {
-	MatchAll   bool         `json:"matchAll" yaml:"matchAll"`
-	Conditions []Conditions `json:"conditions" yaml:"conditions"`
+	MatchAll   bool             `json:"matchAll" yaml:"matchAll"`
+	Conditions []Conditions     `json:"conditions" yaml:"conditions"`
+	Groups     []ConditionGroup `json:"groups" yaml:"groups"`
+}
+
+// Action of a task, executed in the order
+type Action struct {
+	Type              string            `json:"type" yaml:"type"`
+	Description       string            `json:"description" yaml:"description"`
+	Handlers          []string          `json:"handlers" yaml:"handlers"`                   // handler action
+	Parameters        map[string]string `json:"parameters" yaml:"parameters"`               // handler action, task handler parameters used, if empty
+	Delay             string            `json:"delay" yaml:"delay"`                         // delay action
+	Condition         Rule              `json:"condition" yaml:"condition"`                 // wait and condition actions
+	Timeout           string            `json:"timeout" yaml:"timeout"`                     // wait action and the handler results
+	Then              []Action          `json:"then" yaml:"then"`                           // condition action
+	Else              []Action          `json:"else" yaml:"else"`                           // condition action
+	OnFailure         []string          `json:"onFailure" yaml:"onFailure"`                 // fallback handlers, notified on failure
+	ContinueOnFailure bool              `json:"continueOnFailure" yaml:"continueOnFailure"` // executes the next actions on failure
+}
+
+// Validate verifies the rule and the actions of the task
+func (c *Config) Validate() error {
+	err := c.EvaluationConfig.Rule.Validate()
+	if err != nil {
+		return err
+	}
+	return validateActions(c.Actions, "actions")
+}
+
+// ExpressionVariableNames returns the variables available on the expression
+func (c *Config) ExpressionVariableNames() []string {
+	names := []string{types.KeyTask, types.KeyTaskEvent}
+	for name := range c.Variables {
+		names = append(names, name)
+	}
+	return names
+}
+
+func validateActions(actions []Action, path string) error {
+	for index, action := range actions {
+		actionPath := fmt.Sprintf("%s.%d", path, index)
+		switch action.Type {
+		case ActionTypeHandler:
+			if len(action.Handlers) == 0 {
+				return fmt.Errorf("handlers required on the handler action, %s", actionPath)
+			}
+
+		case ActionTypeDelay:
+			if _, err := time.ParseDuration(action.Delay); err != nil {
+				return fmt.Errorf("invalid delay '%s' on %s", action.Delay, actionPath)
+			}
+
+		case ActionTypeWait:
+			if _, err := time.ParseDuration(action.Timeout); err != nil {
+				return fmt.Errorf("invalid timeout '%s' on %s", action.Timeout, actionPath)
+			}
+
+		case ActionTypeCondition:
+			if err := validateActions(action.Then, actionPath+".then"); err != nil {
+				return err
+			}
+			if err := validateActions(action.Else, actionPath+".else"); err != nil {
+				return err
+			}
+
+		default:
+			return fmt.Errorf("invalid action type '%s' on %s", action.Type, actionPath)
+		}
+
+		if action.Type == ActionTypeWait || action.Type == ActionTypeCondition {
+			group := action.Condition.Group()
+			if err := group.validate(actionPath + ".condition"); err != nil {
+				return err
+			}
+		}
+		if action.Type == ActionTypeHandler && action.Timeout != "" {
+			if _, err := time.ParseDuration(action.Timeout); err != nil {
+				return fmt.Errorf("invalid timeout '%s' on %s", action.Timeout, actionPath)
+			}
+		}
+	}
+	return nil
+}
+
+// ConditionGroup combines the conditions and the nested groups with the operator
+type ConditionGroup struct {
+	Operator   string           `json:"operator" yaml:"operator"`
+	Conditions []Conditions     `json:"conditions" yaml:"conditions"`
+	Groups     []ConditionGroup `json:"groups" yaml:"groups"`
+}
+
+// Group returns the rule as a condition group
+func (r *Rule) Group() ConditionGroup {
+	operator := GroupOperatorOr
+	if r.MatchAll {
+		operator = GroupOperatorAnd
+	}
+	return ConditionGroup{Operator: operator, Conditions: r.Conditions, Groups: r.Groups}
+}
+
+// Validate verifies the condition types and the group operators of the rule
+func (r *Rule) Validate() error {
+	group := r.Group()
+	return group.validate("rule")
+}
+
+func (g *ConditionGroup) validate(path string) error {
+	switch g.Operator {
+	case GroupOperatorAnd, GroupOperatorOr, GroupOperatorNot, "":
+		// valid operators
+	default:
+		return fmt.Errorf("invalid group operator '%s' on %s, supported: %s, %s, %s", g.Operator, path, GroupOperatorAnd, GroupOperatorOr, GroupOperatorNot)
+	}
+	for index, condition := range g.Conditions {
+		switch condition.Type {
+		case ConditionTypeValue, "", ConditionTypeSunWindow:
+			// no additional fields
+		case ConditionTypeForDuration, ConditionTypeChangedCount, ConditionTypeNoUpdate:
+			if condition.Duration == "" {
+				return fmt.Errorf("duration required on the '%s' condition, %s.conditions.%d", condition.Type, path, index)
+			}
+		default:
+			return fmt.Errorf("invalid condition type '%s' on %s.conditions.%d", condition.Type, path, index)
+		}
+	}
+	for index := range g.Groups {
+		err := g.Groups[index].validate(fmt.Sprintf("%s.groups.%d", path, index))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // WebhookData struct
@@ -81,9 +247,11 @@ type WebhookData struct {
 
 // Conditions struct
 type Conditions struct {
+	Type     string      `json:"type" yaml:"type"`
 	Variable string      `json:"variable" yaml:"variable"`
 	Operator string      `json:"operator" yaml:"operator"`
 	Value    interface{} `json:"value" yaml:"value"`
+	Duration string      `json:"duration" yaml:"duration"` // used on the temporal conditions
 }
 
 // DampeningConfig struct
@@ -96,17 +264,58 @@ type DampeningConfig struct {
 
 // State struct
 type State struct {
-	LastEvaluation    time.Time        `json:"lastEvaluation" yaml:"lastEvaluation"`
-	LastSuccess       time.Time        `json:"lastSuccess" yaml:"lastSuccess"`
-	Message           string           `json:"message" yaml:"message"`
-	LastDuration      string           `json:"lastDuration" yaml:"lastDuration"`
-	LastStatus        bool             `json:"lastStatus" yaml:"lastStatus"`
-	ExecutedCount     int64            `json:"executedCount" yaml:"executedCount"`
-	ExecutionsHistory []ExecutionState `json:"executionsHistory" yaml:"executionsHistory"`
-	ActiveSince       time.Time        `json:"activeSince" yaml:"activeSince"`
+	LastEvaluation    time.Time                 `json:"lastEvaluation" yaml:"lastEvaluation"`
+	LastSuccess       time.Time                 `json:"lastSuccess" yaml:"lastSuccess"`
+	Message           string                    `json:"message" yaml:"message"`
+	LastDuration      string                    `json:"lastDuration" yaml:"lastDuration"`
+	LastStatus        bool                      `json:"lastStatus" yaml:"lastStatus"`
+	ExecutedCount     int64                     `json:"executedCount" yaml:"executedCount"`
+	ExecutionsHistory []ExecutionState          `json:"executionsHistory" yaml:"executionsHistory"`
+	ActiveSince       time.Time                 `json:"activeSince" yaml:"activeSince"`
+	Conditions        map[string]ConditionState `json:"conditions" yaml:"conditions"` // state of the temporal conditions, key is the path of the condition
+}
+
+// ConditionState of a temporal condition
+type ConditionState struct {
+	MatchedSince time.Time   `json:"matchedSince" yaml:"matchedSince"`
+	LastValue    interface{} `json:"lastValue" yaml:"lastValue"`
+	Changes      []time.Time `json:"changes" yaml:"changes"`
 }
 
 type ExecutionState struct {
 	Triggered bool      `json:"triggered" yaml:"triggered"`
 	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
 }
+
+// Trace of a task evaluation, returned on the dry run
+type Trace struct {
+	EventMatched       *bool                  `json:"eventMatched" yaml:"eventMatched"` // event filter status, nil if the event not supplied
+	Variables          map[string]interface{} `json:"variables" yaml:"variables"`
+	Conditions         []ConditionTrace       `json:"conditions" yaml:"conditions"`
+	Output             interface{}            `json:"output" yaml:"output"` // response of the javascript or webhook
+	Triggered          bool                   `json:"triggered" yaml:"triggered"`
+	DampeningTriggered bool                   `json:"dampeningTriggered" yaml:"dampeningTriggered"`
+	NotifyHandlers     bool                   `json:"notifyHandlers" yaml:"notifyHandlers"`
+	Handlers           []string               `json:"handlers" yaml:"handlers"`
+	HandlerParameters  map[string]string      `json:"handlerParameters" yaml:"handlerParameters"`
+	Actions            []Action               `json:"actions" yaml:"actions"` // actions will be executed
+	Messages           []string               `json:"messages" yaml:"messages"`
+}
+
+// ConditionTrace of a rule condition
+type ConditionTrace struct {
+	Path          string      `json:"path" yaml:"path"` // location of the condition on the rule, example: groups.0.conditions.1
+	Type          string      `json:"type,omitempty" yaml:"type,omitempty"`
+	Variable      string      `json:"variable" yaml:"variable"`
+	Value         interface{} `json:"value" yaml:"value"`
+	Operator      string      `json:"operator" yaml:"operator"`
+	ExpectedValue interface{} `json:"expectedValue" yaml:"expectedValue"`
+	Matched       bool        `json:"matched" yaml:"matched"`
+	Details       string      `json:"details,omitempty" yaml:"details,omitempty"` // evaluation details of the temporal conditions
+	Error         string      `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// AddMessage records a message on the trace
+func (t *Trace) AddMessage(format string, args ...interface{}) {
+	t.Messages = append(t.Messages, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,72 @@
+package taskhistory
+
+import (
+	"time"
+)
+
+// source types of the execution
+const (
+	SourceTypeTask     = "task"
+	SourceTypeSchedule = "schedule"
+)
+
+// handler and action execution status
+const (
+	HandlerStatusPending = "pending"
+	HandlerStatusSuccess = "success"
+	HandlerStatusError   = "error"
+)
+
+// Entry holds an execution of a task or a schedule
+type Entry struct {
+	ID                 string          `json:"id" yaml:"id"`
+	Timestamp          time.Time       `json:"timestamp" yaml:"timestamp"`
+	SourceType         string          `json:"sourceType" yaml:"sourceType"`
+	SourceID           string          `json:"sourceId" yaml:"sourceId"`
+	Description        string          `json:"description" yaml:"description"`
+	Event              *TriggerEvent   `json:"event,omitempty" yaml:"event,omitempty"`
+	Triggered          bool            `json:"triggered" yaml:"triggered"`
+	DampeningTriggered bool            `json:"dampeningTriggered" yaml:"dampeningTriggered"`
+	NotifiedHandlers   bool            `json:"notifiedHandlers" yaml:"notifiedHandlers"`
+	Duration           string          `json:"duration" yaml:"duration"`
+	Error              string          `json:"error" yaml:"error"`
+	Handlers           []HandlerResult `json:"handlers" yaml:"handlers"`
+	Actions            []ActionResult  `json:"actions" yaml:"actions"`
+}
+
+// TriggerEvent details of the event triggered the execution
+type TriggerEvent struct {
+	Type       string `json:"type" yaml:"type"`
+	EntityType string `json:"entityType" yaml:"entityType"`
+	EntityID   string `json:"entityId" yaml:"entityId"`
+}
+
+// HandlerResult holds the result of a handler notified by the execution
+type HandlerResult struct {
+	HandlerID string    `json:"handlerId" yaml:"handlerId"`
+	Status    string    `json:"status" yaml:"status"`
+	Error     string    `json:"error" yaml:"error"`
+	Duration  string    `json:"duration" yaml:"duration"`
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+}
+
+// ActionResult holds the result of an action executed by the task
+type ActionResult struct {
+	Path      string    `json:"path" yaml:"path"` // location of the action on the task, example: actions.1.then.0
+	Type      string    `json:"type" yaml:"type"`
+	Status    string    `json:"status" yaml:"status"`
+	Message   string    `json:"message" yaml:"message"`
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+}
+
+// NewHandlerResults returns the pending results of the handlers
+func NewHandlerResults(handlers []string) []HandlerResult {
+	results := make([]HandlerResult, 0)
+	for _, handlerID := range handlers {
+		if handlerID == "" {
+			continue
+		}
+		results = append(results, HandlerResult{HandlerID: handlerID, Status: HandlerStatusPending})
+	}
+	return results
+}
@@ -15,6 +15,10 @@ type User struct {
 	Password   string               `json:"password" yaml:"password"` // keep the hashed password, not the actual password
 	FullName   string               `json:"fullName" yaml:"fullName"`
 	Labels     cmap.CustomStringMap `json:"labels" yaml:"labels"`
+	Roles      []string             `json:"roles" yaml:"roles"`
+	TwoFactor  TwoFactor            `json:"twoFactor" yaml:"twoFactor"`
+	Provider   string               `json:"provider" yaml:"provider"`     // external identity provider id, empty for local users
+	ExternalID string               `json:"externalId" yaml:"externalId"` // subject on the external identity provider
 	ModifiedOn time.Time            `json:"modifiedOn" yaml:"modifiedOn"`
 }
 
@@ -23,9 +27,35 @@ func (u *User) MarshalJSON() ([]byte, error) {
 	type user User // prevent recursion
 	x := user(*u)
 	x.Password = ""
+	x.TwoFactor.Secret = ""
+	x.TwoFactor.PendingSecret = ""
+	x.TwoFactor.RecoveryCodesLeft = len(x.TwoFactor.RecoveryCodes)
+	x.TwoFactor.RecoveryCodes = nil
 	return json.Marshal(x)
 }
 
+// TwoFactor holds the time based one time password details
+type TwoFactor struct {
+	Enabled           bool     `json:"enabled" yaml:"enabled"`
+	Required          bool     `json:"required" yaml:"required"`                     // enforced by the administrator
+	Secret            string   `json:"secret,omitempty" yaml:"secret"`               // encrypted secret
+	PendingSecret     string   `json:"pendingSecret,omitempty" yaml:"pendingSecret"` // encrypted secret, waiting for the enrollment confirmation
+	RecoveryCodes     []string `json:"recoveryCodes,omitempty" yaml:"recoveryCodes"` // hashed recovery codes
+	RecoveryCodesLeft int      `json:"recoveryCodesLeft" yaml:"-"`
+	LastUsedStep      int64    `json:"lastUsedStep" yaml:"lastUsedStep"` // prevents the reuse of a code
+}
+
+// TwoFactorEnrollment returned on the enrollment, secret and uri used on the authenticator apps
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret" yaml:"secret"`
+	URI    string `json:"uri" yaml:"uri"`
+}
+
+// TwoFactorRequest used to confirm the enrollment, disable and regenerate the recovery codes
+type TwoFactorRequest struct {
+	Code string `json:"code" yaml:"code"`
+}
+
 // UserWithPassword used to keep the password on json export
 type UserWithPassword User
 
@@ -9,6 +9,8 @@ const (
 	KeyFullName       = "fullname"
 	KeyAuthorized     = "authorized"
 	KeyExpiresAt      = "expires_at"
+	KeyTwoFactorEnrol = "2fa_enroll" // token issued only to enroll the two factor authentication
+	KeySessionID      = "session_id" // token revoked, when the session removed
 
 	EnvJwtAccessSecret = "JWT_ACCESS_SECRET" // environment variable to set secret for JWT token
 
@@ -30,6 +32,7 @@ type UserLogin struct {
 	Username  string `json:"username" yaml:"username"`
 	Password  string `json:"password" yaml:"password"`
 	SvcToken  string `json:"token" yaml:"token"`
+	Code      string `json:"code" yaml:"code"` // two factor authentication code or a recovery code
 	ExpiresIn string `json:"expiresIn" yaml:"expiresIn"`
 }
 
@@ -46,6 +49,8 @@ type JwtTokenResponse struct {
 	FullName string `json:"fullName" yaml:"fullName"`
 	Email    string `json:"email" yaml:"email"`
 	Token    string `json:"token" yaml:"token"`
+
+	TwoFactorEnrollmentRequired bool `json:"twoFactorEnrollmentRequired" yaml:"twoFactorEnrollmentRequired"`
 }
 
 // Response struct
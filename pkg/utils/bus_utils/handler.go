@@ -11,6 +11,18 @@ import (
 
 // PostToHandler send data to handlers
 func PostToHandler(handlers []string, data map[string]string) {
+	PostToHandlerWithExecutionID("", handlers, data)
+}
+
+// PostToHandlerWithExecutionID send data to handlers, handlers report the result against the execution id
+// returns false, if there is no enabled parameter to post
+func PostToHandlerWithExecutionID(executionID string, handlers []string, data map[string]string) bool {
+	return PostToHandlerWithReply(executionID, "", handlers, data)
+}
+
+// PostToHandlerWithReply send data to handlers, handlers post the result on the reply topic
+// returns false, if there is no enabled parameter to post
+func PostToHandlerWithReply(executionID, replyTopic string, handlers []string, data map[string]string) bool {
 	zap.L().Debug("Posting data to handlers", zap.Any("handlers", handlers))
 
 	// remove disabled parameters
@@ -31,7 +43,7 @@ func PostToHandler(handlers []string, data map[string]string) {
 	}
 
 	if len(updateData) == 0 {
-		return
+		return false
 	}
 
 	for _, handlerID := range handlers {
@@ -39,12 +51,15 @@ func PostToHandler(handlers []string, data map[string]string) {
 			continue
 		}
 		msg := &handlerType.MessageWrapper{
-			ID:   handlerID,
-			Data: updateData,
+			ID:          handlerID,
+			ExecutionID: executionID,
+			ReplyTopic:  replyTopic,
+			Data:        updateData,
 		}
 		err := mcbus.Publish(mcbus.FormatTopic(mcbus.TopicPostMessageNotifyHandler), msg)
 		if err != nil {
 			zap.L().Error("error on posting data to handler", zap.Error(err), zap.String("handlerID", handlerID))
 		}
 	}
+	return true
 }
@@ -6,6 +6,7 @@ import (
 	rsTY "github.com/mycontroller-org/server/v2/pkg/types/resource_service"
 	scheduleTY "github.com/mycontroller-org/server/v2/pkg/types/schedule"
 	taskTY "github.com/mycontroller-org/server/v2/pkg/types/task"
+	taskHistoryTY "github.com/mycontroller-org/server/v2/pkg/types/task_history"
 	"go.uber.org/zap"
 )
 
@@ -34,6 +35,11 @@ func SetScheduleState(id string, state scheduleTY.State) {
 	PostToResourceService(id, state, rsTY.TypeScheduler, rsTY.CommandUpdateState, "")
 }
 
+// SetTaskHistoryHandlerResult sends handler result of a task or schedule execution into bus
+func SetTaskHistoryHandlerResult(executionID string, result taskHistoryTY.HandlerResult) {
+	PostToResourceService(executionID, result, rsTY.TypeTaskHistory, rsTY.CommandUpdateState, "")
+}
+
 // DisableSchedule sends id to resource service
 func DisableSchedule(id string) {
 	PostToResourceService(id, id, rsTY.TypeScheduler, rsTY.CommandDisable, "")
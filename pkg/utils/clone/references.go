@@ -0,0 +1,107 @@
+package cloneutil
+
+import (
+	"reflect"
+	"time"
+)
+
+// UpdateStrings replaces all the string values of the source with the returned value of the updateFn
+// source should be a pointer, to update the struct fields
+func UpdateStrings(source interface{}, updateFn func(value string) (string, error)) error {
+	return updateStringsRecursive(reflect.ValueOf(source), updateFn)
+}
+
+func updateStringsRecursive(value reflect.Value, updateFn func(value string) (string, error)) error {
+	switch value.Kind() {
+
+	case reflect.Ptr:
+		originalValue := value.Elem()
+		if !originalValue.IsValid() {
+			return nil
+		}
+		return updateStringsRecursive(originalValue, updateFn)
+
+	case reflect.Interface:
+		originalValue := value.Elem()
+		if !originalValue.IsValid() {
+			return nil
+		}
+		// string inside an interface is not addressable, set it on the interface
+		if originalValue.Kind() == reflect.String {
+			if value.CanSet() {
+				newValue, err := updateFn(originalValue.String())
+				if err != nil {
+					return err
+				}
+				value.Set(reflect.ValueOf(newValue).Convert(originalValue.Type()))
+			}
+			return nil
+		}
+		return updateStringsRecursive(originalValue, updateFn)
+
+	case reflect.Struct:
+		if value.Type() == reflect.TypeOf(time.Time{}) {
+			return nil
+		}
+		for index := 0; index < value.NumField(); index++ {
+			field := value.Field(index)
+			if !field.CanSet() {
+				continue
+			}
+			err := updateStringsRecursive(field, updateFn)
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice:
+		for index := 0; index < value.Len(); index++ {
+			err := updateStringsRecursive(value.Index(index), updateFn)
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		for _, key := range value.MapKeys() {
+			originalValue := value.MapIndex(key)
+			if originalValue.Kind() == reflect.Interface {
+				originalValue = originalValue.Elem()
+				if !originalValue.IsValid() {
+					continue
+				}
+			}
+
+			if originalValue.Kind() == reflect.String {
+				newValue, err := updateFn(originalValue.String())
+				if err != nil {
+					return err
+				}
+				if newValue != originalValue.String() {
+					value.SetMapIndex(key, reflect.ValueOf(newValue).Convert(originalValue.Type()))
+				}
+				continue
+			}
+
+			// map values are not addressable, update a copy and set it back
+			copyValue := reflect.New(originalValue.Type()).Elem()
+			copyValue.Set(originalValue)
+			err := updateStringsRecursive(copyValue, updateFn)
+			if err != nil {
+				return err
+			}
+			value.SetMapIndex(key, copyValue)
+		}
+
+	case reflect.String:
+		if value.CanSet() {
+			newValue, err := updateFn(value.String())
+			if err != nil {
+				return err
+			}
+			value.SetString(newValue)
+		}
+
+	}
+	return nil
+}
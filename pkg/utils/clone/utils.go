@@ -131,17 +131,35 @@ var (
 		"authentication",
 		"jwtaccesssecret",
 		"encryptionkey",
+		"apikey",
+		"api_key",
+		"clientsecret",
+		"client_secret",
 	}
 )
 
 // UpdateSecrets encrypts or decrypts the spacial keys
 // special keys should be in lower case
 func UpdateSecrets(source interface{}, secret, encryptionPrefix string, encrypt bool, specialKeys []string) error {
-	original := reflect.ValueOf(source)
-	return updateSecretRecursive(original, secret, encryptionPrefix, encrypt, specialKeys)
+	updateFn := func(fieldName, value string) (string, error) {
+		return updateStringSecret(fieldName, value, secret, encryptionPrefix, encrypt, specialKeys)
+	}
+	return updateSecretRecursive(reflect.ValueOf(source), updateFn)
+}
+
+// RedactSecrets replaces the values of the special keys with the mask
+// special keys should be in lower case
+func RedactSecrets(source interface{}, mask string, specialKeys []string) error {
+	updateFn := func(fieldName, value string) (string, error) {
+		if value == "" || !utils.ContainsString(specialKeys, strings.ToLower(fieldName)) {
+			return value, nil
+		}
+		return mask, nil
+	}
+	return updateSecretRecursive(reflect.ValueOf(source), updateFn)
 }
 
-func updateSecretRecursive(value reflect.Value, secret, encryptionPrefix string, encrypt bool, specialKeys []string) error {
+func updateSecretRecursive(value reflect.Value, updateFn func(fieldName, value string) (string, error)) error {
 	switch value.Kind() {
 
 	case reflect.Ptr:
@@ -150,7 +168,7 @@ func updateSecretRecursive(value reflect.Value, secret, encryptionPrefix string,
 		if !originalValue.IsValid() {
 			return nil
 		}
-		err := updateSecretRecursive(originalValue, secret, encryptionPrefix, encrypt, specialKeys)
+		err := updateSecretRecursive(originalValue, updateFn)
 		if err != nil {
 			return err
 		}
@@ -160,7 +178,7 @@ func updateSecretRecursive(value reflect.Value, secret, encryptionPrefix string,
 		if !originalValue.IsValid() {
 			return nil
 		}
-		err := updateSecretRecursive(originalValue, secret, encryptionPrefix, encrypt, specialKeys)
+		err := updateSecretRecursive(originalValue, updateFn)
 		if err != nil {
 			return err
 		}
@@ -177,14 +195,14 @@ func updateSecretRecursive(value reflect.Value, secret, encryptionPrefix string,
 				if originalValue.Kind() == reflect.String { // update secret
 					if originalValue.CanAddr() { // set only if it is addressable
 						fieldName := value.Type().Field(index).Name
-						newValue, err := updateStringSecret(fieldName, originalValue.String(), secret, encryptionPrefix, encrypt, specialKeys)
+						newValue, err := updateFn(fieldName, originalValue.String())
 						if err != nil {
 							return err
 						}
 						value.Field(index).SetString(newValue)
 					}
 				} else {
-					err := updateSecretRecursive(originalValue, secret, encryptionPrefix, encrypt, specialKeys)
+					err := updateSecretRecursive(originalValue, updateFn)
 					if err != nil {
 						return err
 					}
@@ -196,7 +214,7 @@ func updateSecretRecursive(value reflect.Value, secret, encryptionPrefix string,
 		for index := 0; index < value.Len(); index++ {
 			originalValue := value.Index(index)
 			if originalValue.Kind() != reflect.String {
-				err := updateSecretRecursive(originalValue, secret, encryptionPrefix, encrypt, specialKeys)
+				err := updateSecretRecursive(originalValue, updateFn)
 				if err != nil {
 					return err
 				}
@@ -216,13 +234,13 @@ func updateSecretRecursive(value reflect.Value, secret, encryptionPrefix string,
 			}
 
 			if originalValue.Kind() == reflect.String { // update secret
-				newValue, err := updateStringSecret(keyString, originalValue.String(), secret, encryptionPrefix, encrypt, specialKeys)
+				newValue, err := updateFn(keyString, originalValue.String())
 				if err != nil {
 					return err
 				}
 				value.SetMapIndex(key, reflect.ValueOf(newValue))
 			} else {
-				err := updateSecretRecursive(originalValue, secret, encryptionPrefix, encrypt, specialKeys)
+				err := updateSecretRecursive(originalValue, updateFn)
 				if err != nil {
 					return err
 				}
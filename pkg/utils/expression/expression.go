@@ -0,0 +1,127 @@
+package expression
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/mycontroller-org/server/v2/pkg/json"
+)
+
+// maximum evaluation cost of an expression, limits the expensive comprehensions
+const costLimit = 1000000
+
+var mapType = reflect.TypeOf(map[string]interface{}{})
+
+// Compile parses and type checks the CEL expression
+// the variables are declared with dynamic type, result should be a bool or a map
+func Compile(expression string, variableNames []string) (cel.Program, error) {
+	if expression == "" {
+		return nil, fmt.Errorf("expression can not be empty")
+	}
+
+	names := append([]string{}, variableNames...)
+	sort.Strings(names)
+	options := []cel.EnvOption{cel.CrossTypeNumericComparisons(true)}
+	for _, name := range names {
+		options = append(options, cel.Variable(name, cel.DynType))
+	}
+	env, err := cel.NewEnv(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("invalid expression, %s", issues.Err().Error())
+	}
+
+	if !isValidOutputType(ast.OutputType()) {
+		return nil, fmt.Errorf("expression should return a bool or a map, returns %s", ast.OutputType())
+	}
+
+	return env.Program(ast, cel.CostLimit(costLimit))
+}
+
+// result type verified on compile, dynamic type verified on the evaluation
+func isValidOutputType(outputType *cel.Type) bool {
+	switch outputType.Kind() {
+	case types.BoolKind, types.DynKind:
+		return true
+
+	case types.MapKind:
+		keyKind := outputType.Parameters()[0].Kind()
+		return keyKind == types.StringKind || keyKind == types.DynKind
+
+	default:
+		return false
+	}
+}
+
+// Evaluate compiles and evaluates the expression with the variables
+// all the declared variables should be available, missing variables supplied as null
+func Evaluate(expression string, variableNames []string, variables map[string]interface{}) (interface{}, error) {
+	program, err := Compile(expression, variableNames)
+	if err != nil {
+		return nil, err
+	}
+	return EvaluateProgram(program, variableNames, variables)
+}
+
+// EvaluateProgram evaluates the compiled expression with the variables
+func EvaluateProgram(program cel.Program, variableNames []string, variables map[string]interface{}) (interface{}, error) {
+	activation, err := toActivation(variableNames, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	result, _, err := program.Eval(activation)
+	if err != nil {
+		return nil, fmt.Errorf("error on evaluating the expression, %s", err.Error())
+	}
+	return toNative(result)
+}
+
+// converts the variables to json compatible values, the entities accessed with json field names
+func toActivation(variableNames []string, variables map[string]interface{}) (map[string]interface{}, error) {
+	activation := make(map[string]interface{})
+	for _, name := range variableNames {
+		value, found := variables[name]
+		if !found || value == nil {
+			activation[name] = types.NullValue
+			continue
+		}
+		dataBytes, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("error on converting the variable '%s', %s", name, err.Error())
+		}
+		var converted interface{}
+		err = json.Unmarshal(dataBytes, &converted)
+		if err != nil {
+			return nil, fmt.Errorf("error on converting the variable '%s', %s", name, err.Error())
+		}
+		activation[name] = converted
+	}
+	return activation, nil
+}
+
+// converts the result to bool or map[string]interface{}
+func toNative(result ref.Val) (interface{}, error) {
+	switch result.Type() {
+	case types.BoolType:
+		return result.Value(), nil
+
+	case types.MapType:
+		converted, err := result.ConvertToNative(mapType)
+		if err != nil {
+			return nil, fmt.Errorf("error on converting the result, %s", err.Error())
+		}
+		return converted, nil
+
+	default:
+		return nil, fmt.Errorf("expression should return a bool or a map, returned %s", result.Type().TypeName())
+	}
+}
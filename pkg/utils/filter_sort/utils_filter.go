@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"time"
 
 	"github.com/mycontroller-org/server/v2/pkg/types/cmap"
 	sfTY "github.com/mycontroller-org/server/v2/pkg/types/service_filter"
@@ -61,6 +62,10 @@ func IsMatching(entity interface{}, filters []storageTY.Filter) bool {
 		case reflect.Bool:
 			match = CompareBool(value, filter.Operator, filter.Value)
 
+		case reflect.Struct:
+			timeValue, ok := value.(time.Time)
+			match = ok && CompareTime(timeValue, filter.Operator, filter.Value)
+
 		default:
 			match = false
 		}
@@ -128,6 +133,39 @@ func CompareString(value interface{}, operator string, filterValue interface{})
 	return false
 }
 
+// CompareTime compares time, filter value can be a time or a RFC3339 string
+func CompareTime(value time.Time, operator string, filterValue interface{}) bool {
+	expectedValue, ok := filterValue.(time.Time)
+	if !ok {
+		parsedValue, err := time.Parse(time.RFC3339Nano, converterUtils.ToString(filterValue))
+		if err != nil {
+			return false
+		}
+		expectedValue = parsedValue
+	}
+
+	switch operator {
+	case storageTY.OperatorEqual, storageTY.OperatorNone:
+		return value.Equal(expectedValue)
+
+	case storageTY.OperatorNotEqual:
+		return !value.Equal(expectedValue)
+
+	case storageTY.OperatorGreaterThan:
+		return value.After(expectedValue)
+
+	case storageTY.OperatorGreaterThanEqual:
+		return !value.Before(expectedValue)
+
+	case storageTY.OperatorLessThan:
+		return value.Before(expectedValue)
+
+	case storageTY.OperatorLessThanEqual:
+		return !value.After(expectedValue)
+	}
+	return false
+}
+
 // VerifyBoolSlice implementation
 func VerifyBoolSlice(value bool, operator string, filterValue interface{}) bool {
 	genericSlice, ok := filterValue.([]interface{})
@@ -0,0 +1,75 @@
+package javascript
+
+import (
+	"errors"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/dop251/goja_nodejs/require"
+	"go.uber.org/zap"
+)
+
+// ModuleLoader returns the source of the script module
+type ModuleLoader func(name string) (string, error)
+
+// ErrModuleNotFound should be returned by the module loader, if the module not available
+var ErrModuleNotFound = errors.New("script module not found")
+
+var (
+	moduleLoader ModuleLoader
+	// this can be shared by multiple runtimes, compiled modules are cached on the registry
+	registry      = require.NewRegistry(require.WithLoader(loadModuleSource))
+	registryMutex sync.RWMutex
+)
+
+// SetModuleLoader updates the loader of the script modules, used on require("name")
+func SetModuleLoader(loader ModuleLoader) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	moduleLoader = loader
+	registry = require.NewRegistry(require.WithLoader(loadModuleSource))
+}
+
+// ResetModules removes the compiled modules, should be called when a module updated or removed
+func ResetModules() {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	registry = require.NewRegistry(require.WithLoader(loadModuleSource))
+}
+
+func getRegistry() *require.Registry {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	return registry
+}
+
+// loadModuleSource resolves require("name") to the script module "name"
+// the filesystem is not accessible from the scripts
+func loadModuleSource(filename string) ([]byte, error) {
+	name := strings.TrimPrefix(path.Clean(filename), "node_modules/")
+	if !strings.HasSuffix(name, ".js") {
+		return nil, require.ModuleFileDoesNotExistError
+	}
+	name = strings.TrimSuffix(name, ".js")
+	if name == "" || strings.Contains(name, "/") {
+		return nil, require.ModuleFileDoesNotExistError
+	}
+
+	registryMutex.RLock()
+	loader := moduleLoader
+	registryMutex.RUnlock()
+	if loader == nil {
+		return nil, require.ModuleFileDoesNotExistError
+	}
+
+	source, err := loader(name)
+	if err != nil {
+		if errors.Is(err, ErrModuleNotFound) {
+			return nil, require.ModuleFileDoesNotExistError
+		}
+		zap.L().Error("error on loading a script module", zap.String("name", name), zap.Error(err))
+		return nil, err
+	}
+	return []byte(source), nil
+}
@@ -0,0 +1,142 @@
+package javascript
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+	settingsTY "github.com/mycontroller-org/server/v2/pkg/types/settings"
+	"github.com/mycontroller-org/server/v2/pkg/utils"
+	jsHelper "github.com/mycontroller-org/server/v2/pkg/utils/javascript/js_helper"
+	"go.uber.org/zap"
+)
+
+// default limits of the runtime
+const (
+	DefaultTimeout          = "10s"
+	DefaultMaxCallStackSize = 5000
+	DefaultMaxMemory        = "256MiB"
+
+	memoryCheckInterval = 100 * time.Millisecond
+)
+
+// runtimeConfig holds the parsed runtime settings
+type runtimeConfig struct {
+	timeout          time.Duration
+	maxCallStackSize int
+	maxMemory        int64           // approximate, heap growth of the process
+	allowedHelpers   map[string]bool // nil allows all the helpers
+}
+
+var (
+	config = runtimeConfig{
+		timeout:          utils.ToDuration(DefaultTimeout, 0),
+		maxCallStackSize: DefaultMaxCallStackSize,
+		maxMemory:        utils.ParseSizeWithDefault(DefaultMaxMemory, 0),
+	}
+	configMutex sync.RWMutex
+)
+
+// UpdateConfig applies the runtime settings, defaults used for the empty values
+func UpdateConfig(cfg settingsTY.ScriptRuntime) error {
+	updated := runtimeConfig{
+		timeout:          utils.ToDuration(DefaultTimeout, 0),
+		maxCallStackSize: DefaultMaxCallStackSize,
+		maxMemory:        utils.ParseSizeWithDefault(DefaultMaxMemory, 0),
+	}
+
+	if cfg.Timeout != "" {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid timeout '%s'", cfg.Timeout)
+		}
+		updated.timeout = timeout
+	}
+
+	if cfg.MaxCallStackSize < 0 {
+		return fmt.Errorf("invalid maxCallStackSize '%d'", cfg.MaxCallStackSize)
+	} else if cfg.MaxCallStackSize > 0 {
+		updated.maxCallStackSize = cfg.MaxCallStackSize
+	}
+
+	if cfg.MaxMemory != "" {
+		maxMemory, err := utils.ParseSize(cfg.MaxMemory)
+		if err != nil || maxMemory <= 0 {
+			return fmt.Errorf("invalid maxMemory '%s'", cfg.MaxMemory)
+		}
+		updated.maxMemory = maxMemory
+	}
+
+	if len(cfg.AllowedHelpers) > 0 {
+		helpers := jsHelper.GetHelperUtils()
+		updated.allowedHelpers = make(map[string]bool)
+		for _, helper := range cfg.AllowedHelpers {
+			if _, found := helpers[helper]; !found {
+				return fmt.Errorf("unknown helper '%s'", helper)
+			}
+			updated.allowedHelpers[helper] = true
+		}
+	}
+
+	configMutex.Lock()
+	config = updated
+	configMutex.Unlock()
+
+	zap.L().Debug("updated javascript runtime config", zap.Any("config", cfg))
+	return nil
+}
+
+func getConfig() runtimeConfig {
+	configMutex.RLock()
+	defer configMutex.RUnlock()
+	return config
+}
+
+// watch interrupts the runtime on the timeout or on the memory limit
+// returned function should be called once the execution completed
+func watch(rt *goja.Runtime, cfg runtimeConfig) func() {
+	done := make(chan struct{})
+
+	go func() {
+		timer := time.NewTimer(cfg.timeout)
+		defer timer.Stop()
+
+		// memory usage is sampled on the process heap, it is an approximate limit
+		var memoryTicker <-chan time.Time
+		var heapOnStart uint64
+		if cfg.maxMemory > 0 {
+			heapOnStart = heapAlloc()
+			ticker := time.NewTicker(memoryCheckInterval)
+			defer ticker.Stop()
+			memoryTicker = ticker.C
+		}
+
+		for {
+			select {
+			case <-done:
+				return
+
+			case <-timer.C:
+				rt.Interrupt(fmt.Errorf("script execution timed out after %s", cfg.timeout))
+				return
+
+			case <-memoryTicker:
+				current := heapAlloc()
+				if current > heapOnStart && int64(current-heapOnStart) > cfg.maxMemory {
+					rt.Interrupt(fmt.Errorf("script execution exceeded the memory limit of %s", utils.ToBinarySizeString(float64(cfg.maxMemory))))
+					return
+				}
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+func heapAlloc() uint64 {
+	stats := runtime.MemStats{}
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
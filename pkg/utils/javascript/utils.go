@@ -6,24 +6,23 @@ import (
 
 	"github.com/dop251/goja"
 	"github.com/dop251/goja_nodejs/console"
-	"github.com/dop251/goja_nodejs/require"
 
 	"go.uber.org/zap"
 
 	jsHelper "github.com/mycontroller-org/server/v2/pkg/utils/javascript/js_helper"
 )
 
-var registry = new(require.Registry) // this can be shared by multiple runtimes
-
 // Execute a given javascript
 func Execute(scriptString string, variables map[string]interface{}) (interface{}, error) {
+	cfg := getConfig()
 	rt := goja.New()
+	rt.SetMaxCallStackSize(cfg.maxCallStackSize)
 	// enable this line if we want to use supplied object as json
 	// GoLang func call will not be available, if json enabled
 	// rt.SetFieldNameMapper(goja.TagFieldNameMapper("json", true))
 
 	// add console support on javascript
-	registry.Enable(rt)
+	getRegistry().Enable(rt)
 	console.Enable(rt)
 
 	for name, value := range variables {
@@ -34,16 +33,34 @@ func Execute(scriptString string, variables map[string]interface{}) (interface{}
 	}
 	zap.L().Debug("executing script", zap.Any("variables", variables), zap.String("scriptString", scriptString))
 
-	// include helper functions
-	err := rt.Set(jsHelper.KeyMcUtils, jsHelper.GetHelperUtils())
+	// include allowed helper functions
+	helpers := jsHelper.GetHelperUtils()
+	if cfg.allowedHelpers != nil {
+		for name := range helpers {
+			if !cfg.allowedHelpers[name] {
+				delete(helpers, name)
+			}
+		}
+	}
+	err := rt.Set(jsHelper.KeyMcUtils, helpers)
 	if err != nil {
 		zap.L().Warn("error on setting helper functions", zap.Error(err))
 	}
 
-	// TODO: include timeout
-
+	stopWatch := watch(rt, cfg)
 	response, err := rt.RunString(string(scriptString))
+	stopWatch()
 	if err != nil {
+		var interruptedErr *goja.InterruptedError
+		if errors.As(err, &interruptedErr) {
+			if reason, ok := interruptedErr.Value().(error); ok {
+				return nil, reason
+			}
+		}
+		var stackOverflowErr *goja.StackOverflowError
+		if errors.As(err, &stackOverflowErr) {
+			return nil, fmt.Errorf("script exceeded the maximum call stack size of %d", cfg.maxCallStackSize)
+		}
 		return nil, err
 	}
 	output := response.Export()
@@ -52,6 +69,12 @@ func Execute(scriptString string, variables map[string]interface{}) (interface{}
 	return output, nil
 }
 
+// Validate verifies the syntax of the script, the script is not executed
+func Validate(scriptString string) error {
+	_, err := goja.Compile("", scriptString, false)
+	return err
+}
+
 // ToMap converts the interface data to map[string]interface{}
 func ToMap(data interface{}) (map[string]interface{}, error) {
 	if data == nil {
@@ -12,6 +12,7 @@ import (
 	firmwareTY "github.com/mycontroller-org/server/v2/pkg/types/firmware"
 	fwdPayloadTY "github.com/mycontroller-org/server/v2/pkg/types/forward_payload"
 	nodeTY "github.com/mycontroller-org/server/v2/pkg/types/node"
+	sceneTY "github.com/mycontroller-org/server/v2/pkg/types/scene"
 	scheduleTY "github.com/mycontroller-org/server/v2/pkg/types/schedule"
 	sourceTY "github.com/mycontroller-org/server/v2/pkg/types/source"
 	taskTY "github.com/mycontroller-org/server/v2/pkg/types/task"
@@ -32,6 +33,7 @@ var (
 	QuickIDFirmware       = []string{"firmware"}
 	QuickIDDataRepository = []string{"data_repository"}
 	QuickIDForwardPayload = []string{"forward_payload"}
+	QuickIDScene          = []string{"scene"}
 )
 
 // IsValidQuickID says is it in quikID format
@@ -55,6 +57,7 @@ func IsValidQuickID(quickID string) bool {
 	validIDs = append(validIDs, QuickIDDataRepository...)
 	validIDs = append(validIDs, QuickIDFirmware...)
 	validIDs = append(validIDs, QuickIDForwardPayload...)
+	validIDs = append(validIDs, QuickIDScene...)
 
 	return utils.ContainsString(validIDs, entityType)
 }
@@ -136,7 +139,8 @@ func EntityKeyValueMap(quickID string) (string, map[string]string, error) {
 		utils.ContainsString(QuickIDHandler, entityType),
 		utils.ContainsString(QuickIDDataRepository, entityType),
 		utils.ContainsString(QuickIDFirmware, entityType),
-		utils.ContainsString(QuickIDForwardPayload, entityType):
+		utils.ContainsString(QuickIDForwardPayload, entityType),
+		utils.ContainsString(QuickIDScene, entityType):
 		if typeID[1] == "" {
 			return "", nil, fmt.Errorf("invalid data. quickID:%s", quickID)
 		}
@@ -232,6 +236,12 @@ func GetQuickID(entity interface{}) (string, error) {
 			return fmt.Sprintf("%s:%s", QuickIDForwardPayload[0], res.ID), nil
 		}
 
+	case reflect.TypeOf(sceneTY.Config{}):
+		res, ok := entity.(sceneTY.Config)
+		if ok {
+			return fmt.Sprintf("%s:%s", QuickIDScene[0], res.ID), nil
+		}
+
 	default:
 		return "", fmt.Errorf("unsupported type received: %s", itemType.String())
 	}
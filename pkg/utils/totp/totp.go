@@ -0,0 +1,95 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// time based one time password, RFC 6238
+// compatible with the authenticator apps, sha1, 6 digits, 30 seconds period
+const (
+	Period     = 30
+	Digits     = 6
+	secretSize = 20
+	skewSteps  = 1 // accepts the codes of the previous and next steps, to tolerate the clock drift
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a base32 encoded random secret
+func GenerateSecret() (string, error) {
+	secret := make([]byte, secretSize)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(secret), nil
+}
+
+// GenerateCode returns the code of the time step
+func GenerateCode(secret string, step int64) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimSpace(secret)))
+	if err != nil {
+		return "", fmt.Errorf("invalid secret, error:%s", err.Error())
+	}
+
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	// dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	modulo := uint32(1)
+	for index := 0; index < Digits; index++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", Digits, value%modulo), nil
+}
+
+// GetStep returns the time step of the time
+func GetStep(t time.Time) int64 {
+	return t.Unix() / Period
+}
+
+// Validate verifies the code and returns the matched time step
+// the caller should reject the steps used already, to prevent replay
+func Validate(secret, code string, t time.Time) (bool, int64) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != Digits {
+		return false, 0
+	}
+	currentStep := GetStep(t)
+	for step := currentStep - skewSteps; step <= currentStep+skewSteps; step++ {
+		expected, err := GenerateCode(secret, step)
+		if err != nil {
+			return false, 0
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return true, step
+		}
+	}
+	return false, 0
+}
+
+// GetProvisioningURI returns the uri used to generate the qr code for the authenticator apps
+func GetProvisioningURI(issuer, account, secret string) string {
+	label := url.PathEscape(fmt.Sprintf("%s:%s", issuer, account))
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprintf("%d", Digits))
+	query.Set("period", fmt.Sprintf("%d", Period))
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, query.Encode())
+}
@@ -35,6 +35,7 @@ import (
 
 const (
 	webhookTimeout = time.Second * 10
+	redactedValue  = "***"
 )
 
 type genericAPI struct {
@@ -43,31 +44,133 @@ type genericAPI struct {
 
 // LoadVariables loads all the defined variables
 func LoadVariables(variablesPreMap map[string]string, secret string) (map[string]interface{}, error) {
+	variables, _, err := LoadVariablesWithRedacted(variablesPreMap, secret)
+	return variables, err
+}
+
+// LoadVariablesWithRedacted loads all the defined variables and a copy of the variables to expose on the traces
+// the copy masks the values of the special keys
+// secret references are not resolved, available only to the handler and gateway configurations
+func LoadVariablesWithRedacted(variablesPreMap map[string]string, secret string) (map[string]interface{}, map[string]interface{}, error) {
 	variables := make(map[string]interface{})
 	for name, stringValue := range variablesPreMap {
 		value := getEntity(name, stringValue)
 		if value == nil {
-			return nil, fmt.Errorf("failed to load a variable. name: %s, keyPath:%s", name, stringValue)
+			return nil, nil, fmt.Errorf("failed to load a variable. name: %s, keyPath:%s", name, stringValue)
 		}
 		variables[name] = value
 	}
 
 	// clone variables
 	clonedVariables := cloneUtil.Clone(variables)
+	redactedVariables := cloneUtil.Clone(variables)
 
 	backToVariables, ok := clonedVariables.(map[string]interface{})
-	if ok {
+	backToRedacted, redactedOk := redactedVariables.(map[string]interface{})
+	if ok && redactedOk {
 		// descrypt the secrets, tokens
 		err := cloneUtil.UpdateSecrets(backToVariables, secret, "", false, cloneUtil.DefaultSpecialKeys)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+
+		// mask the secrets, tokens
+		err = cloneUtil.RedactSecrets(backToRedacted, redactedValue, cloneUtil.DefaultSpecialKeys)
+		if err != nil {
+			return nil, nil, err
 		}
 
-		return backToVariables, nil
+		return backToVariables, backToRedacted, nil
 	}
 
 	zap.L().Error("error on clone, returning variables as is")
-	return variables, nil
+	return variables, variables, nil
+}
+
+// GetSecretValues returns the decrypted values, masked on the redacted variables
+func GetSecretValues(variables, redactedVariables map[string]interface{}) []string {
+	secretValues := make([]string, 0)
+	for name, redacted := range redactedVariables {
+		secretValues = appendSecretValues(secretValues, toGeneric(variables[name]), toGeneric(redacted))
+	}
+	return secretValues
+}
+
+func appendSecretValues(secretValues []string, actual, redacted interface{}) []string {
+	switch redactedValue := redacted.(type) {
+	case map[string]interface{}:
+		if actualMap, ok := actual.(map[string]interface{}); ok {
+			for key, value := range redactedValue {
+				secretValues = appendSecretValues(secretValues, actualMap[key], value)
+			}
+		}
+
+	case []interface{}:
+		if actualSlice, ok := actual.([]interface{}); ok && len(actualSlice) == len(redactedValue) {
+			for index := range redactedValue {
+				secretValues = appendSecretValues(secretValues, actualSlice[index], redactedValue[index])
+			}
+		}
+
+	default:
+		if actualString, ok := actual.(string); ok && actualString != "" && !reflect.DeepEqual(actual, redacted) {
+			secretValues = append(secretValues, actualString)
+		}
+	}
+	return secretValues
+}
+
+// toGeneric converts the structs to the maps, variables compared on the json format
+func toGeneric(data interface{}) interface{} {
+	if _, ok := data.(string); ok || data == nil {
+		return data
+	}
+	var generic interface{}
+	if err := json.ToStruct(data, &generic); err != nil {
+		return data
+	}
+	return generic
+}
+
+// RedactValues returns the data with the secret values masked
+// the data returned as is, if there is no secret value on it
+func RedactValues(data interface{}, secretValues []string) interface{} {
+	if data == nil || len(secretValues) == 0 {
+		return data
+	}
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		zap.L().Error("error on redacting the data", zap.Error(err))
+		return redactedValue
+	}
+	redacted := RedactString(string(dataBytes), secretValues, true)
+	if redacted == string(dataBytes) {
+		return data
+	}
+	var redactedData interface{}
+	if err = json.Unmarshal([]byte(redacted), &redactedData); err != nil {
+		zap.L().Error("error on redacting the data", zap.Error(err))
+		return redactedValue
+	}
+	return redactedData
+}
+
+// RedactString masks the secret values on the string
+// on json, the secret values are compared in the escaped format
+func RedactString(value string, secretValues []string, isJSON bool) string {
+	for _, secretValue := range secretValues {
+		if isJSON {
+			escaped, err := json.Marshal(secretValue)
+			if err != nil {
+				continue
+			}
+			secretValue = strings.TrimSuffix(strings.TrimPrefix(string(escaped), `"`), `"`)
+		}
+		if secretValue != "" {
+			value = strings.ReplaceAll(value, secretValue, redactedValue)
+		}
+	}
+	return value
 }
 
 func getEntity(name, stringValue string) interface{} {
@@ -0,0 +1,439 @@
+package embedded
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	sch "github.com/mycontroller-org/server/v2/pkg/service/core_scheduler"
+	"github.com/mycontroller-org/server/v2/pkg/types"
+	"github.com/mycontroller-org/server/v2/pkg/types/cmap"
+	"github.com/mycontroller-org/server/v2/pkg/utils"
+	converterUtils "github.com/mycontroller-org/server/v2/pkg/utils/convertor"
+	metricTY "github.com/mycontroller-org/server/v2/plugin/database/metric/types"
+	"go.uber.org/zap"
+)
+
+const (
+	PluginEmbedded = "embedded"
+
+	defaultDataDir            = "embedded"
+	defaultSegmentDuration    = 24 * time.Hour
+	defaultRetention          = 365 * 24 * time.Hour
+	defaultFlushInterval      = 5 * time.Second
+	defaultCompactionInterval = 1 * time.Hour
+
+	flushJobName      = "embedded-metric-db-flush"
+	compactionJobName = "embedded-metric-db-compaction"
+)
+
+// Config of the embedded metric database
+type Config struct {
+	DataDir            string             `yaml:"data_dir"`
+	SegmentDuration    string             `yaml:"segment_duration"`
+	Retention          string             `yaml:"retention"`
+	FlushInterval      string             `yaml:"flush_interval"`
+	CompactionInterval string             `yaml:"compaction_interval"`
+	Downsample         []DownsampleConfig `yaml:"downsample"`
+}
+
+// DownsampleConfig keeps the data older than "after" with the resolution of "window"
+type DownsampleConfig struct {
+	After  string `yaml:"after"`
+	Window string `yaml:"window"`
+}
+
+type downsampleRule struct {
+	after  time.Duration
+	window time.Duration
+}
+
+// retentionRules applied on the compaction
+type retentionRules struct {
+	retention       time.Duration
+	downsampleRules []downsampleRule
+}
+
+// bufferedSamples of a series, written to the disk on the next flush
+type bufferedSamples struct {
+	series  *series
+	samples []sample
+}
+
+// Client of the embedded metric database
+type Client struct {
+	Config             Config
+	dataDir            string
+	segmentDuration    time.Duration
+	flushInterval      time.Duration
+	compactionInterval time.Duration
+	defaultRules       retentionRules
+	policies           map[string]retentionRules
+	series             map[string]*series
+	buffer             map[string]*bufferedSamples
+	mutex              *sync.RWMutex
+	bufferMutex        *sync.Mutex
+	compactionMutex    *sync.Mutex
+	policyMutex        *sync.RWMutex
+}
+
+// NewClient of the embedded metric database
+func NewClient(config cmap.CustomMap) (metricTY.Plugin, error) {
+	cfg := Config{}
+	err := utils.MapToStruct(utils.TagNameYaml, config, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.DataDir == "" {
+		cfg.DataDir = defaultDataDir
+	}
+
+	c := &Client{
+		Config:             cfg,
+		dataDir:            path.Join(types.GetDataDirectoryMetric(), cfg.DataDir),
+		segmentDuration:    utils.ToDuration(cfg.SegmentDuration, defaultSegmentDuration),
+		flushInterval:      utils.ToDuration(cfg.FlushInterval, defaultFlushInterval),
+		compactionInterval: utils.ToDuration(cfg.CompactionInterval, defaultCompactionInterval),
+		defaultRules:       retentionRules{retention: defaultRetention},
+		policies:           make(map[string]retentionRules),
+		series:             make(map[string]*series),
+		buffer:             make(map[string]*bufferedSamples),
+		mutex:              &sync.RWMutex{},
+		bufferMutex:        &sync.Mutex{},
+		compactionMutex:    &sync.Mutex{},
+		policyMutex:        &sync.RWMutex{},
+	}
+
+	if cfg.Retention != "" {
+		retention, err := metricTY.ParseDuration(cfg.Retention)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retention:%s, error:%s", cfg.Retention, err.Error())
+		}
+		c.defaultRules.retention = retention
+	}
+
+	if c.segmentDuration < time.Minute {
+		zap.L().Warn("minimum supported segment duration is 1m, switching back to default", zap.String("segmentDuration", cfg.SegmentDuration))
+		c.segmentDuration = defaultSegmentDuration
+	}
+
+	// update downsample rules
+	for _, ds := range cfg.Downsample {
+		after, err := metricTY.ParseDuration(ds.After)
+		if err != nil {
+			return nil, fmt.Errorf("invalid downsample after duration:%s, error:%s", ds.After, err.Error())
+		}
+		window, err := metricTY.ParseDuration(ds.Window)
+		if err != nil {
+			return nil, fmt.Errorf("invalid downsample window duration:%s, error:%s", ds.Window, err.Error())
+		}
+		if window < time.Second {
+			return nil, fmt.Errorf("minimum supported downsample window is 1s, received:%s", ds.Window)
+		}
+		c.defaultRules.downsampleRules = append(c.defaultRules.downsampleRules, downsampleRule{after: after, window: window})
+	}
+	sort.Slice(c.defaultRules.downsampleRules, func(i, j int) bool {
+		return c.defaultRules.downsampleRules[i].after < c.defaultRules.downsampleRules[j].after
+	})
+
+	err = c.loadSeries()
+	if err != nil {
+		return nil, err
+	}
+
+	// schedule flush and compaction jobs
+	err = sch.SVC.AddFunc(flushJobName, fmt.Sprintf("@every %s", c.flushInterval.String()), c.flush)
+	if err != nil {
+		return nil, err
+	}
+	err = sch.SVC.AddFunc(compactionJobName, fmt.Sprintf("@every %s", c.compactionInterval.String()), c.compact)
+	if err != nil {
+		return nil, err
+	}
+
+	zap.L().Info("embedded metric database loaded", zap.String("dataDir", c.dataDir), zap.Int("numberOfSeries", len(c.series)))
+	return c, nil
+}
+
+func (c *Client) Name() string {
+	return PluginEmbedded
+}
+
+// Close flushes the buffered data and stops the jobs
+func (c *Client) Close() error {
+	sch.SVC.RemoveFunc(flushJobName)
+	sch.SVC.RemoveFunc(compactionJobName)
+	c.flush()
+	return nil
+}
+
+// Ping verifies the data directory availability
+func (c *Client) Ping() error {
+	if !utils.IsDirExists(c.dataDir) {
+		return fmt.Errorf("data directory not available: %s", c.dataDir)
+	}
+	return nil
+}
+
+// Write keeps the data in the buffer, will be written to the disk on the next flush
+func (c *Client) Write(data *metricTY.InputData) error {
+	if data.MetricType == metricTY.MetricTypeNone {
+		return nil
+	}
+	s, smpl, err := c.toSample(data)
+	if err != nil {
+		return err
+	}
+
+	c.bufferMutex.Lock()
+	defer c.bufferMutex.Unlock()
+	buffered, found := c.buffer[s.ID]
+	if !found {
+		buffered = &bufferedSamples{series: s}
+		c.buffer[s.ID] = buffered
+	}
+	buffered.samples = append(buffered.samples, *smpl)
+	return nil
+}
+
+// WriteBlocking writes the data directly to the disk
+func (c *Client) WriteBlocking(data *metricTY.InputData) error {
+	if data.MetricType == metricTY.MetricTypeNone {
+		return nil
+	}
+	s, smpl, err := c.toSample(data)
+	if err != nil {
+		return err
+	}
+	return c.appendSamples(s, []sample{*smpl})
+}
+
+// WriteBatch writes the data directly to the disk, grouped by the series
+func (c *Client) WriteBatch(data []*metricTY.InputData) error {
+	seriesMap := make(map[string]*series)
+	samplesMap := make(map[string][]sample)
+	for _, item := range data {
+		if item.MetricType == metricTY.MetricTypeNone {
+			continue
+		}
+		s, smpl, err := c.toSample(item)
+		if err != nil {
+			return err
+		}
+		seriesMap[s.ID] = s
+		samplesMap[s.ID] = append(samplesMap[s.ID], *smpl)
+	}
+
+	for id, samples := range samplesMap {
+		err := c.appendSamples(seriesMap[id], samples)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Query func implementation
+func (c *Client) Query(queryConfig *metricTY.QueryConfig) (map[string][]metricTY.ResponseData, error) {
+	// flush the buffered data, to include the recent data on the result
+	c.flush()
+
+	metricsMap := make(map[string][]metricTY.ResponseData)
+	for _, q := range queryConfig.Individual {
+		// clone global config
+		query := queryConfig.Global.Clone()
+		// update individual config
+		query.Merge(&q)
+
+		metrics, err := c.executeQuery(&query)
+		if err != nil {
+			return metricsMap, err
+		}
+		metricsMap[q.Name] = metrics
+	}
+	return metricsMap, nil
+}
+
+// UpdatePolicies updates the retention and downsample rules, applied on the next compaction
+func (c *Client) UpdatePolicies(policies []metricTY.Policy) error {
+	updatedPolicies := make(map[string]retentionRules)
+	for index := range policies {
+		policy := &policies[index]
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+		rules := retentionRules{retention: policy.TotalRetention()}
+		after, _ := metricTY.ParseDuration(policy.Retention)
+		for _, ds := range policy.SortedDownsample() {
+			window, _ := metricTY.ParseDuration(ds.Window)
+			rules.downsampleRules = append(rules.downsampleRules, downsampleRule{after: after, window: window})
+			after, _ = metricTY.ParseDuration(ds.Retention)
+		}
+		updatedPolicies[policy.Name] = rules
+	}
+
+	c.policyMutex.Lock()
+	defer c.policyMutex.Unlock()
+	c.policies = updatedPolicies
+	return nil
+}
+
+// getRules returns the retention rules of the policy, if not found returns the default rules
+func (c *Client) getRules(policy string) retentionRules {
+	c.policyMutex.RLock()
+	defer c.policyMutex.RUnlock()
+	if rules, found := c.policies[policy]; found {
+		return rules
+	}
+	return c.defaultRules
+}
+
+// flush writes the buffered samples to the disk
+func (c *Client) flush() {
+	c.bufferMutex.Lock()
+	buffer := c.buffer
+	c.buffer = make(map[string]*bufferedSamples)
+	c.bufferMutex.Unlock()
+
+	for id, buffered := range buffer {
+		err := c.appendSamples(buffered.series, buffered.samples)
+		if err != nil {
+			zap.L().Error("error on writing samples to disk", zap.String("seriesID", id), zap.Int("samples", len(buffered.samples)), zap.Error(err))
+		}
+	}
+}
+
+// appendSamples writes the samples to the series
+// recreates the series, if it was removed by the compaction
+func (c *Client) appendSamples(s *series, samples []sample) error {
+	err := s.append(samples, c.segmentDuration)
+	if !errors.Is(err, errSeriesRemoved) {
+		return err
+	}
+	policy := s.getPolicy()
+	s, err = c.getOrCreateSeries(s.Meta.MetricType, s.Meta.Tags)
+	if err != nil {
+		return err
+	}
+	err = s.updatePolicy(policy)
+	if err != nil {
+		return err
+	}
+	return s.append(samples, c.segmentDuration)
+}
+
+// loadSeries loads all the available series from the disk
+func (c *Client) loadSeries() error {
+	dirs, err := utils.ListDirs(c.dataDir)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, dir := range dirs {
+		s, err := loadSeries(c.dataDir, dir.Name)
+		if err != nil {
+			zap.L().Warn("error on loading a series, ignoring it", zap.String("seriesDir", dir.FullPath), zap.Error(err))
+			continue
+		}
+		c.series[s.ID] = s
+	}
+	return nil
+}
+
+func (c *Client) getSeries(id string) *series {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.series[id]
+}
+
+// getOrCreateSeries returns the series, creates it if not available
+func (c *Client) getOrCreateSeries(metricType string, tags map[string]string) (*series, error) {
+	id := seriesID(metricType, tags)
+	if s := c.getSeries(id); s != nil {
+		return s, nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if s, found := c.series[id]; found {
+		return s, nil
+	}
+	s, err := newSeries(c.dataDir, metricType, tags)
+	if err != nil {
+		return nil, err
+	}
+	c.series[id] = s
+	return s, nil
+}
+
+// getSeriesList returns the series matching the metric type and tags
+func (c *Client) getSeriesList(metricType string, tags map[string]string) []*series {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	matched := make([]*series, 0)
+	for _, s := range c.series {
+		if s.matches(metricType, tags) {
+			matched = append(matched, s)
+		}
+	}
+	return matched
+}
+
+// toSample converts the input data to a sample and returns with the target series
+func (c *Client) toSample(data *metricTY.InputData) (*series, *sample, error) {
+	// convert tags to lowercase
+	tags := make(map[string]string)
+	for name, value := range data.Tags {
+		tags[strings.ToLower(name)] = value
+	}
+
+	timestamp := data.Time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	smpl := &sample{
+		Time:    timestamp,
+		Count:   1,
+		Numbers: make(map[string]aggregate),
+		Strings: make(map[string]string),
+	}
+	for name, value := range data.Fields {
+		switch data.MetricType {
+		case metricTY.MetricTypeString:
+			smpl.Strings[name] = converterUtils.ToString(value)
+
+		case metricTY.MetricTypeBinary:
+			number := float64(0)
+			if converterUtils.ToBool(value) {
+				number = 1
+			}
+			smpl.Numbers[name] = aggregate{Mean: number, Min: number, Max: number, Last: number, Count: 1}
+
+		case metricTY.MetricTypeGauge, metricTY.MetricTypeGaugeFloat, metricTY.MetricTypeCounter, metricTY.MetricTypeGEO:
+			number := converterUtils.ToFloat(value)
+			smpl.Numbers[name] = aggregate{Mean: number, Min: number, Max: number, Last: number, Count: 1}
+
+		default:
+			return nil, nil, fmt.Errorf("unknown metric type: %s", data.MetricType)
+		}
+	}
+
+	s, err := c.getOrCreateSeries(data.MetricType, tags)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = s.updatePolicy(data.Policy)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, smpl, nil
+}
@@ -0,0 +1,232 @@
+package embedded
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+// aggregate keeps the summary of a numeric field
+// for a raw sample all the values are the same
+type aggregate struct {
+	Mean  float64
+	Min   float64
+	Max   float64
+	Last  float64
+	Count uint64 // number of merged values of the field, can be lower than the sample count
+}
+
+// sample is a single record in a segment file
+// Count is 1 for raw samples and number of merged raw samples for downsampled records
+type sample struct {
+	Time    time.Time
+	Count   uint64
+	Numbers map[string]aggregate
+	Strings map[string]string
+}
+
+// record format:
+// uvarint(payload length), payload
+// payload: varint(unix nano), uvarint(count),
+//
+//	uvarint(numbers count), [name, float64 mean] or [name, mean, min, max, last] when count > 1
+//	uvarint(strings count), [name, value]
+//	optional: uvarint(field counts count), [name, uvarint(count)] for the numbers with a count different from the sample count
+func encodeSample(s *sample) []byte {
+	payload := make([]byte, 0, 64)
+	payload = binary.AppendVarint(payload, s.Time.UnixNano())
+	payload = binary.AppendUvarint(payload, s.Count)
+
+	payload = binary.AppendUvarint(payload, uint64(len(s.Numbers)))
+	for _, name := range sortedKeys(s.Numbers) {
+		agg := s.Numbers[name]
+		payload = appendString(payload, name)
+		payload = appendFloat(payload, agg.Mean)
+		if s.Count > 1 {
+			payload = appendFloat(payload, agg.Min)
+			payload = appendFloat(payload, agg.Max)
+			payload = appendFloat(payload, agg.Last)
+		}
+	}
+
+	payload = binary.AppendUvarint(payload, uint64(len(s.Strings)))
+	for _, name := range sortedKeys(s.Strings) {
+		payload = appendString(payload, name)
+		payload = appendString(payload, s.Strings[name])
+	}
+
+	fieldCounts := make(map[string]uint64)
+	for name, agg := range s.Numbers {
+		if agg.Count != 0 && agg.Count != s.Count {
+			fieldCounts[name] = agg.Count
+		}
+	}
+	if len(fieldCounts) > 0 {
+		payload = binary.AppendUvarint(payload, uint64(len(fieldCounts)))
+		for _, name := range sortedKeys(fieldCounts) {
+			payload = appendString(payload, name)
+			payload = binary.AppendUvarint(payload, fieldCounts[name])
+		}
+	}
+
+	record := make([]byte, 0, len(payload)+binary.MaxVarintLen64)
+	record = binary.AppendUvarint(record, uint64(len(payload)))
+	return append(record, payload...)
+}
+
+// decodeSamples reads all the records from the data
+// a truncated record at the end (partial write) is ignored
+func decodeSamples(data []byte) ([]sample, error) {
+	samples := make([]sample, 0)
+	reader := bytes.NewReader(data)
+	for reader.Len() > 0 {
+		length, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return samples, nil
+		}
+		if length > uint64(reader.Len()) {
+			return samples, nil
+		}
+		payload := make([]byte, length)
+		if _, err = io.ReadFull(reader, payload); err != nil {
+			return samples, nil
+		}
+		s, err := decodePayload(payload)
+		if err != nil {
+			return samples, err
+		}
+		samples = append(samples, *s)
+	}
+	return samples, nil
+}
+
+func decodePayload(payload []byte) (*sample, error) {
+	reader := bytes.NewReader(payload)
+	timestamp, err := binary.ReadVarint(reader)
+	if err != nil {
+		return nil, err
+	}
+	count, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, err
+	}
+	s := &sample{
+		Time:    time.Unix(0, timestamp),
+		Count:   count,
+		Numbers: make(map[string]aggregate),
+		Strings: make(map[string]string),
+	}
+
+	numbersCount, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, err
+	}
+	for index := uint64(0); index < numbersCount; index++ {
+		name, err := readString(reader)
+		if err != nil {
+			return nil, err
+		}
+		mean, err := readFloat(reader)
+		if err != nil {
+			return nil, err
+		}
+		agg := aggregate{Mean: mean, Min: mean, Max: mean, Last: mean, Count: count}
+		if count > 1 {
+			if agg.Min, err = readFloat(reader); err != nil {
+				return nil, err
+			}
+			if agg.Max, err = readFloat(reader); err != nil {
+				return nil, err
+			}
+			if agg.Last, err = readFloat(reader); err != nil {
+				return nil, err
+			}
+		}
+		s.Numbers[name] = agg
+	}
+
+	stringsCount, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, err
+	}
+	for index := uint64(0); index < stringsCount; index++ {
+		name, err := readString(reader)
+		if err != nil {
+			return nil, err
+		}
+		value, err := readString(reader)
+		if err != nil {
+			return nil, err
+		}
+		s.Strings[name] = value
+	}
+
+	// field counts are not available on the older records
+	if reader.Len() == 0 {
+		return s, nil
+	}
+	fieldCountsCount, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, err
+	}
+	for index := uint64(0); index < fieldCountsCount; index++ {
+		name, err := readString(reader)
+		if err != nil {
+			return nil, err
+		}
+		fieldCount, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, err
+		}
+		if agg, found := s.Numbers[name]; found {
+			agg.Count = fieldCount
+			s.Numbers[name] = agg
+		}
+	}
+	return s, nil
+}
+
+func appendString(data []byte, value string) []byte {
+	data = binary.AppendUvarint(data, uint64(len(value)))
+	return append(data, value...)
+}
+
+func appendFloat(data []byte, value float64) []byte {
+	return binary.LittleEndian.AppendUint64(data, math.Float64bits(value))
+}
+
+func readString(reader *bytes.Reader) (string, error) {
+	length, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return "", err
+	}
+	if length > uint64(reader.Len()) {
+		return "", errors.New("invalid string length")
+	}
+	data := make([]byte, length)
+	if _, err = io.ReadFull(reader, data); err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func readFloat(reader *bytes.Reader) (float64, error) {
+	data := make([]byte, 8)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return 0, err
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(data)), nil
+}
+
+func sortedKeys[T any](data map[string]T) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,128 @@
+package embedded
+
+import (
+	"time"
+
+	"github.com/mycontroller-org/server/v2/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// compact runs on all the series
+// removes the segments beyond the retention, compresses the completed segments
+// and downsamples as per the downsample rules
+func (c *Client) compact() {
+	c.compactionMutex.Lock()
+	defer c.compactionMutex.Unlock()
+
+	startTime := time.Now()
+
+	c.mutex.RLock()
+	seriesList := make([]*series, 0, len(c.series))
+	for _, s := range c.series {
+		seriesList = append(seriesList, s)
+	}
+	c.mutex.RUnlock()
+
+	for _, s := range seriesList {
+		empty, err := c.compactSeries(s, startTime)
+		if err != nil {
+			zap.L().Error("error on compacting a series", zap.String("seriesID", s.ID), zap.Error(err))
+			continue
+		}
+		if empty {
+			c.removeSeries(s)
+		}
+	}
+	zap.L().Debug("embedded metric database compaction completed", zap.Int("numberOfSeries", len(seriesList)), zap.String("timeTaken", time.Since(startTime).String()))
+}
+
+// compactSeries returns true, if there is no segment left on the series
+func (c *Client) compactSeries(s *series, now time.Time) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	segments, err := s.listSegments()
+	if err != nil {
+		return false, err
+	}
+
+	rules := c.getRules(s.Meta.Policy)
+	remaining := 0
+	for index := range segments {
+		seg := &segments[index]
+		segEnd := time.Unix(seg.Start, 0).Add(c.segmentDuration)
+
+		// segment still active
+		if segEnd.After(now) {
+			remaining++
+			continue
+		}
+
+		// remove the segment beyond the retention
+		if rules.retention > 0 && segEnd.Before(now.Add(-rules.retention)) {
+			err = s.removeSegment(seg)
+			if err != nil {
+				return false, err
+			}
+			continue
+		}
+		remaining++
+
+		window := rules.getDownsampleWindow(now.Sub(segEnd))
+		if !seg.HasRaw && seg.Window >= int64(window.Seconds()) {
+			continue
+		}
+
+		samples, err := s.readSegment(seg)
+		if err != nil {
+			return false, err
+		}
+		samples = downsample(samples, window)
+		err = s.writeCompacted(seg, samples, int64(window.Seconds()))
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return remaining == 0, nil
+}
+
+// getDownsampleWindow returns the downsample window for the data age
+func (rr *retentionRules) getDownsampleWindow(age time.Duration) time.Duration {
+	window := time.Duration(0)
+	for _, rule := range rr.downsampleRules {
+		if age >= rule.after {
+			window = rule.window
+		}
+	}
+	return window
+}
+
+// removeSeries removes a series without data from the index and the disk
+func (c *Client) removeSeries(s *series) {
+	// do not remove, if there is a pending data
+	c.bufferMutex.Lock()
+	defer c.bufferMutex.Unlock()
+	if buffered, found := c.buffer[s.ID]; found && len(buffered.samples) > 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// verify no new data written on the mean time
+	segments, err := s.listSegments()
+	if err != nil || len(segments) > 0 {
+		return
+	}
+	delete(c.series, s.ID)
+	// the samples taken by a flush or a blocking write in the mean time, written to a new series
+	s.removed = true
+	err = utils.RemoveDir(s.dir)
+	if err != nil {
+		zap.L().Error("error on removing a series directory", zap.String("seriesDir", s.dir), zap.Error(err))
+	}
+}
@@ -0,0 +1,83 @@
+package embedded
+
+import (
+	"time"
+)
+
+// downsample merges the samples into the given window
+// samples should be sorted by time, merged sample keeps the window start time
+func downsample(samples []sample, window time.Duration) []sample {
+	if window <= 0 || len(samples) == 0 {
+		return samples
+	}
+
+	merged := make([]sample, 0)
+	var current *sample
+	var currentWindow time.Time
+	for index := range samples {
+		item := &samples[index]
+		itemWindow := item.Time.Truncate(window)
+		if current == nil || !itemWindow.Equal(currentWindow) {
+			if current != nil {
+				merged = append(merged, *current)
+			}
+			currentWindow = itemWindow
+			current = &sample{
+				Time:    itemWindow,
+				Count:   0,
+				Numbers: make(map[string]aggregate),
+				Strings: make(map[string]string),
+			}
+		}
+		mergeSample(current, item)
+	}
+	if current != nil {
+		merged = append(merged, *current)
+	}
+	return merged
+}
+
+// mergeSample merges the source sample into the destination
+// source sample should be newer than all the samples merged before
+// mean is weighted with the count of the field, a field can be missing on some samples
+func mergeSample(dst, src *sample) {
+	srcCount := src.Count
+	if srcCount == 0 {
+		srcCount = 1
+	}
+	for name, srcAgg := range src.Numbers {
+		srcAgg.Count = fieldCount(&srcAgg, srcCount)
+		dstAgg, found := dst.Numbers[name]
+		if !found {
+			dst.Numbers[name] = srcAgg
+			continue
+		}
+		dstFieldCount := fieldCount(&dstAgg, dst.Count)
+		total := float64(dstFieldCount + srcAgg.Count)
+		dstAgg.Mean = (dstAgg.Mean*float64(dstFieldCount) + srcAgg.Mean*float64(srcAgg.Count)) / total
+		if srcAgg.Min < dstAgg.Min {
+			dstAgg.Min = srcAgg.Min
+		}
+		if srcAgg.Max > dstAgg.Max {
+			dstAgg.Max = srcAgg.Max
+		}
+		dstAgg.Last = srcAgg.Last
+		dstAgg.Count = dstFieldCount + srcAgg.Count
+		dst.Numbers[name] = dstAgg
+	}
+	for name, value := range src.Strings {
+		dst.Strings[name] = value
+	}
+	dst.Count += srcCount
+}
+
+// fieldCount returns the count of the field, the sample count used when not available
+func fieldCount(agg *aggregate, sampleCount uint64) uint64 {
+	if agg.Count != 0 {
+		return agg.Count
+	}
+	if sampleCount == 0 {
+		return 1
+	}
+	return sampleCount
+}
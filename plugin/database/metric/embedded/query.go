@@ -0,0 +1,130 @@
+package embedded
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metricTY "github.com/mycontroller-org/server/v2/plugin/database/metric/types"
+)
+
+// contants
+const (
+	defaultWindow = 5 * time.Minute
+	defaultStart  = -1 * time.Hour
+
+	FunctionMean = "mean"
+	FunctionMin  = "min"
+	FunctionMax  = "max"
+	FunctionLast = "last"
+)
+
+var defaultFunctions = []string{FunctionMean, FunctionMin, FunctionMax}
+
+func (c *Client) executeQuery(q *metricTY.Query) ([]metricTY.ResponseData, error) {
+	now := time.Now()
+	start, err := parseTime(q.Start, now, now.Add(defaultStart))
+	if err != nil {
+		return nil, err
+	}
+	stop, err := parseTime(q.Stop, now, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// collect samples from all the matching series
+	samples := make([]sample, 0)
+	for _, s := range c.getSeriesList(q.MetricType, q.Tags) {
+		seriesSamples, err := s.read(start, stop, c.segmentDuration)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, seriesSamples...)
+	}
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].Time.Before(samples[j].Time) })
+
+	metrics := make([]metricTY.ResponseData, 0)
+	switch q.MetricType {
+	case metricTY.MetricTypeGauge, metricTY.MetricTypeGaugeFloat, metricTY.MetricTypeCounter:
+		window := defaultWindow
+		if q.Window != "" {
+			window, err = time.ParseDuration(q.Window)
+			if err != nil {
+				return nil, fmt.Errorf("invalid window:%s, error:%s", q.Window, err.Error())
+			}
+			if window <= 0 {
+				return nil, fmt.Errorf("invalid window:%s", q.Window)
+			}
+		}
+
+		functions := q.Functions
+		if len(functions) == 0 {
+			functions = defaultFunctions
+		}
+		for _, fn := range functions {
+			switch strings.ToLower(fn) {
+			case FunctionMean, FunctionMin, FunctionMax, FunctionLast:
+			default:
+				return nil, fmt.Errorf("unsupported function:%s, supported functions:%v", fn, []string{FunctionMean, FunctionMin, FunctionMax, FunctionLast})
+			}
+		}
+
+		for _, item := range downsample(samples, window) {
+			agg, found := item.Numbers[metricTY.FieldValue]
+			if !found {
+				continue
+			}
+			_metric := make(map[string]interface{})
+			for _, fn := range functions {
+				fn = strings.ToLower(fn)
+				switch fn {
+				case FunctionMean:
+					_metric[fn] = agg.Mean
+				case FunctionMin:
+					_metric[fn] = agg.Min
+				case FunctionMax:
+					_metric[fn] = agg.Max
+				case FunctionLast:
+					_metric[fn] = agg.Last
+				}
+			}
+			// report with the window stop time, similar to influxdb aggregateWindow
+			metrics = append(metrics, metricTY.ResponseData{Time: item.Time.Add(window), MetricType: q.MetricType, Metric: _metric})
+		}
+
+	default:
+		// returns the stored values as is
+		for _, item := range samples {
+			_metric := make(map[string]interface{})
+			for name, agg := range item.Numbers {
+				_metric[name] = agg.Last
+			}
+			for name, value := range item.Strings {
+				_metric[name] = value
+			}
+			metrics = append(metrics, metricTY.ResponseData{Time: item.Time, MetricType: q.MetricType, Metric: _metric})
+		}
+	}
+
+	return metrics, nil
+}
+
+// parseTime supports relative duration (-1h), "now()" and RFC3339 formats
+func parseTime(value string, now, defaultTime time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return defaultTime, nil
+	}
+	if value == "now()" {
+		return now, nil
+	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(duration), nil
+	}
+	parsedTime, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time format:%s, supported formats: relative duration(-1h), now(), RFC3339", value)
+	}
+	return parsedTime, nil
+}
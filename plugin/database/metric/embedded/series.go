@@ -0,0 +1,347 @@
+package embedded
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	json "github.com/mycontroller-org/server/v2/pkg/json"
+	"github.com/mycontroller-org/server/v2/pkg/utils"
+)
+
+const (
+	seriesMetaFilename = "series.json"
+	rawSegmentSuffix   = ".seg"
+	compactedSuffix    = ".seg.gz"
+)
+
+// seriesMeta stored on the series directory
+type seriesMeta struct {
+	MetricType string            `json:"metricType"`
+	Tags       map[string]string `json:"tags"`
+	Policy     string            `json:"policy"`
+}
+
+// series holds a single time series on the disk
+// each segment of the series kept in a separate file, named with segment start time
+// raw appends go to "<start>.seg", compaction merges it into "<start>-<window>.seg.gz"
+type series struct {
+	ID      string
+	Meta    seriesMeta
+	dir     string
+	mutex   *sync.RWMutex
+	removed bool // removed by the compaction, appends should go to a new series
+}
+
+// errSeriesRemoved returned on append to a removed series
+var errSeriesRemoved = errors.New("series removed")
+
+// segment file details
+type segment struct {
+	Start     int64 // unix seconds
+	HasRaw    bool
+	Compacted string // compacted file name, if available
+	Window    int64  // downsample window of the compacted file in seconds, 0 = raw
+}
+
+// returns unique id for the metric type and tags
+func seriesID(metricType string, tags map[string]string) string {
+	var key strings.Builder
+	key.WriteString(metricType)
+	for _, name := range sortedKeys(tags) {
+		fmt.Fprintf(&key, ",%s=%s", name, tags[name])
+	}
+	hash := sha1.Sum([]byte(key.String()))
+	return hex.EncodeToString(hash[:])
+}
+
+func newSeries(rootDir, metricType string, tags map[string]string) (*series, error) {
+	id := seriesID(metricType, tags)
+	s := &series{
+		ID:    id,
+		Meta:  seriesMeta{MetricType: metricType, Tags: tags},
+		dir:   path.Join(rootDir, id),
+		mutex: &sync.RWMutex{},
+	}
+	err := s.writeMeta()
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *series) writeMeta() error {
+	metaBytes, err := json.Marshal(&s.Meta)
+	if err != nil {
+		return err
+	}
+	return utils.WriteFile(s.dir, seriesMetaFilename, metaBytes)
+}
+
+// getPolicy returns the retention policy name of the series
+func (s *series) getPolicy() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.Meta.Policy
+}
+
+// updatePolicy updates the retention policy name of the series
+func (s *series) updatePolicy(policy string) error {
+	if s.getPolicy() == policy {
+		return nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.Meta.Policy = policy
+	return s.writeMeta()
+}
+
+func loadSeries(rootDir, id string) (*series, error) {
+	s := &series{
+		ID:    id,
+		dir:   path.Join(rootDir, id),
+		mutex: &sync.RWMutex{},
+	}
+	metaBytes, err := os.ReadFile(path.Join(s.dir, seriesMetaFilename))
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(metaBytes, &s.Meta)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// matches returns true, if all the supplied tags present on this series
+func (s *series) matches(metricType string, tags map[string]string) bool {
+	if metricType != "" && s.Meta.MetricType != metricType {
+		return false
+	}
+	for name, value := range tags {
+		if s.Meta.Tags[strings.ToLower(name)] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// append samples to the raw segment files
+func (s *series) append(samples []sample, segmentDuration time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// do not recreate the removed directory without the meta file
+	if s.removed {
+		return errSeriesRemoved
+	}
+
+	// group by segment
+	segments := make(map[int64][]byte)
+	for index := range samples {
+		start := segmentStart(samples[index].Time, segmentDuration)
+		segments[start] = append(segments[start], encodeSample(&samples[index])...)
+	}
+
+	for start, data := range segments {
+		err := utils.AppendFile(s.dir, rawFilename(start), data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listSegments returns the segments sorted by start time
+func (s *series) listSegments() ([]segment, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	segmentsMap := make(map[int64]*segment)
+	getSegment := func(start int64) *segment {
+		seg, found := segmentsMap[start]
+		if !found {
+			seg = &segment{Start: start}
+			segmentsMap[start] = seg
+		}
+		return seg
+	}
+	for _, file := range files {
+		name := file.Name()
+		switch {
+		case strings.HasSuffix(name, compactedSuffix):
+			parts := strings.SplitN(strings.TrimSuffix(name, compactedSuffix), "-", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			start, err := strconv.ParseInt(parts[0], 10, 64)
+			if err != nil {
+				continue
+			}
+			window, err := strconv.ParseInt(parts[1], 10, 64)
+			if err != nil {
+				continue
+			}
+			seg := getSegment(start)
+			seg.Compacted = name
+			seg.Window = window
+
+		case strings.HasSuffix(name, rawSegmentSuffix):
+			start, err := strconv.ParseInt(strings.TrimSuffix(name, rawSegmentSuffix), 10, 64)
+			if err != nil {
+				continue
+			}
+			getSegment(start).HasRaw = true
+		}
+	}
+
+	segments := make([]segment, 0, len(segmentsMap))
+	for _, seg := range segmentsMap {
+		segments = append(segments, *seg)
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i].Start < segments[j].Start })
+	return segments, nil
+}
+
+// readSegment returns all the samples from compacted and raw files of a segment, sorted by time
+func (s *series) readSegment(seg *segment) ([]sample, error) {
+	samples := make([]sample, 0)
+	if seg.Compacted != "" {
+		compressed, err := os.ReadFile(path.Join(s.dir, seg.Compacted))
+		if err != nil {
+			return nil, err
+		}
+		reader, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return nil, err
+		}
+		compacted, err := decodeSamples(data)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, compacted...)
+	}
+	if seg.HasRaw {
+		data, err := os.ReadFile(path.Join(s.dir, rawFilename(seg.Start)))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		raw, err := decodeSamples(data)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, raw...)
+	}
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].Time.Before(samples[j].Time) })
+	return samples, nil
+}
+
+// read returns the samples between the start and stop time
+func (s *series) read(start, stop time.Time, segmentDuration time.Duration) ([]sample, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	segments, err := s.listSegments()
+	if err != nil {
+		return nil, err
+	}
+	samples := make([]sample, 0)
+	for index := range segments {
+		seg := &segments[index]
+		segStart := time.Unix(seg.Start, 0)
+		if !segStart.Before(stop) || !segStart.Add(segmentDuration).After(start) {
+			continue
+		}
+		segSamples, err := s.readSegment(seg)
+		if err != nil {
+			return nil, err
+		}
+		for _, sample := range segSamples {
+			if !sample.Time.Before(start) && sample.Time.Before(stop) {
+				samples = append(samples, sample)
+			}
+		}
+	}
+	return samples, nil
+}
+
+// writeCompacted replaces the segment files with a single compressed file
+func (s *series) writeCompacted(seg *segment, samples []sample, window int64) error {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	for index := range samples {
+		if _, err := writer.Write(encodeSample(&samples[index])); err != nil {
+			return err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	// write into a temporary file and rename it, to avoid partial files on failures
+	filename := compactedFilename(seg.Start, window)
+	tmpFilename := filename + ".tmp"
+	err := utils.WriteFile(s.dir, tmpFilename, buffer.Bytes())
+	if err != nil {
+		return err
+	}
+	err = os.Rename(path.Join(s.dir, tmpFilename), path.Join(s.dir, filename))
+	if err != nil {
+		return err
+	}
+	if seg.Compacted != "" && seg.Compacted != filename {
+		if err = os.Remove(path.Join(s.dir, seg.Compacted)); err != nil {
+			return err
+		}
+	}
+	if seg.HasRaw {
+		if err = os.Remove(path.Join(s.dir, rawFilename(seg.Start))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeSegment deletes all the files of a segment
+func (s *series) removeSegment(seg *segment) error {
+	if seg.Compacted != "" {
+		if err := os.Remove(path.Join(s.dir, seg.Compacted)); err != nil {
+			return err
+		}
+	}
+	if seg.HasRaw {
+		if err := os.Remove(path.Join(s.dir, rawFilename(seg.Start))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func segmentStart(timestamp time.Time, segmentDuration time.Duration) int64 {
+	return timestamp.Truncate(segmentDuration).Unix()
+}
+
+func rawFilename(start int64) string {
+	return fmt.Sprintf("%d%s", start, rawSegmentSuffix)
+}
+
+func compactedFilename(start, window int64) string {
+	return fmt.Sprintf("%d-%d%s", start, window, compactedSuffix)
+}
@@ -2,7 +2,9 @@ package influx
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -76,6 +78,7 @@ type Client struct {
 	logger      *myLogger
 	mutex       *sync.RWMutex
 	ctx         context.Context
+	policies    map[string]metricTY.Policy
 }
 
 // NewClient of influxdb
@@ -117,12 +120,13 @@ func NewClient(config cmap.CustomMap) (metricTY.Plugin, error) {
 	iClient := influxdb2.NewClient(cfg.URI, cfg.Token)
 
 	c := &Client{
-		Config: cfg,
-		Client: iClient,
-		stop:   make(chan bool),
-		mutex:  &sync.RWMutex{},
-		logger: _logger,
-		ctx:    context.TODO(),
+		Config:   cfg,
+		Client:   iClient,
+		stop:     make(chan bool),
+		mutex:    &sync.RWMutex{},
+		logger:   _logger,
+		ctx:      context.TODO(),
+		policies: make(map[string]metricTY.Policy),
 	}
 
 	err = c.Ping()
@@ -207,6 +211,9 @@ func (c *Client) Ping() error {
 		return err
 	}
 	zap.L().Debug("ping status", zap.Bool("isReachable", isReachable))
+	if !isReachable {
+		return errors.New("influxdb server not reachable")
+	}
 	return nil
 }
 
@@ -246,11 +253,25 @@ func (c *Client) Query(queryConfig *metricTY.QueryConfig) (map[string][]metricTY
 			return nil, err
 		}
 
+		// select the target bucket based on the retention policy
+		target := c.getQueryTarget(&query)
+
 		// execute query
-		metrics, err := c.queryClient.ExecuteQuery(&query, measurement)
+		metrics, err := c.queryClient.ExecuteQuery(&query, measurement, target)
 		if err != nil {
 			return metricsMap, err
 		}
+
+		// data written before the policy applied, remains on the default target
+		// a data point written only on one target, hence the results merged
+		if target.Name != "" {
+			defaultQuery := query.Clone()
+			defaultMetrics, err := c.queryClient.ExecuteQuery(&defaultQuery, measurement, extraTY.Target{})
+			if err != nil {
+				return metricsMap, err
+			}
+			metrics = mergeMetrics(defaultMetrics, metrics)
+		}
 		metricsMap[q.Name] = metrics
 	}
 
@@ -266,10 +287,41 @@ func (c *Client) WriteBlocking(data *metricTY.InputData) error {
 	if err != nil {
 		return err
 	}
-	wb := c.Client.WriteAPIBlocking(c.Config.OrganizationName, c.Config.BucketName)
+	wb := c.Client.WriteAPIBlocking(c.Config.OrganizationName, c.getWriteTarget(data.Policy))
 	return wb.WritePoint(ctx, p)
 }
 
+// WriteBatch implementation, writes the points of a target in a single request
+func (c *Client) WriteBatch(data []*metricTY.InputData) error {
+	targets := make([]string, 0)
+	pointsMap := make(map[string][]*write.Point)
+	for _, item := range data {
+		if item.MetricType == metricTY.MetricTypeNone {
+			continue
+		}
+		p, err := c.getPoint(item)
+		if err != nil {
+			return err
+		}
+		target := c.getWriteTarget(item.Policy)
+		if _, found := pointsMap[target]; !found {
+			targets = append(targets, target)
+		}
+		pointsMap[target] = append(pointsMap[target], p)
+	}
+
+	for _, target := range targets {
+		wb := c.Client.WriteAPIBlocking(c.Config.OrganizationName, target)
+		err := wb.WritePoint(ctx, pointsMap[target]...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write implementation, writes in batch asynchronously
+// failures are not reported to the caller, logged by the influxdb client
 func (c *Client) Write(data *metricTY.InputData) error {
 	if data.MetricType == metricTY.MetricTypeNone {
 		return nil
@@ -278,11 +330,132 @@ func (c *Client) Write(data *metricTY.InputData) error {
 	if err != nil {
 		return err
 	}
-	w := c.Client.WriteAPI(c.Config.OrganizationName, c.Config.BucketName)
+	w := c.Client.WriteAPI(c.Config.OrganizationName, c.getWriteTarget(data.Policy))
 	w.WritePoint(p)
 	return nil
 }
 
+// UpdatePolicies creates buckets and downsample tasks for the policies
+func (c *Client) UpdatePolicies(policies []metricTY.Policy) error {
+	for index := range policies {
+		if err := policies[index].Validate(); err != nil {
+			return err
+		}
+	}
+
+	// downsample applied only on the numeric measurements
+	measurements := make([]string, 0)
+	for _, metricType := range []string{metricTY.MetricTypeGauge, metricTY.MetricTypeGaugeFloat, metricTY.MetricTypeCounter} {
+		measurement, err := c.getMeasurementName(metricType)
+		if err != nil {
+			return err
+		}
+		measurements = append(measurements, measurement)
+	}
+
+	err := c.adminClient.ApplyPolicies(policies, measurements)
+	if err != nil {
+		return err
+	}
+
+	updatedPolicies := make(map[string]metricTY.Policy)
+	for _, policy := range policies {
+		updatedPolicies[policy.Name] = policy
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.policies = updatedPolicies
+	return nil
+}
+
+// getWriteTarget returns the bucket of the policy, if policy not available returns the default bucket
+func (c *Client) getWriteTarget(policyName string) string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if _, found := c.policies[policyName]; !found {
+		return c.Config.BucketName
+	}
+	return c.adminClient.WriteTarget(policyName)
+}
+
+// getQueryTarget returns the raw or downsampled data target based on the query start time
+// updates the query window, if it is lower than the downsample window
+func (c *Client) getQueryTarget(query *metricTY.Query) extraTY.Target {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	policy, found := c.policies[query.Policy]
+	if !found {
+		return extraTY.Target{}
+	}
+
+	// downsample applied only on the numeric measurements
+	switch query.MetricType {
+	case metricTY.MetricTypeGauge, metricTY.MetricTypeGaugeFloat, metricTY.MetricTypeCounter:
+	default:
+		return extraTY.Target{Name: c.adminClient.QueryTarget(policy.Name, nil)}
+	}
+
+	age := time.Duration(0)
+	// relative start supports the additional units of the policies, ex: -30d
+	if strings.HasPrefix(query.Start, "-") {
+		if duration, err := metricTY.ParseDuration(strings.TrimPrefix(query.Start, "-")); err == nil {
+			age = duration
+		}
+	} else if startTime, err := time.Parse(time.RFC3339Nano, query.Start); err == nil {
+		age = time.Since(startTime)
+	}
+
+	downsample := policy.GetQueryTier(age)
+	if downsample != nil {
+		window, _ := metricTY.ParseDuration(downsample.Window)
+		queryWindow, err := metricTY.ParseDuration(query.Window)
+		if err != nil || queryWindow < window {
+			query.Window = fmt.Sprintf("%ds", int64(window.Seconds()))
+		}
+	}
+	return extraTY.Target{Name: c.adminClient.QueryTarget(policy.Name, downsample), Downsampled: downsample != nil}
+}
+
+// mergeMetrics merges the results of the default and the policy targets in time order
+// on the same timestamp, the policy target data preferred, if it has values
+func mergeMetrics(defaultMetrics, policyMetrics []metricTY.ResponseData) []metricTY.ResponseData {
+	if len(defaultMetrics) == 0 {
+		return policyMetrics
+	}
+	if len(policyMetrics) == 0 {
+		return defaultMetrics
+	}
+
+	merged := make([]metricTY.ResponseData, 0, len(defaultMetrics)+len(policyMetrics))
+	indexes := make(map[int64]int)
+	for _, items := range [][]metricTY.ResponseData{defaultMetrics, policyMetrics} {
+		for _, item := range items {
+			key := item.Time.UnixNano()
+			if index, found := indexes[key]; found {
+				if hasValues(item.Metric) {
+					merged[index] = item
+				}
+				continue
+			}
+			indexes[key] = len(merged)
+			merged = append(merged, item)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Time.Before(merged[j].Time) })
+	return merged
+}
+
+// hasValues returns true, if any of the aggregated value available
+func hasValues(metric map[string]interface{}) bool {
+	for _, value := range metric {
+		if value != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Client) getPoint(data *metricTY.InputData) (*write.Point, error) {
 	measurementName, err := c.getMeasurementName(data.MetricType)
 	if err != nil {
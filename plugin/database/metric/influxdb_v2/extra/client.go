@@ -13,14 +13,50 @@ const (
 	FieldLatitude  = "latitude"
 	FieldLongitude = "longitude"
 	FieldAltitude  = "altitude"
+
+	// fields of the downsampled data, aggregated from the raw value of the window
+	FieldMean  = "mean"
+	FieldMin   = "min"
+	FieldMax   = "max"
+	FieldSum   = "sum"
+	FieldCount = "count"
 )
 
+// DownsampleFields kept on the downsampled data
+var DownsampleFields = []string{FieldMean, FieldMin, FieldMax, FieldSum, FieldCount}
+
+// DownsampleSource returns the downsampled field and the function to aggregate the query function
+// functions without a matching field, aggregated from the mean
+func DownsampleSource(function string) (string, string) {
+	switch function {
+	case FieldMin, FieldMax, FieldSum:
+		return function, function
+	case FieldCount:
+		return FieldCount, FieldSum
+	}
+	return FieldMean, function
+}
+
+// Target of a query
+// name is a bucket name on v2 and retention policy name on v1, empty name uses the default
+type Target struct {
+	Name        string
+	Downsampled bool // keeps the downsample fields, instead of the raw value
+}
+
 // QueryAPI interface
 type QueryAPI interface {
-	ExecuteQuery(queryConfig *metricTY.Query, measurement string) ([]metricTY.ResponseData, error)
+	ExecuteQuery(queryConfig *metricTY.Query, measurement string, target Target) ([]metricTY.ResponseData, error)
 }
 
 // AdminAPI interface
 type AdminAPI interface {
 	CreateBucket() error
+	// ApplyPolicies creates or updates the retention and downsample setup of the policies
+	// downsample applied only on the supplied measurements
+	ApplyPolicies(policies []metricTY.Policy, measurements []string) error
+	// WriteTarget returns the bucket name to write the raw data of the policy
+	WriteTarget(policyName string) string
+	// QueryTarget returns the target to query, raw data target returned for nil downsample
+	QueryTarget(policyName string, downsample *metricTY.DownsamplePolicy) string
 }
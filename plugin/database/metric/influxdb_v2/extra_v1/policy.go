@@ -0,0 +1,176 @@
+package extrav1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mycontroller-org/server/v2/pkg/json"
+	queryTY "github.com/mycontroller-org/server/v2/plugin/database/metric/influxdb_v2/extra"
+	metricTY "github.com/mycontroller-org/server/v2/plugin/database/metric/types"
+	"go.uber.org/zap"
+)
+
+// WriteTarget returns "database/retention_policy", supported on influxdb 1.8 v2 compatible write api
+func (av1 *AdminV1) WriteTarget(policyName string) string {
+	if policyName == "" {
+		return av1.bucket
+	}
+	return fmt.Sprintf("%s/%s", av1.bucket, policyName)
+}
+
+// QueryTarget returns the retention policy name
+func (av1 *AdminV1) QueryTarget(policyName string, downsample *metricTY.DownsamplePolicy) string {
+	if downsample == nil {
+		return policyName
+	}
+	window, _ := metricTY.ParseDuration(downsample.Window)
+	return fmt.Sprintf("%s_%ds", policyName, int64(window.Seconds()))
+}
+
+// ApplyPolicies creates retention policies and continuous queries to downsample the data
+// retention policies of the removed policies are not deleted, the data should be removed manually
+func (av1 *AdminV1) ApplyPolicies(policies []metricTY.Policy, measurements []string) error {
+	queryNames := make(map[string]bool)
+	for index := range policies {
+		policy := &policies[index]
+		rawRetention, _ := metricTY.ParseDuration(policy.Retention)
+		err := av1.ensureRetentionPolicy(policy.Name, rawRetention)
+		if err != nil {
+			return err
+		}
+
+		for _, ds := range policy.SortedDownsample() {
+			window, _ := metricTY.ParseDuration(ds.Window)
+			retention, _ := metricTY.ParseDuration(ds.Retention)
+			targetRP := av1.QueryTarget(policy.Name, &ds)
+			err = av1.ensureRetentionPolicy(targetRP, retention)
+			if err != nil {
+				return err
+			}
+
+			queryName := fmt.Sprintf("%s%s", av1.queryNamePrefix(), targetRP)
+			queryNames[queryName] = true
+			err = av1.ensureContinuousQuery(queryName, policy.Name, targetRP, window, measurements)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// remove continuous queries of removed policies
+	existingQueries, err := av1.listContinuousQueries()
+	if err != nil {
+		return err
+	}
+	for _, name := range existingQueries {
+		if strings.HasPrefix(name, av1.queryNamePrefix()) && !queryNames[name] {
+			err = av1.execute(fmt.Sprintf(`DROP CONTINUOUS QUERY "%s" ON "%s"`, name, av1.bucket))
+			if err != nil {
+				return err
+			}
+			zap.L().Info("removed metrics continuous query", zap.String("name", name))
+		}
+	}
+	return nil
+}
+
+func (av1 *AdminV1) queryNamePrefix() string {
+	return "mc_downsample_"
+}
+
+// ensureRetentionPolicy creates or alters a retention policy
+func (av1 *AdminV1) ensureRetentionPolicy(name string, retention time.Duration) error {
+	duration := "INF"
+	if retention > 0 {
+		duration = fmt.Sprintf("%ds", int64(retention.Seconds()))
+	}
+	err := av1.execute(fmt.Sprintf(`CREATE RETENTION POLICY "%s" ON "%s" DURATION %s REPLICATION 1`, name, av1.bucket, duration))
+	if err != nil {
+		if !strings.Contains(err.Error(), "already exists") {
+			return err
+		}
+		err = av1.execute(fmt.Sprintf(`ALTER RETENTION POLICY "%s" ON "%s" DURATION %s`, name, av1.bucket, duration))
+		if err != nil {
+			return err
+		}
+	}
+	zap.L().Debug("metrics retention policy updated", zap.String("name", name), zap.String("duration", duration))
+	return nil
+}
+
+// ensureContinuousQuery recreates a continuous query with the latest definition
+func (av1 *AdminV1) ensureContinuousQuery(name, sourceRP, targetRP string, window time.Duration, measurements []string) error {
+	err := av1.execute(fmt.Sprintf(`DROP CONTINUOUS QUERY "%s" ON "%s"`, name, av1.bucket))
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		return err
+	}
+
+	// each aggregate kept as a field, to serve the query functions from the downsampled data
+	aggregates := make([]string, 0)
+	for _, fn := range queryTY.DownsampleFields {
+		aggregates = append(aggregates, fmt.Sprintf(`%[1]s("%[2]s") AS "%[1]s"`, fn, queryTY.FieldValue))
+	}
+	query := fmt.Sprintf(`CREATE CONTINUOUS QUERY "%[1]s" ON "%[2]s" BEGIN SELECT %[3]s INTO "%[2]s"."%[4]s".:MEASUREMENT FROM "%[2]s"."%[5]s"./^(%[6]s)$/ GROUP BY time(%[7]ds), * END`,
+		name, av1.bucket, strings.Join(aggregates, ", "), targetRP, sourceRP, strings.Join(measurements, "|"), int64(window.Seconds()))
+	err = av1.execute(query)
+	if err != nil {
+		return err
+	}
+	zap.L().Info("metrics continuous query created", zap.String("name", name), zap.String("source", sourceRP), zap.String("target", targetRP))
+	return nil
+}
+
+// listContinuousQueries returns the continuous query names of the database
+func (av1 *AdminV1) listContinuousQueries() ([]string, error) {
+	queryResult, err := av1.executeWithResult("SHOW CONTINUOUS QUERIES")
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0)
+	for _, result := range queryResult.Results {
+		for _, series := range result.Series {
+			if series.Name != av1.bucket {
+				continue
+			}
+			for _, values := range series.Values {
+				if len(values) > 0 {
+					if name, ok := values[0].(string); ok {
+						names = append(names, name)
+					}
+				}
+			}
+		}
+	}
+	return names, nil
+}
+
+func (av1 *AdminV1) execute(query string) error {
+	_, err := av1.executeWithResult(query)
+	return err
+}
+
+func (av1 *AdminV1) executeWithResult(query string) (*QueryResult, error) {
+	queryParams := map[string]interface{}{"q": query}
+	response, err := av1.client.ExecuteJson(av1.url, http.MethodPost, av1.headers, queryParams, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	queryResult := &QueryResult{}
+	err = json.Unmarshal(response.Body, queryResult)
+	if err != nil {
+		return nil, err
+	}
+	if queryResult.Error != "" {
+		return nil, errors.New(queryResult.Error)
+	}
+	for _, result := range queryResult.Results {
+		if result.Error != "" {
+			return nil, errors.New(result.Error)
+		}
+	}
+	return queryResult, nil
+}
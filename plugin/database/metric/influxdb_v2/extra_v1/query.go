@@ -12,6 +12,7 @@ import (
 	cloneUtils "github.com/mycontroller-org/server/v2/pkg/utils/clone"
 	converterUtils "github.com/mycontroller-org/server/v2/pkg/utils/convertor"
 	httpclient "github.com/mycontroller-org/server/v2/pkg/utils/http_client_json"
+	queryTY "github.com/mycontroller-org/server/v2/plugin/database/metric/influxdb_v2/extra"
 	metricType "github.com/mycontroller-org/server/v2/plugin/database/metric/types"
 	"go.uber.org/zap"
 )
@@ -41,10 +42,10 @@ func NewQueryClient(uri string, insecure bool, bucket, username, password string
 	}
 }
 
-func (qv1 *QueryV1) ExecuteQuery(query *metricType.Query, measurement string) ([]metricType.ResponseData, error) {
+func (qv1 *QueryV1) ExecuteQuery(query *metricType.Query, measurement string, target queryTY.Target) ([]metricType.ResponseData, error) {
 	queryParams, _ := cloneUtils.Clone(qv1.queryParams).(map[string]interface{})
 
-	queryString := qv1.buildQuery(query, measurement)
+	queryString := qv1.buildQuery(query, measurement, target.Name, target.Downsampled)
 	queryParams["q"] = queryString
 
 	zap.L().Debug("input", zap.String("query", queryString))
@@ -124,26 +125,31 @@ func (qv1 *QueryV1) ExecuteQuery(query *metricType.Query, measurement string) ([
 	return metrics, nil
 }
 
-func (qv1 *QueryV1) buildQuery(query *metricType.Query, measurement string) string {
+func (qv1 *QueryV1) buildQuery(query *metricType.Query, measurement, retentionPolicy string, downsampled bool) string {
 	if len(query.Functions) == 0 {
 		query.Functions = []string{"mean", "min", "max"}
 	}
 
 	// update functions
+	// downsampled data aggregated from the matching aggregate field
 	functions := make([]string, len(query.Functions))
 	for index, fn := range query.Functions {
-		if strings.HasPrefix(fn, "percentile") {
+		field, aggregateFn := queryTY.FieldValue, fn
+		if downsampled {
+			field, aggregateFn = queryTY.DownsampleSource(fn)
+		}
+		if strings.HasPrefix(aggregateFn, "percentile") {
 			p := int64(99)
-			tmp := strings.SplitN(fn, "_", 2)
+			tmp := strings.SplitN(aggregateFn, "_", 2)
 			if len(tmp) == 2 {
 				_p, err := strconv.ParseInt(tmp[1], 10, 64)
 				if err == nil {
 					p = _p
 				}
 			}
-			functions[index] = fmt.Sprintf(`percentile("value", %02d) AS "%s"`, p, fn)
+			functions[index] = fmt.Sprintf(`percentile("%s", %02d) AS "%s"`, field, p, fn)
 		} else {
-			functions[index] = fmt.Sprintf(`%s("value")`, fn)
+			functions[index] = fmt.Sprintf(`%s("%s") AS "%s"`, aggregateFn, field, fn)
 		}
 	}
 
@@ -168,7 +174,11 @@ func (qv1 *QueryV1) buildQuery(query *metricType.Query, measurement string) stri
 
 	}
 
-	fmt.Fprintf(&qBuilder, ` FROM "%s"`, measurement)
+	if retentionPolicy != "" {
+		fmt.Fprintf(&qBuilder, ` FROM "%s"."%s"`, retentionPolicy, measurement)
+	} else {
+		fmt.Fprintf(&qBuilder, ` FROM "%s"`, measurement)
+	}
 
 	index := 0
 	for tag, value := range query.Tags {
@@ -0,0 +1,169 @@
+package extrav2
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/domain"
+	queryTY "github.com/mycontroller-org/server/v2/plugin/database/metric/influxdb_v2/extra"
+	metricTY "github.com/mycontroller-org/server/v2/plugin/database/metric/types"
+	"go.uber.org/zap"
+)
+
+// downsample task template, aggregates the raw data of the last window in to the downsample bucket
+// each aggregate kept as a field, to serve the query functions from the downsampled data
+const downsampleTaskTemplate = `
+data = from(bucket: "%[1]s")
+	|> range(start: -%[2]ds)
+	|> filter(fn: (r) => r["_field"] == "%[3]s")
+	|> filter(fn: (r) => %[4]s)
+	|> toFloat()
+union(tables: [%[7]s])
+	|> to(bucket: "%[5]s", org: "%[6]s")`
+
+// aggregate of the downsample task, stored as a field
+const downsampleAggregateTemplate = `
+	data
+		|> aggregateWindow(every: %[1]ds, fn: %[2]s, createEmpty: false)
+		|> toFloat()
+		|> set(key: "_field", value: "%[2]s")`
+
+// WriteTarget returns the bucket name of the policy
+func (av2 *AdminV2) WriteTarget(policyName string) string {
+	if policyName == "" {
+		return av2.bucketName
+	}
+	return fmt.Sprintf("%s_%s", av2.bucketName, policyName)
+}
+
+// QueryTarget returns the bucket name of the policy or downsample bucket name
+func (av2 *AdminV2) QueryTarget(policyName string, downsample *metricTY.DownsamplePolicy) string {
+	if downsample == nil {
+		return av2.WriteTarget(policyName)
+	}
+	window, _ := metricTY.ParseDuration(downsample.Window)
+	return fmt.Sprintf("%s_%s_%ds", av2.bucketName, policyName, int64(window.Seconds()))
+}
+
+// ApplyPolicies creates the buckets with retention and tasks to downsample the data
+// buckets of the removed policies are not deleted, the data should be removed manually
+func (av2 *AdminV2) ApplyPolicies(policies []metricTY.Policy, measurements []string) error {
+	orgDomain, err := av2.client.OrganizationsAPI().FindOrganizationByName(av2.ctx, av2.organizationName)
+	if err != nil {
+		return err
+	}
+
+	taskNames := make(map[string]bool)
+	for index := range policies {
+		policy := &policies[index]
+		rawRetention, _ := metricTY.ParseDuration(policy.Retention)
+		rawBucket := av2.WriteTarget(policy.Name)
+		err = av2.ensureBucket(orgDomain, rawBucket, rawRetention)
+		if err != nil {
+			return err
+		}
+
+		for _, ds := range policy.SortedDownsample() {
+			window, _ := metricTY.ParseDuration(ds.Window)
+			retention, _ := metricTY.ParseDuration(ds.Retention)
+			targetBucket := av2.QueryTarget(policy.Name, &ds)
+			err = av2.ensureBucket(orgDomain, targetBucket, retention)
+			if err != nil {
+				return err
+			}
+
+			taskName := av2.taskName(policy.Name, window)
+			taskNames[taskName] = true
+			err = av2.ensureTask(orgDomain, taskName, rawBucket, targetBucket, window, measurements)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// remove the tasks of removed policies
+	tasks, err := av2.client.TasksAPI().FindTasks(av2.ctx, &api.TaskFilter{OrgID: *orgDomain.Id, Limit: 500})
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if strings.HasPrefix(task.Name, av2.taskNamePrefix()) && !taskNames[task.Name] {
+			err = av2.client.TasksAPI().DeleteTaskWithID(av2.ctx, task.Id)
+			if err != nil {
+				return err
+			}
+			zap.L().Info("removed metrics downsample task", zap.String("taskName", task.Name))
+		}
+	}
+	return nil
+}
+
+func (av2 *AdminV2) taskNamePrefix() string {
+	return fmt.Sprintf("mc_%s_", av2.bucketName)
+}
+
+func (av2 *AdminV2) taskName(policyName string, window time.Duration) string {
+	return fmt.Sprintf("%s%s_%ds", av2.taskNamePrefix(), policyName, int64(window.Seconds()))
+}
+
+// ensureBucket creates a bucket or updates the retention of the existing bucket
+func (av2 *AdminV2) ensureBucket(orgDomain *domain.Organization, bucketName string, retention time.Duration) error {
+	rule := domain.RetentionRule{EverySeconds: int64(retention.Seconds())}
+	bucketDomain, err := av2.client.BucketsAPI().FindBucketByName(av2.ctx, bucketName)
+	if err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			return err
+		}
+		_, err = av2.client.BucketsAPI().CreateBucketWithName(av2.ctx, orgDomain, bucketName, rule)
+		if err != nil {
+			return err
+		}
+		zap.L().Info("metrics bucket created", zap.String("bucketName", bucketName), zap.String("retention", retention.String()))
+		return nil
+	}
+
+	if len(bucketDomain.RetentionRules) == 1 && bucketDomain.RetentionRules[0].EverySeconds == rule.EverySeconds {
+		return nil
+	}
+	bucketDomain.RetentionRules = domain.RetentionRules{rule}
+	_, err = av2.client.BucketsAPI().UpdateBucket(av2.ctx, bucketDomain)
+	if err != nil {
+		return err
+	}
+	zap.L().Info("metrics bucket retention updated", zap.String("bucketName", bucketName), zap.String("retention", retention.String()))
+	return nil
+}
+
+// ensureTask recreates the downsample task with the latest definition
+func (av2 *AdminV2) ensureTask(orgDomain *domain.Organization, taskName, sourceBucket, targetBucket string, window time.Duration, measurements []string) error {
+	tasks, err := av2.client.TasksAPI().FindTasks(av2.ctx, &api.TaskFilter{OrgID: *orgDomain.Id, Name: taskName})
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		err = av2.client.TasksAPI().DeleteTaskWithID(av2.ctx, task.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	measurementFilters := make([]string, 0)
+	for _, measurement := range measurements {
+		measurementFilters = append(measurementFilters, fmt.Sprintf(`r["_measurement"] == "%s"`, measurement))
+	}
+	aggregates := make([]string, 0)
+	for _, fn := range queryTY.DownsampleFields {
+		aggregates = append(aggregates, fmt.Sprintf(downsampleAggregateTemplate, int64(window.Seconds()), fn))
+	}
+	flux := fmt.Sprintf(downsampleTaskTemplate, sourceBucket, int64(window.Seconds()), queryTY.FieldValue,
+		strings.Join(measurementFilters, " or "), targetBucket, av2.organizationName, strings.Join(aggregates, ","))
+	every := fmt.Sprintf("%ds", int64(window.Seconds()))
+	_, err = av2.client.TasksAPI().CreateTaskWithEvery(av2.ctx, taskName, flux, every, *orgDomain.Id)
+	if err != nil {
+		return err
+	}
+	zap.L().Info("metrics downsample task created", zap.String("taskName", taskName), zap.String("source", sourceBucket), zap.String("target", targetBucket))
+	return nil
+}
@@ -35,6 +35,18 @@ func (qv2 *QueryV2) aggregateWindowFunc(name, window string) (string, string) {
 			|> toFloat()`, name, window)
 }
 
+// aggregateWindowDownsampledFunc aggregates the matching field of the downsampled data
+func (qv2 *QueryV2) aggregateWindowDownsampledFunc(name, window string) (string, string) {
+	field, fn := queryTY.DownsampleSource(name)
+	return name, fmt.Sprintf(`
+		%[1]s = data
+			|> filter(fn: (r) => r["_field"] == "%[3]s")
+			|> drop(columns: ["_field"])
+			|> aggregateWindow(every: %[2]s, fn: %[4]s)
+			|> set(key: "aggregation_type", value: "%[1]s")
+			|> toFloat()`, name, window, field, fn)
+}
+
 func (qv2 *QueryV2) aggregateWindowPercentileFunc(percentile, window string) (string, string) {
 	p := float64(0.99)
 	tmp := strings.SplitN(percentile, "_", 2)
@@ -69,7 +81,7 @@ func (qv2 *QueryV2) union(name string, fns []string) string {
 			|> yield(name: "%s")`, finalData, name)
 }
 
-func (qv2 *QueryV2) buildQuery(suppliedMetricType, name, bucket, start, stop, window string, filters map[string]string, functions []string) string {
+func (qv2 *QueryV2) buildQuery(suppliedMetricType, name, bucket, start, stop, window string, filters map[string]string, functions []string, downsampled bool) string {
 	// add bucket
 	query := fmt.Sprintf(`data = from(bucket: "%s")`, bucket)
 
@@ -100,7 +112,9 @@ func (qv2 *QueryV2) buildQuery(suppliedMetricType, name, bucket, start, stop, wi
 		for _, fn := range functions {
 			fn = strings.ToLower(fn)
 			var fnName, definition string
-			if strings.HasPrefix(fn, "percentile") {
+			if downsampled {
+				fnName, definition = qv2.aggregateWindowDownsampledFunc(fn, window)
+			} else if strings.HasPrefix(fn, "percentile") {
 				fnName, definition = qv2.aggregateWindowPercentileFunc(fn, window)
 			} else {
 				fnName, definition = qv2.aggregateWindowFunc(fn, window)
@@ -124,7 +138,7 @@ func (qv2 *QueryV2) buildQuery(suppliedMetricType, name, bucket, start, stop, wi
 	return query
 }
 
-func (qv2 *QueryV2) ExecuteQuery(q *metricTY.Query, measurement string) ([]metricTY.ResponseData, error) {
+func (qv2 *QueryV2) ExecuteQuery(q *metricTY.Query, measurement string, target queryTY.Target) ([]metricTY.ResponseData, error) {
 	filters := make(map[string]string)
 
 	// add measurement
@@ -135,10 +149,17 @@ func (qv2 *QueryV2) ExecuteQuery(q *metricTY.Query, measurement string) ([]metri
 		filters[k] = v
 	}
 
-	// add field value
-	filters["_field"] = queryTY.FieldValue
+	// add field value, downsampled data filtered by the aggregate fields
+	if !target.Downsampled {
+		filters["_field"] = queryTY.FieldValue
+	}
+
+	bucket := qv2.bucket
+	if target.Name != "" {
+		bucket = target.Name
+	}
 
-	query := qv2.buildQuery(q.MetricType, q.Name, qv2.bucket, q.Start, q.Stop, q.Window, filters, q.Functions)
+	query := qv2.buildQuery(q.MetricType, q.Name, bucket, q.Start, q.Stop, q.Window, filters, q.Functions, target.Downsampled)
 
 	zap.L().Debug("query", zap.String("query", query))
 
@@ -1,6 +1,7 @@
 package metric
 
 import (
+	embedded "github.com/mycontroller-org/server/v2/plugin/database/metric/embedded"
 	influxdbV2 "github.com/mycontroller-org/server/v2/plugin/database/metric/influxdb_v2"
 	voiddb "github.com/mycontroller-org/server/v2/plugin/database/metric/voiddb"
 )
@@ -8,4 +9,5 @@ import (
 func init() {
 	Register(voiddb.PluginVoidDB, voiddb.NewClient)
 	Register(influxdbV2.PluginInfluxdbV2, influxdbV2.NewClient)
+	Register(embedded.PluginEmbedded, embedded.NewClient)
 }
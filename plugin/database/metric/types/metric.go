@@ -11,7 +11,9 @@ type Plugin interface {
 	Ping() error
 	Write(data *InputData) error
 	WriteBlocking(data *InputData) error
+	WriteBatch(data []*InputData) error
 	Query(queryConfig *QueryConfig) (map[string][]ResponseData, error)
+	UpdatePolicies(policies []Policy) error
 }
 
 // Metric types
@@ -50,12 +52,14 @@ type InputData struct {
 	Time       time.Time              `json:"timestamp"`
 	Tags       map[string]string      `json:"tags"`
 	Fields     map[string]interface{} `json:"fields"`
+	Policy     string                 `json:"policy"` // retention policy name, empty: default policy
 }
 
 // QueryConfig parameters
 type QueryConfig struct {
-	Global     Query   `json:"global"`
-	Individual []Query `json:"individual"`
+	Global      Query        `json:"global"`
+	Individual  []Query      `json:"individual"`
+	Expressions []Expression `json:"expressions"`
 }
 
 // Query paramaters
@@ -67,6 +71,17 @@ type Query struct {
 	Window     string            `json:"window"`
 	Tags       map[string]string `json:"tags"`
 	Functions  []string          `json:"functions"`
+	Policy     string            `json:"policy"`
+	Labels     map[string]string `json:"labels"`    // selects all the fields with these labels, evaluated on the metric service
+	Aggregate  string            `json:"aggregate"` // aggregates the label selected fields, options: sum(default), mean, min, max
+}
+
+// Expression evaluated on the metric service, with the results of the individual queries
+// example: "(power_a + power_b) / 1000", "rate(energy, 1h)", "integral(power, 1h) / 1000"
+type Expression struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+	Function   string `json:"function"` // value used from the referenced query results, default: mean
 }
 
 // ResponseData struct
@@ -88,6 +103,10 @@ func (q *Query) Clone() Query {
 	if q.Functions != nil {
 		functions = q.Functions
 	}
+	labels := make(map[string]string)
+	for k, v := range q.Labels {
+		labels[k] = v
+	}
 	return Query{
 		Name:       q.Name,
 		MetricType: q.MetricType,
@@ -96,6 +115,9 @@ func (q *Query) Clone() Query {
 		Window:     q.Window,
 		Tags:       tags,
 		Functions:  functions,
+		Policy:     q.Policy,
+		Labels:     labels,
+		Aggregate:  q.Aggregate,
 	}
 }
 
@@ -109,6 +131,9 @@ func (q *Query) Merge(new *Query) {
 		if q.Functions == nil {
 			q.Functions = []string{}
 		}
+		if q.Labels == nil {
+			q.Labels = make(map[string]string)
+		}
 		// update vales
 		if new.Name != "" {
 			q.Name = new.Name
@@ -125,11 +150,22 @@ func (q *Query) Merge(new *Query) {
 		if new.Window != "" {
 			q.Window = new.Window
 		}
+		if new.Policy != "" {
+			q.Policy = new.Policy
+		}
+		if new.Aggregate != "" {
+			q.Aggregate = new.Aggregate
+		}
 		if len(new.Tags) > 0 {
 			for k, v := range new.Tags {
 				q.Tags[k] = v
 			}
 		}
+		if len(new.Labels) > 0 {
+			for k, v := range new.Labels {
+				q.Labels[k] = v
+			}
+		}
 		if len(new.Functions) > 0 {
 			for _, newFn := range new.Functions {
 				found := false
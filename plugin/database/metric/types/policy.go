@@ -0,0 +1,177 @@
+package metric
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mycontroller-org/server/v2/pkg/types/cmap"
+)
+
+var policyNameRegex = regexp.MustCompile("^[a-z0-9_]+$")
+
+// Policy defines retention and downsampling of the metric data
+// selected by metric types and/or labels, first matching policy will be applied
+// example: keep raw data for 7 days, 5 minutes mean for 1 year
+//
+//	name: gauge_long_term
+//	metricTypes: ["gauge", "gauge_float"]
+//	retention: 7d
+//	downsample:
+//	  - window: 5m
+//	    retention: 1y
+type Policy struct {
+	Name        string               `json:"name" yaml:"name"`
+	MetricTypes []string             `json:"metricTypes" yaml:"metricTypes"`
+	Labels      cmap.CustomStringMap `json:"labels" yaml:"labels"`
+	Retention   string               `json:"retention" yaml:"retention"` // retention of raw data, empty: keep forever
+	Downsample  []DownsamplePolicy   `json:"downsample" yaml:"downsample"`
+}
+
+// DownsamplePolicy keeps aggregated (mean, min, max, sum and count) data with the window resolution
+type DownsamplePolicy struct {
+	Window    string `json:"window" yaml:"window"`
+	Retention string `json:"retention" yaml:"retention"`
+}
+
+// Matches returns true if the metric type and labels selected by this policy
+func (p *Policy) Matches(metricType string, labels cmap.CustomStringMap) bool {
+	if len(p.MetricTypes) > 0 {
+		found := false
+		for _, mt := range p.MetricTypes {
+			if mt == metricType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for key, value := range p.Labels {
+		if labels.Get(key) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate verifies the policy
+func (p *Policy) Validate() error {
+	if !policyNameRegex.MatchString(p.Name) {
+		return fmt.Errorf("invalid policy name:'%s', allowed characters: [a-z0-9_]", p.Name)
+	}
+	rawRetention, err := ParseDuration(p.Retention)
+	if err != nil {
+		return fmt.Errorf("invalid retention on policy:%s, error:%s", p.Name, err.Error())
+	}
+	if len(p.Downsample) > 0 && rawRetention == 0 {
+		return fmt.Errorf("raw data retention required on policy:%s, when downsample defined", p.Name)
+	}
+	previousRetention := rawRetention
+	previousWindow := time.Duration(0)
+	for _, ds := range p.SortedDownsample() {
+		window, err := ParseDuration(ds.Window)
+		if err != nil {
+			return fmt.Errorf("invalid downsample window on policy:%s, error:%s", p.Name, err.Error())
+		}
+		if window < time.Second {
+			return fmt.Errorf("minimum supported downsample window is 1s, policy:%s", p.Name)
+		}
+		if window <= previousWindow {
+			return fmt.Errorf("downsample window should grow with retention, policy:%s, window:%s", p.Name, ds.Window)
+		}
+		retention, err := ParseDuration(ds.Retention)
+		if err != nil {
+			return fmt.Errorf("invalid downsample retention on policy:%s, error:%s", p.Name, err.Error())
+		}
+		if retention <= previousRetention {
+			return fmt.Errorf("downsample retention should be greater than previous retention, policy:%s, retention:%s", p.Name, ds.Retention)
+		}
+		previousRetention = retention
+		previousWindow = window
+	}
+	return nil
+}
+
+// SortedDownsample returns the downsample policies sorted by retention
+func (p *Policy) SortedDownsample() []DownsamplePolicy {
+	items := make([]DownsamplePolicy, len(p.Downsample))
+	copy(items, p.Downsample)
+	sort.SliceStable(items, func(i, j int) bool {
+		a, _ := ParseDuration(items[i].Retention)
+		b, _ := ParseDuration(items[j].Retention)
+		return a < b
+	})
+	return items
+}
+
+// TotalRetention returns the retention of the oldest data on this policy, 0 = forever
+func (p *Policy) TotalRetention() time.Duration {
+	retention, _ := ParseDuration(p.Retention)
+	for _, ds := range p.Downsample {
+		dsRetention, _ := ParseDuration(ds.Retention)
+		if dsRetention > retention {
+			retention = dsRetention
+		}
+	}
+	return retention
+}
+
+// GetQueryTier returns the downsample policy holds the data for the given age
+// returns nil, if raw data covers the age
+func (p *Policy) GetQueryTier(age time.Duration) *DownsamplePolicy {
+	rawRetention, _ := ParseDuration(p.Retention)
+	if rawRetention == 0 || age <= rawRetention {
+		return nil
+	}
+	tiers := p.SortedDownsample()
+	for index := range tiers {
+		retention, _ := ParseDuration(tiers[index].Retention)
+		if age <= retention {
+			return &tiers[index]
+		}
+	}
+	if len(tiers) > 0 {
+		return &tiers[len(tiers)-1]
+	}
+	return nil
+}
+
+// ParseDuration parses the golang duration with additional units d(day), w(week) and y(year)
+// empty string returns zero duration
+func ParseDuration(duration string) (time.Duration, error) {
+	duration = strings.TrimSpace(duration)
+	if duration == "" {
+		return 0, nil
+	}
+	units := map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+		"y": 365 * 24 * time.Hour,
+	}
+	for suffix, unit := range units {
+		if strings.HasSuffix(duration, suffix) {
+			value, err := strconv.ParseInt(strings.TrimSuffix(duration, suffix), 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration:%s", duration)
+			}
+			if value < 0 {
+				return 0, errors.New("duration should not be negative")
+			}
+			return time.Duration(value) * unit, nil
+		}
+	}
+	parsed, err := time.ParseDuration(duration)
+	if err != nil {
+		return 0, err
+	}
+	if parsed < 0 {
+		return 0, errors.New("duration should not be negative")
+	}
+	return parsed, nil
+}
@@ -34,7 +34,13 @@ func (c *Client) Write(data *metricTY.InputData) error { return nil }
 // WriteBlocking function
 func (c *Client) WriteBlocking(data *metricTY.InputData) error { return nil }
 
+// WriteBatch function
+func (c *Client) WriteBatch(data []*metricTY.InputData) error { return nil }
+
 // Query function
 func (c *Client) Query(queryConfig *metricTY.QueryConfig) (map[string][]metricTY.ResponseData, error) {
 	return nil, nil
 }
+
+// UpdatePolicies function
+func (c *Client) UpdatePolicies(policies []metricTY.Policy) error { return nil }
@@ -5,6 +5,7 @@ import (
 	generic "github.com/mycontroller-org/server/v2/plugin/gateway/provider/generic"
 	mysensorsV2 "github.com/mycontroller-org/server/v2/plugin/gateway/provider/mysensors_v2"
 	philipsHue "github.com/mycontroller-org/server/v2/plugin/gateway/provider/philipshue"
+	presence "github.com/mycontroller-org/server/v2/plugin/gateway/provider/presence"
 	systemMonitoring "github.com/mycontroller-org/server/v2/plugin/gateway/provider/system_monitoring"
 	"github.com/mycontroller-org/server/v2/plugin/gateway/provider/tasmota"
 )
@@ -14,6 +15,7 @@ func init() {
 	Register(generic.PluginGeneric, generic.NewPluginGeneric)
 	Register(mysensorsV2.PluginMySensorsV2, mysensorsV2.NewPluginMySensorsV2)
 	Register(philipsHue.PluginPhilipsHue, philipsHue.NewPluginPhilipsHue)
+	Register(presence.PluginPresence, presence.NewPluginPresence)
 	Register(systemMonitoring.PluginSystemMonitoring, systemMonitoring.NewPluginSystemMonitoring)
 	Register(tasmota.PluginTasmota, tasmota.NewPluginTasmota)
 }
@@ -0,0 +1,73 @@
+package presence
+
+import "github.com/mycontroller-org/server/v2/pkg/types/cmap"
+
+// source types
+const (
+	SourceTypePing      = "ping"      // reachability of a network device, icmp ping or tcp connect
+	SourceTypeARP       = "arp"       // mac address on the arp table
+	SourceTypeOwnTracks = "owntracks" // owntracks location and transition messages over mqtt
+	SourceTypeBLE       = "ble"       // updates of a field, reported by a beacon scanner node
+	SourceTypeWebhook   = "webhook"   // state set via action api, ex: phone automation
+)
+
+// combine logic of the sources
+const (
+	CombineAny      = "any"      // home, if any of the source detects
+	CombineAll      = "all"      // home, if all the sources detect
+	CombineMajority = "majority" // home, if more than half of the sources detect
+)
+
+// person states
+const (
+	StateHome = "home"
+	StateAway = "away"
+)
+
+// field ids of a person
+const (
+	FieldPresent = "present"
+	FieldState   = "state"
+)
+
+const (
+	defaultNodeID          = "presence"
+	defaultCheckInterval   = "30s"
+	defaultTimeout         = "5m"
+	defaultOwnTracksRegion = "home"
+	defaultProbeTimeout    = "2s"
+)
+
+// Config of presence provider
+type Config struct {
+	Type          string                  `yaml:"type"`
+	Protocol      cmap.CustomMap          `yaml:"protocol"`      // optional mqtt protocol, receives owntracks messages
+	NodeID        string                  `yaml:"nodeId"`        // default: presence
+	CheckInterval string                  `yaml:"checkInterval"` // probe interval of ping and arp sources
+	ProbeTimeout  string                  `yaml:"probeTimeout"`  // timeout of a ping or tcp connect
+	Persons       map[string]PersonConfig `yaml:"persons"`       // person id => config
+}
+
+// PersonConfig of a person, presence derived from the sources
+type PersonConfig struct {
+	Name     string                  `yaml:"name"`
+	Disabled bool                    `yaml:"disabled"`
+	Combine  string                  `yaml:"combine"` // any, all or majority
+	Timeout  string                  `yaml:"timeout"` // default timeout of the sources
+	Sources  map[string]SourceConfig `yaml:"sources"` // source id => config
+}
+
+// SourceConfig of a presence source
+// a source marked as away, if it is not seen within the timeout
+type SourceConfig struct {
+	Type       string `yaml:"type"`
+	Disabled   bool   `yaml:"disabled"`
+	Timeout    string `yaml:"timeout"`
+	Host       string `yaml:"host"`       // ping: ip address or hostname
+	Port       int    `yaml:"port"`       // ping: tcp port, tcp connect used instead of icmp ping
+	MacAddress string `yaml:"macAddress"` // arp: mac address of the device
+	Topic      string `yaml:"topic"`      // owntracks: device topic, ex: owntracks/alice/phone
+	Region     string `yaml:"region"`     // owntracks: region name of the home
+	FieldID    string `yaml:"fieldId"`    // ble: quick id of the field, ex: field:gw1.scanner.beacons.alice_tag
+	MinRSSI    int    `yaml:"minRssi"`    // ble: seen only if the value is greater than or equal, ignored if zero
+}
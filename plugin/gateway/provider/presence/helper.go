@@ -0,0 +1,72 @@
+package presence
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mycontroller-org/server/v2/pkg/service/mcbus"
+	"github.com/mycontroller-org/server/v2/pkg/types"
+	msgTY "github.com/mycontroller-org/server/v2/pkg/types/message"
+	"go.uber.org/zap"
+)
+
+func (p *Provider) getData(name string, value interface{}, metricType string, isReadOnly bool) msgTY.Payload {
+	data := msgTY.NewPayload()
+	data.Key = name
+	data.SetValue(fmt.Sprintf("%v", value))
+	data.MetricType = metricType
+	if isReadOnly {
+		data.Labels.Set(types.LabelReadOnly, "true")
+	}
+	return data
+}
+
+func (p *Provider) getMsg(sourceID string) msgTY.Message {
+	msg := msgTY.NewMessage(true)
+	msg.GatewayID = p.GatewayConfig.ID
+	msg.NodeID = p.NodeID
+	msg.SourceID = sourceID
+	msg.Type = msgTY.TypeSet
+	msg.Timestamp = time.Now()
+	return msg
+}
+
+func (p *Provider) getPresentationMsg(sourceID, name string) msgTY.Message {
+	msg := msgTY.NewMessage(true)
+	msg.GatewayID = p.GatewayConfig.ID
+	msg.NodeID = p.NodeID
+	msg.SourceID = sourceID
+	msg.Type = msgTY.TypePresentation
+	msg.Timestamp = time.Now()
+
+	if name != "" {
+		data := msgTY.NewPayload()
+		data.Key = "name"
+		data.SetValue(name)
+		msg.Payloads = append(msg.Payloads, data)
+	}
+	return msg
+}
+
+func (p *Provider) postMsg(msg *msgTY.Message) {
+	topic := mcbus.GetTopicPostMessageToProcessor()
+	err := mcbus.Publish(topic, msg)
+	if err != nil {
+		zap.L().Error("error on posting msg", zap.String("gatewayId", p.GatewayConfig.ID), zap.Error(err))
+	}
+}
+
+// presents the node and the persons
+func (p *Provider) postPresentation() {
+	nodeMsg := p.getPresentationMsg("", "Presence")
+	p.postMsg(&nodeMsg)
+
+	for id, ps := range p.persons {
+		name := ps.config.Name
+		if name == "" {
+			name = id
+		}
+		msg := p.getPresentationMsg(id, name)
+		p.postMsg(&msg)
+	}
+}
@@ -0,0 +1,273 @@
+package presence
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mycontroller-org/server/v2/pkg/types"
+	msgTY "github.com/mycontroller-org/server/v2/pkg/types/message"
+	"github.com/mycontroller-org/server/v2/pkg/utils"
+	quickIdUtils "github.com/mycontroller-org/server/v2/pkg/utils/quick_id"
+	gwPtl "github.com/mycontroller-org/server/v2/plugin/gateway/protocol"
+	mqtt "github.com/mycontroller-org/server/v2/plugin/gateway/protocol/protocol_mqtt"
+	providerTY "github.com/mycontroller-org/server/v2/plugin/gateway/provider/type"
+	gwTY "github.com/mycontroller-org/server/v2/plugin/gateway/types"
+	"go.uber.org/zap"
+)
+
+const (
+	PluginPresence = "presence"
+
+	schedulePrefix = "schedule_presence_gw_"
+)
+
+// Provider derives home/away state of the persons from multiple sources
+type Provider struct {
+	Config         Config
+	GatewayConfig  *gwTY.Config
+	Protocol       gwPtl.Protocol
+	NodeID         string
+	checkInterval  string
+	probeTimeout   time.Duration
+	persons        map[string]*personState
+	mutex          sync.Mutex
+	fieldTopic     string
+	subscriptionID int64
+}
+
+// NewPluginPresence provider
+func NewPluginPresence(gatewayCfg *gwTY.Config) (providerTY.Plugin, error) {
+	cfg := Config{}
+	err := utils.MapToStruct(utils.TagNameYaml, gatewayCfg.Provider, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &Provider{
+		Config:         cfg,
+		GatewayConfig:  gatewayCfg,
+		NodeID:         cfg.NodeID,
+		checkInterval:  utils.ValidDuration(cfg.CheckInterval, defaultCheckInterval),
+		probeTimeout:   utils.ToDuration(utils.ValidDuration(cfg.ProbeTimeout, defaultProbeTimeout), 0),
+		persons:        make(map[string]*personState),
+		subscriptionID: -1,
+	}
+	if provider.NodeID == "" {
+		provider.NodeID = defaultNodeID
+	}
+
+	for personID, personCfg := range cfg.Persons {
+		if personCfg.Disabled {
+			continue
+		}
+		ps, err := provider.getPersonState(personID, personCfg)
+		if err != nil {
+			return nil, err
+		}
+		provider.persons[personID] = ps
+	}
+	zap.L().Debug("Config details", zap.Any("received", gatewayCfg.Provider), zap.Any("converted", cfg))
+	return provider, nil
+}
+
+// verifies the person config and returns the initial state
+func (p *Provider) getPersonState(personID string, cfg PersonConfig) (*personState, error) {
+	switch cfg.Combine {
+	case "":
+		cfg.Combine = CombineAny
+	case CombineAny, CombineAll, CombineMajority:
+	default:
+		return nil, fmt.Errorf("person '%s': invalid combine '%s', supported: %s, %s, %s", personID, cfg.Combine, CombineAny, CombineAll, CombineMajority)
+	}
+
+	personTimeout, err := time.ParseDuration(utils.ValidDuration(cfg.Timeout, defaultTimeout))
+	if err != nil {
+		return nil, err
+	}
+
+	ps := &personState{
+		id:            personID,
+		config:        cfg,
+		sources:       make(map[string]*sourceState),
+		sourcePresent: make(map[string]bool),
+	}
+	for sourceID, sourceCfg := range cfg.Sources {
+		if sourceCfg.Disabled {
+			continue
+		}
+		if sourceID == FieldPresent || sourceID == FieldState {
+			return nil, fmt.Errorf("person '%s': source id '%s' is reserved", personID, sourceID)
+		}
+
+		// event sources keep the last reported state, if there is no timeout
+		timeout := personTimeout
+		if sourceCfg.Type == SourceTypeOwnTracks || sourceCfg.Type == SourceTypeWebhook {
+			timeout = 0
+		}
+		if sourceCfg.Timeout != "" {
+			timeout, err = time.ParseDuration(sourceCfg.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("person '%s', source '%s': invalid timeout, %s", personID, sourceID, err.Error())
+			}
+		}
+
+		err = p.verifySource(sourceCfg)
+		if err != nil {
+			return nil, fmt.Errorf("person '%s', source '%s': %s", personID, sourceID, err.Error())
+		}
+		ps.sources[sourceID] = &sourceState{id: sourceID, config: sourceCfg, timeout: timeout}
+	}
+	return ps, nil
+}
+
+func (p *Provider) verifySource(cfg SourceConfig) error {
+	switch cfg.Type {
+	case SourceTypePing:
+		if cfg.Host == "" {
+			return errors.New("host can not be empty")
+		}
+
+	case SourceTypeARP:
+		if cfg.MacAddress == "" {
+			return errors.New("macAddress can not be empty")
+		}
+
+	case SourceTypeOwnTracks:
+		if cfg.Topic == "" {
+			return errors.New("topic can not be empty")
+		}
+		if p.Config.Protocol.GetString(types.NameType) != gwPtl.TypeMQTT {
+			return errors.New("owntracks source requires mqtt protocol")
+		}
+
+	case SourceTypeBLE:
+		resourceType, _, err := quickIdUtils.EntityKeyValueMap(cfg.FieldID)
+		if err != nil {
+			return err
+		}
+		if resourceType != types.EntityField {
+			return fmt.Errorf("field quick id expected, received '%s'", cfg.FieldID)
+		}
+
+	case SourceTypeWebhook:
+
+	default:
+		return fmt.Errorf("unsupported type '%s'", cfg.Type)
+	}
+	return nil
+}
+
+func (p *Provider) Name() string {
+	return PluginPresence
+}
+
+// Start func
+func (p *Provider) Start(rxMessageFunc func(rawMsg *msgTY.RawMessage) error) error {
+	protocolType := p.Config.Protocol.GetString(types.NameType)
+	switch protocolType {
+	case "":
+		// mqtt is optional, required only for owntracks
+
+	case gwPtl.TypeMQTT:
+		protocol, err := mqtt.New(p.GatewayConfig, p.Config.Protocol, rxMessageFunc)
+		if err != nil {
+			return err
+		}
+		p.Protocol = protocol
+
+	default:
+		return fmt.Errorf("protocol not implemented: %s", protocolType)
+	}
+
+	err := p.startFieldListener()
+	if err != nil {
+		_ = p.closeProtocol()
+		return err
+	}
+
+	p.postPresentation()
+	p.loadState()
+	p.schedule("check", p.checkInterval, p.check)
+	go p.check()
+
+	return nil
+}
+
+// Close func
+func (p *Provider) Close() error {
+	p.unloadAll()
+	p.stopFieldListener()
+	return p.closeProtocol()
+}
+
+func (p *Provider) closeProtocol() error {
+	if p.Protocol != nil {
+		return p.Protocol.Close()
+	}
+	return nil
+}
+
+// Post receives the webhook source updates
+// webhook source accepts "home", "true", "1" and "on" as home, other values as away
+func (p *Provider) Post(msg *msgTY.Message) error {
+	if len(msg.Payloads) == 0 {
+		return errors.New("there is no payload details on the message")
+	}
+
+	if p.NodeID != msg.NodeID {
+		return nil
+	}
+
+	if msg.Type != msgTY.TypeSet {
+		return nil
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	ps, found := p.persons[msg.SourceID]
+	if !found {
+		return fmt.Errorf("person '%s' not available", msg.SourceID)
+	}
+
+	now := time.Now()
+	for _, payload := range msg.Payloads {
+		source, found := ps.sources[payload.Key]
+		if !found || source.config.Type != SourceTypeWebhook {
+			return fmt.Errorf("person '%s': webhook source '%s' not available", ps.id, payload.Key)
+		}
+		if isHome(string(payload.Value)) {
+			source.setSeen(now)
+		} else {
+			source.setAway()
+		}
+	}
+
+	if resultMsg := p.evaluate(ps, now); resultMsg != nil {
+		p.postMsg(resultMsg)
+	}
+	return nil
+}
+
+// check probes the network sources and posts the changes
+func (p *Provider) check() {
+	p.probeNetworkSources()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+	for _, ps := range p.persons {
+		if msg := p.evaluate(ps, now); msg != nil {
+			p.postMsg(msg)
+		}
+	}
+}
+
+func isHome(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	return value == StateHome || value == "true" || value == "1" || value == "on"
+}
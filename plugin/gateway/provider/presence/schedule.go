@@ -0,0 +1,26 @@
+package presence
+
+import (
+	"fmt"
+
+	scheduleUtils "github.com/mycontroller-org/server/v2/pkg/utils/schedule"
+	"go.uber.org/zap"
+)
+
+func (p *Provider) getScheduleID(resourceID string) string {
+	return fmt.Sprintf("%s_%s_%s", schedulePrefix, p.GatewayConfig.ID, resourceID)
+}
+
+func (p *Provider) unloadAll() {
+	scheduleUtils.UnscheduleAll(schedulePrefix, p.GatewayConfig.ID)
+}
+
+func (p *Provider) schedule(resourceID, interval string, triggerFunc func()) {
+	scheduleID := p.getScheduleID(resourceID)
+	scheduleUtils.Unschedule(scheduleID) // removes the existing schedule, if any
+	jobSpec := fmt.Sprintf("@every %s", interval)
+	err := scheduleUtils.Schedule(scheduleID, jobSpec, triggerFunc)
+	if err != nil {
+		zap.L().Error("error on adding schedule", zap.String("gatewayId", p.GatewayConfig.ID), zap.Error(err))
+	}
+}
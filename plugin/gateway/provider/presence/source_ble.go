@@ -0,0 +1,101 @@
+package presence
+
+import (
+	"time"
+
+	"github.com/mycontroller-org/server/v2/pkg/service/mcbus"
+	"github.com/mycontroller-org/server/v2/pkg/types"
+	busTY "github.com/mycontroller-org/server/v2/pkg/types/bus"
+	eventTY "github.com/mycontroller-org/server/v2/pkg/types/bus/event"
+	fieldTY "github.com/mycontroller-org/server/v2/pkg/types/field"
+	"github.com/mycontroller-org/server/v2/pkg/utils/convertor"
+	quickIdUtils "github.com/mycontroller-org/server/v2/pkg/utils/quick_id"
+	"go.uber.org/zap"
+)
+
+// startFieldListener listens the field events, if there is a ble source
+func (p *Provider) startFieldListener() error {
+	hasBLE := false
+	for _, ps := range p.persons {
+		for _, source := range ps.sources {
+			if source.config.Type == SourceTypeBLE {
+				hasBLE = true
+			}
+		}
+	}
+	if !hasBLE {
+		return nil
+	}
+
+	p.fieldTopic = mcbus.FormatTopic(mcbus.TopicEventField)
+	sID, err := mcbus.Subscribe(p.fieldTopic, p.onFieldEvent)
+	if err != nil {
+		return err
+	}
+	p.subscriptionID = sID
+	return nil
+}
+
+func (p *Provider) stopFieldListener() {
+	if p.subscriptionID == -1 {
+		return
+	}
+	err := mcbus.Unsubscribe(p.fieldTopic, p.subscriptionID)
+	if err != nil {
+		zap.L().Error("error on unsubscription", zap.String("gatewayId", p.GatewayConfig.ID), zap.String("topic", p.fieldTopic), zap.Error(err))
+	}
+	p.subscriptionID = -1
+}
+
+// onFieldEvent updates the ble sources of the field
+// with minRssi, the field value should be greater than or equal to minRssi
+func (p *Provider) onFieldEvent(busData *busTY.BusData) {
+	event := &eventTY.Event{}
+	err := busData.LoadData(event)
+	if err != nil {
+		zap.L().Warn("error on convert to target type", zap.Any("topic", busData.Topic), zap.Error(err))
+		return
+	}
+	if event.EntityType != types.EntityField || event.Type != eventTY.TypeUpdated || event.Entity == nil {
+		return
+	}
+
+	field := fieldTY.Field{}
+	err = event.LoadEntity(&field)
+	if err != nil {
+		zap.L().Warn("error on conversion", zap.Any("entity", event), zap.Error(err))
+		return
+	}
+	// ignore own fields
+	if field.GatewayID == p.GatewayConfig.ID {
+		return
+	}
+	quickID, err := quickIdUtils.GetQuickID(field)
+	if err != nil {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+	for _, ps := range p.persons {
+		updated := false
+		for _, source := range ps.sources {
+			cfg := source.config
+			if cfg.Type != SourceTypeBLE || cfg.FieldID != quickID {
+				continue
+			}
+			if cfg.MinRSSI != 0 && convertor.ToFloat(field.Current.Value) < float64(cfg.MinRSSI) {
+				continue
+			}
+			source.setSeen(now)
+			updated = true
+		}
+		if updated {
+			if msg := p.evaluate(ps, now); msg != nil {
+				p.postMsg(msg)
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package presence
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const arpTableFile = "/proc/net/arp"
+
+// probeNetworkSources updates the ping and arp sources
+func (p *Provider) probeNetworkSources() {
+	p.mutex.Lock()
+	sources := make([]*sourceState, 0)
+	hasARP := false
+	for _, ps := range p.persons {
+		for _, source := range ps.sources {
+			switch source.config.Type {
+			case SourceTypePing:
+				sources = append(sources, source)
+			case SourceTypeARP:
+				sources = append(sources, source)
+				hasARP = true
+			}
+		}
+	}
+	p.mutex.Unlock()
+
+	if len(sources) == 0 {
+		return
+	}
+
+	arpTable := map[string]bool{}
+	if hasARP {
+		table, err := readARPTable()
+		if err != nil {
+			zap.L().Error("error on reading arp table", zap.String("gatewayId", p.GatewayConfig.ID), zap.Error(err))
+		} else {
+			arpTable = table
+		}
+	}
+
+	// probes in parallel, a host may not respond till the timeout
+	seen := make([]bool, len(sources))
+	wg := sync.WaitGroup{}
+	for index, source := range sources {
+		if source.config.Type == SourceTypeARP {
+			seen[index] = arpTable[normalizeMAC(source.config.MacAddress)]
+			continue
+		}
+		wg.Add(1)
+		go func(index int, cfg SourceConfig) {
+			defer wg.Done()
+			seen[index] = p.ping(cfg)
+		}(index, source.config)
+	}
+	wg.Wait()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	now := time.Now()
+	for index, source := range sources {
+		if seen[index] {
+			source.setSeen(now)
+		}
+	}
+}
+
+// ping uses tcp connect, if the port supplied, otherwise icmp ping command
+func (p *Provider) ping(cfg SourceConfig) bool {
+	if cfg.Port > 0 {
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort(cfg.Host, fmt.Sprintf("%d", cfg.Port)), p.probeTimeout)
+		if err != nil {
+			return false
+		}
+		_ = conn.Close()
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.probeTimeout)
+	defer cancel()
+	err := exec.CommandContext(ctx, "ping", "-c", "1", cfg.Host).Run()
+	return err == nil
+}
+
+// readARPTable returns the complete entries of the arp table
+func readARPTable() (map[string]bool, error) {
+	file, err := os.Open(arpTableFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// format: IP address, HW type, Flags, HW address, Mask, Device
+	table := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	scanner.Scan() // skip the header
+	for scanner.Scan() {
+		columns := strings.Fields(scanner.Text())
+		if len(columns) < 4 || columns[2] == "0x0" { // incomplete entry
+			continue
+		}
+		table[normalizeMAC(columns[3])] = true
+	}
+	return table, scanner.Err()
+}
+
+func normalizeMAC(mac string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(mac)), "-", ":")
+}
@@ -0,0 +1,96 @@
+package presence
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mycontroller-org/server/v2/pkg/json"
+	msgTY "github.com/mycontroller-org/server/v2/pkg/types/message"
+	"github.com/mycontroller-org/server/v2/pkg/utils"
+	"github.com/mycontroller-org/server/v2/pkg/utils/convertor"
+	gwPtl "github.com/mycontroller-org/server/v2/plugin/gateway/protocol"
+	"go.uber.org/zap"
+)
+
+// owntracks message types
+const (
+	ownTracksTypeLocation   = "location"
+	ownTracksTypeTransition = "transition"
+
+	ownTracksEventEnter = "enter"
+	ownTracksEventLeave = "leave"
+)
+
+// ownTracksMessage, fields used to derive the presence
+type ownTracksMessage struct {
+	Type      string   `json:"_type"`
+	InRegions []string `json:"inregions"` // location: current regions
+	Event     string   `json:"event"`     // transition: enter or leave
+	Desc      string   `json:"desc"`      // transition: region name
+}
+
+// ConvertToMessages updates the owntracks sources, returns the changed person states
+// transitions are received on "<device topic>/event"
+func (p *Provider) ConvertToMessages(rawMsg *msgTY.RawMessage) ([]*msgTY.Message, error) {
+	topic := convertor.ToString(rawMsg.Others.Get(gwPtl.KeyMqttTopic))
+
+	data, ok := rawMsg.Data.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("error on converting to bytes. received: %T", rawMsg.Data)
+	}
+
+	otMsg := ownTracksMessage{}
+	err := json.Unmarshal(data, &otMsg)
+	if err != nil {
+		zap.L().Debug("ignored a non owntracks message", zap.String("gatewayId", p.GatewayConfig.ID), zap.String("topic", topic), zap.Error(err))
+		return nil, nil
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+	messages := make([]*msgTY.Message, 0)
+	for _, ps := range p.persons {
+		updated := false
+		for _, source := range ps.sources {
+			cfg := source.config
+			if cfg.Type != SourceTypeOwnTracks || (topic != cfg.Topic && topic != cfg.Topic+"/event") {
+				continue
+			}
+			region := cfg.Region
+			if region == "" {
+				region = defaultOwnTracksRegion
+			}
+
+			switch otMsg.Type {
+			case ownTracksTypeLocation:
+				if utils.ContainsString(otMsg.InRegions, region) {
+					source.setSeen(now)
+				} else {
+					source.setAway()
+				}
+				updated = true
+
+			case ownTracksTypeTransition:
+				if otMsg.Desc != region {
+					continue
+				}
+				switch otMsg.Event {
+				case ownTracksEventEnter:
+					source.setSeen(now)
+					updated = true
+				case ownTracksEventLeave:
+					source.setAway()
+					updated = true
+				}
+			}
+		}
+		if updated {
+			if msg := p.evaluate(ps, now); msg != nil {
+				messages = append(messages, msg)
+			}
+		}
+	}
+	return messages, nil
+}
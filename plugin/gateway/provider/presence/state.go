@@ -0,0 +1,166 @@
+package presence
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mycontroller-org/server/v2/pkg/types"
+	fieldTY "github.com/mycontroller-org/server/v2/pkg/types/field"
+	msgTY "github.com/mycontroller-org/server/v2/pkg/types/message"
+	rsTY "github.com/mycontroller-org/server/v2/pkg/types/resource_service"
+	queryUtils "github.com/mycontroller-org/server/v2/pkg/utils/bus_utils/query"
+	"github.com/mycontroller-org/server/v2/pkg/utils/convertor"
+	metricTY "github.com/mycontroller-org/server/v2/plugin/database/metric/types"
+	"go.uber.org/zap"
+)
+
+const queryTimeout = 5 * time.Second
+
+// sourceState of a presence source
+type sourceState struct {
+	id       string
+	config   SourceConfig
+	timeout  time.Duration // zero, keeps the last reported state
+	lastSeen time.Time
+	away     bool // reported as away explicitly
+}
+
+func (s *sourceState) setSeen(at time.Time) {
+	s.lastSeen = at
+	s.away = false
+}
+
+func (s *sourceState) setAway() {
+	s.away = true
+}
+
+func (s *sourceState) isPresent(now time.Time) bool {
+	if s.away || s.lastSeen.IsZero() {
+		return false
+	}
+	return s.timeout == 0 || now.Sub(s.lastSeen) <= s.timeout
+}
+
+// personState keeps the sources and the last posted values of a person
+type personState struct {
+	id            string
+	config        PersonConfig
+	sources       map[string]*sourceState
+	isPosted      bool
+	present       bool
+	sourcePresent map[string]bool
+}
+
+// isPresent combines the sources
+func (ps *personState) isPresent(now time.Time) bool {
+	presentCount := 0
+	for _, source := range ps.sources {
+		if source.isPresent(now) {
+			presentCount++
+		}
+	}
+
+	switch ps.config.Combine {
+	case CombineAll:
+		return len(ps.sources) > 0 && presentCount == len(ps.sources)
+
+	case CombineMajority:
+		return presentCount*2 > len(ps.sources)
+
+	default:
+		return presentCount > 0
+	}
+}
+
+// loadState seeds the person and the source states from the persisted field values
+// a source reported as present, considered as seen now and expires with its timeout
+// on failure, the first evaluation posts the complete state
+func (p *Provider) loadState() {
+	fields := make([]fieldTY.Field, 0)
+	updateFields := func(item interface{}) bool {
+		items, ok := item.(*[]fieldTY.Field)
+		if !ok {
+			zap.L().Error("error on data conversion", zap.String("receivedType", fmt.Sprintf("%T", item)))
+			return false
+		}
+		fields = *items
+		return false
+	}
+	filter := map[string]interface{}{types.KeyGatewayID: p.GatewayConfig.ID, types.KeyNodeID: p.NodeID}
+	out := make([]fieldTY.Field, 0)
+	err := queryUtils.QueryResource("", rsTY.TypeField, rsTY.CommandList, filter, updateFields, &out, queryTimeout)
+	if err != nil {
+		zap.L().Warn("error on loading the persisted state", zap.String("gatewayId", p.GatewayConfig.ID), zap.Error(err))
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+	for index := range fields {
+		field := fields[index]
+		ps, found := p.persons[field.SourceID]
+		if !found || field.Current.Value == nil {
+			continue
+		}
+		isPresent := convertor.ToBool(field.Current.Value)
+		if field.FieldID == FieldPresent {
+			ps.isPosted = true
+			ps.present = isPresent
+			continue
+		}
+		source, found := ps.sources[field.FieldID]
+		if !found {
+			continue
+		}
+		ps.sourcePresent[source.id] = isPresent
+		if isPresent {
+			source.setSeen(now)
+		} else {
+			source.setAway()
+		}
+	}
+}
+
+// evaluate returns a message with the changed person and source states, nil if there is no change
+// on the first evaluation, all the states included
+func (p *Provider) evaluate(ps *personState, now time.Time) *msgTY.Message {
+	present := ps.isPresent(now)
+	isPresentChanged := !ps.isPosted || present != ps.present
+
+	sourcePresent := make(map[string]bool)
+	changedIDs := make([]string, 0)
+	for id, source := range ps.sources {
+		sourcePresent[id] = source.isPresent(now)
+		previous, found := ps.sourcePresent[id]
+		if !ps.isPosted || !found || sourcePresent[id] != previous {
+			changedIDs = append(changedIDs, id)
+		}
+	}
+	if !isPresentChanged && len(changedIDs) == 0 {
+		return nil
+	}
+
+	ps.isPosted = true
+	ps.present = present
+	ps.sourcePresent = sourcePresent
+
+	msg := p.getMsg(ps.id)
+	if isPresentChanged {
+		state := StateAway
+		if present {
+			state = StateHome
+		}
+		msg.Payloads = append(msg.Payloads, p.getData(FieldPresent, present, metricTY.MetricTypeBinary, true))
+		msg.Payloads = append(msg.Payloads, p.getData(FieldState, state, metricTY.MetricTypeNone, true))
+	}
+	sort.Strings(changedIDs)
+	for _, id := range changedIDs {
+		// webhook sources updated via action api
+		isReadOnly := ps.sources[id].config.Type != SourceTypeWebhook
+		msg.Payloads = append(msg.Payloads, p.getData(id, sourcePresent[id], metricTY.MetricTypeBinary, isReadOnly))
+	}
+	return &msg
+}
@@ -26,6 +26,7 @@ type Config struct {
 	State              *types.State         `json:"state" yaml:"state"`
 	ModifiedOn         time.Time            `json:"modifiedOn" yaml:"modifiedOn"`
 	LastTransaction    time.Time            `json:"lastTransaction" yaml:"lastTransaction"`
+	ClientCertificates []string             `json:"clientCertificates" yaml:"clientCertificates"` // required on the plugin http api, sha256 fingerprint or common name
 }
 
 // GetReconnectDelay for this config
@@ -53,8 +53,10 @@ func (hdr *Config) Clone() Config {
 // MessageWrapper to use in bus
 // specially used to send data to handlers
 type MessageWrapper struct {
-	ID   string
-	Data map[string]interface{}
+	ID          string
+	ExecutionID string // task or schedule execution id, handler result is recorded on the task history
+	ReplyTopic  string // handler result posted on this topic, if supplied
+	Data        map[string]interface{}
 }
 
 // GenericData struct
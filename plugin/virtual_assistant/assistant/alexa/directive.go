@@ -24,6 +24,9 @@ func executiveDirective(directive alexaTY.DirectiveOrEvent) *alexaTY.Response {
 	case alexaTY.NamespaceBrightnessController:
 		return executeDirectiveBrightnessController(directive.Endpoint.EndpointID, directive.Header.Name, directive.Payload)
 
+	case alexaTY.NamespaceSceneController:
+		return executeDirectiveSceneController(directive.Endpoint.EndpointID, directive.Header.Name)
+
 	default:
 		zap.L().Warn("namespace not implemented", zap.String("namespace", directive.Header.Namespace), zap.String("name", directive.Header.Name))
 	}
@@ -58,6 +61,55 @@ func executeDirectiveBrightnessController(endpointID, directive string, payload
 	return getErrorResponse(endpointID, alexaTY.ErrorTypeInvalidDirective, fmt.Sprintf("%s directive not supported for %s", directive, alexaTY.NamespaceBrightnessController))
 }
 
+// SceneController, activate restores the scene
+func executeDirectiveSceneController(endpointID, directive string) *alexaTY.Response {
+	if directive != alexaTY.DirectiveActivate {
+		return getErrorResponse(endpointID, alexaTY.ErrorTypeInvalidDirective, fmt.Sprintf("%s directive not supported for %s", directive, alexaTY.NamespaceSceneController))
+	}
+
+	vDevice, err := vdAPI.GetByID(endpointID)
+	if err != nil {
+		zap.L().Error("error on getting virtual device", zap.String("endpointId", endpointID), zap.Error(err))
+		return getErrorResponse(endpointID, alexaTY.ErrorTypeNoSuchEndpoint, "there is no virtual device with this id")
+	}
+
+	resource, found := vDevice.Traits[vdTY.DeviceTraitScene]
+	if !found || resource.Type != vdTY.ResourceByQuickID {
+		zap.L().Error("scene trait not configured with quickId", zap.String("deviceId", vDevice.ID), zap.String("deviceName", vDevice.Name))
+		return getErrorResponse(endpointID, alexaTY.ErrorTypeNoSuchEndpoint, "scene trait not configured for this directive")
+	}
+
+	err = actionAPI.ExecuteActionOnResourceByQuickID(&handlerType.ResourceData{
+		ResourceType: resource.ResourceType,
+		QuickID:      fmt.Sprintf("%s:%s", resource.ResourceType, resource.QuickID),
+		Payload:      "true",
+		PreDelay:     "0s",
+	})
+	if err != nil {
+		zap.L().Error("error on activating a scene", zap.String("deviceId", vDevice.ID), zap.String("deviceName", vDevice.Name), zap.Error(err))
+		return getErrorResponse(vDevice.ID, alexaTY.ErrorTypeInternalError, "error on activating the scene")
+	}
+
+	return &alexaTY.Response{
+		Event: alexaTY.DirectiveOrEvent{
+			Header: alexaTY.Header{
+				Namespace:      alexaTY.NamespaceSceneController,
+				Name:           alexaTY.NameActivationStarted,
+				MessageID:      utils.RandUUID(),
+				PayloadVersion: "3",
+			},
+			Endpoint: &alexaTY.DirectiveEndpoint{
+				EndpointID: vDevice.ID,
+			},
+			Payload: map[string]interface{}{
+				"cause":     map[string]string{"type": alexaTY.CauseTypeVoiceInteraction},
+				"timestamp": time.Now().UTC().Format(time.RFC3339),
+			},
+		},
+		Context: &alexaTY.Context{Properties: []alexaTY.Property{}},
+	}
+}
+
 func executeResourceAction(endpointID, namespace, name string, trait string, payload interface{}) *alexaTY.Response {
 
 	vDevice, err := vdAPI.GetByID(endpointID)
@@ -22,6 +22,17 @@ func executeDiscover(directive alexaTY.DirectiveOrEvent) (interface{}, error) {
 		capabilities := make([]alexaTY.Capability, 0)
 		for trait := range vDevice.Traits {
 			if aInterface, found := alexaTY.TraitControllerMap[trait]; found {
+				// scene controller has no properties, deactivation not supported
+				if aInterface == alexaTY.NamespaceSceneController {
+					supportsDeactivation := false
+					capabilities = append(capabilities, alexaTY.Capability{
+						Type:                 "AlexaInterface",
+						Interface:            aInterface,
+						Version:              "3",
+						SupportsDeactivation: &supportsDeactivation,
+					})
+					continue
+				}
 				properties := alexaTY.GetInterfaceProperties(aInterface)
 				capabilities = append(capabilities, alexaTY.Capability{
 					Type:       "AlexaInterface",
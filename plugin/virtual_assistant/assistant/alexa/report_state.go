@@ -42,6 +42,10 @@ func reportState(directive alexaTY.DirectiveOrEvent) (interface{}, error) {
 			zap.L().Warn("trait not implemented", zap.String("deviceId", vDevice.ID), zap.String("deviceName", vDevice.Name), zap.String("trait", trait))
 			continue
 		}
+		// scene controller has no state
+		if aInterface == alexaTY.NamespaceSceneController {
+			continue
+		}
 		// get property name
 		propertyName, found := alexaTY.InterfacePropertyNameMap[aInterface]
 		if !found {
@@ -38,6 +38,7 @@ type Capability struct {
 	Semantics               *Semantics               `json:"semantics,omitempty"`
 	VerificationsRequired   []VerificationsRequired  `json:"verificationsRequired,omitempty"`
 	DirectiveConfigurations []DirectiveConfiguration `json:"directiveConfigurations,omitempty"`
+	SupportsDeactivation    *bool                    `json:"supportsDeactivation,omitempty"` // scene controller
 }
 
 // https://developer.amazon.com/en-US/docs/alexa/device-apis/alexa-discovery-objects.html#directiveconfigurations-object-details
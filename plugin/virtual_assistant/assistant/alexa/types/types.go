@@ -12,8 +12,10 @@ const (
 	NamespaceBrightnessController = "Alexa.BrightnessController"
 	NamespaceColorController      = "Alexa.ColorController"
 	NamespacePercentageController = "Alexa.PercentageController"
+	NamespaceSceneController      = "Alexa.SceneController"
 
-	NameDiscoverResponse = "Discover.Response"
+	NameDiscoverResponse  = "Discover.Response"
+	NameActivationStarted = "ActivationStarted"
 
 	PropertyNamePowerState      = "powerState"
 	PropertyNameBrightness      = "brightness"
@@ -24,6 +26,9 @@ const (
 	DirectiveTurnOn        = "TurnOn"
 	DirectiveTurnOff       = "TurnOff"
 	DirectiveSetBrightness = "SetBrightness"
+	DirectiveActivate      = "Activate"
+
+	CauseTypeVoiceInteraction = "VOICE_INTERACTION"
 )
 
 var (
@@ -31,6 +36,7 @@ var (
 		vdTY.DeviceTraitOnOff:        NamespacePowerController,
 		vdTY.DeviceTraitBrightness:   NamespaceBrightnessController,
 		vdTY.DeviceTraitColorSetting: NamespaceColorController,
+		vdTY.DeviceTraitScene:        NamespaceSceneController,
 	}
 
 	InterfacePropertyNameMap = map[string]string{
@@ -115,6 +121,7 @@ var (
 		vdTY.DeviceTypeOutlet:         "SMARTPLUG",
 		vdTY.DeviceTypeAirConditioner: "AIR_CONDITIONER",
 		vdTY.DeviceTypeAirCooler:      "AIR_CONDITIONER",
+		vdTY.DeviceTypeScene:          "SCENE_TRIGGER",
 	}
 )
 
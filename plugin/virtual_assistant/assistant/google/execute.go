@@ -64,10 +64,15 @@ func executeCommand(device gaTY.ExecuteRequestDevice, executions []gaTY.ExecuteR
 				continue
 			}
 			if resource.Type == vdTY.ResourceByQuickID {
+				payload := converterUtil.ToString(val)
+				// scene trait sends "deactivate", activation restores the scene
+				if trait == vdTY.DeviceTraitScene {
+					payload = converterUtil.ToString(!converterUtil.ToBool(val))
+				}
 				err = actionAPI.ExecuteActionOnResourceByQuickID(&handlerType.ResourceData{
 					ResourceType: resource.ResourceType,
 					QuickID:      fmt.Sprintf("%s:%s", resource.ResourceType, resource.QuickID),
-					Payload:      converterUtil.ToString(val),
+					Payload:      payload,
 					PreDelay:     "0s",
 				})
 				statusString := gaTY.ExecutionStatusSuccess
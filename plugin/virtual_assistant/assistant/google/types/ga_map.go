@@ -133,6 +133,7 @@ var (
 	CommandParamsMap = map[string]string{
 		"on":         vdTY.DeviceTraitOnOff,
 		"brightness": vdTY.DeviceTraitBrightness,
+		"deactivate": vdTY.DeviceTraitScene,
 	}
 
 	IgnoreParamsList = []string{